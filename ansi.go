@@ -0,0 +1,147 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ANSIColors are the colors of the 16 standard and bright terminal colors.
+var ANSIColors = [16]color.NRGBA{
+	{0, 0, 0, 255}, {205, 0, 0, 255}, {0, 205, 0, 255}, {205, 205, 0, 255},
+	{0, 0, 238, 255}, {205, 0, 205, 255}, {0, 205, 205, 255}, {229, 229, 229, 255},
+	{127, 127, 127, 255}, {255, 0, 0, 255}, {0, 255, 0, 255}, {255, 255, 0, 255},
+	{92, 92, 255, 255}, {255, 0, 255, 255}, {0, 255, 255, 255}, {255, 255, 255, 255},
+}
+
+// ParseANSI extracts the styles of text holding ANSI escape sequences.  The SGR
+// sequences for bold, italic, reset and foreground colors (standard, bright, 256
+// color and 24 bit) are turned into style runs.  Every other escape sequence,
+// including background colors, is removed.
+func ParseANSI(s string) Markup {
+	var m Markup
+	var text []rune
+	var style FontStyle
+	var fg color.Color
+	start := 0
+	flush := func() {
+		if len(text) > start && (style != StyleRegular || fg != nil) {
+			m.Runs = append(m.Runs, StyleRun{Start: start, End: len(text), Style: style, Color: fg})
+		}
+		start = len(text)
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\x1b' {
+			text = append(text, runes[i])
+			continue
+		}
+		if i+1 >= len(runes) || runes[i+1] != '[' {
+			// other escapes are two runes long
+			i++
+			continue
+		}
+		// control sequences end with a rune from @ to ~
+		end := i + 2
+		for end < len(runes) && (runes[end] < '@' || runes[end] > '~') {
+			end++
+		}
+		if end == len(runes) {
+			break
+		}
+		if runes[end] == 'm' {
+			flush()
+			style, fg = applySGR(string(runes[i+2:end]), style, fg)
+		}
+		i = end
+	}
+	flush()
+	m.Text = string(text)
+	return m
+}
+
+// applySGR updates the style and color with the parameters of a select graphic rendition sequence
+func applySGR(params string, style FontStyle, fg color.Color) (FontStyle, color.Color) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil && codes[i] != "" {
+			continue
+		}
+		switch {
+		case code == 0:
+			style, fg = StyleRegular, nil
+		case code == 1:
+			style |= StyleBold
+		case code == 3:
+			style |= StyleItalic
+		case code == 22:
+			style &^= StyleBold
+		case code == 23:
+			style &^= StyleItalic
+		case code >= 30 && code <= 37:
+			fg = ANSIColors[code-30]
+		case code >= 90 && code <= 97:
+			fg = ANSIColors[code-90+8]
+		case code == 39:
+			fg = nil
+		case code == 38 || code == 48:
+			// extended colors consume their arguments, backgrounds are ignored
+			var c color.Color
+			c, i = extendedColor(codes, i+1)
+			if code == 38 && c != nil {
+				fg = c
+			}
+		}
+	}
+	return style, fg
+}
+
+// extendedColor reads a 256 color (5;n) or 24 bit color (2;r;g;b) starting at codes[i]
+// and returns the index of its last parameter
+func extendedColor(codes []string, i int) (color.Color, int) {
+	arg := func(j int) int {
+		if j >= len(codes) {
+			return -1
+		}
+		v, err := strconv.Atoi(codes[j])
+		if err != nil || v < 0 || v > 255 {
+			return -1
+		}
+		return v
+	}
+	switch arg(i) {
+	case 5:
+		n := arg(i + 1)
+		if n < 0 {
+			return nil, i + 1
+		}
+		return ansi256(n), i + 1
+	case 2:
+		r, g, b := arg(i+1), arg(i+2), arg(i+3)
+		if r < 0 || g < 0 || b < 0 {
+			return nil, i + 3
+		}
+		return color.NRGBA{uint8(r), uint8(g), uint8(b), 255}, i + 3
+	}
+	return nil, i
+}
+
+// ansi256 returns color n of the xterm palette
+func ansi256(n int) color.NRGBA {
+	switch {
+	case n < 16:
+		return ANSIColors[n]
+	case n < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return color.NRGBA{levels[n/36], levels[n/6%6], levels[n%6], 255}
+	}
+	gray := uint8(8 + 10*(n-232))
+	return color.NRGBA{gray, gray, gray, 255}
+}
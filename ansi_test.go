@@ -0,0 +1,25 @@
+package gltext
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseANSI(t *testing.T) {
+	m := ParseANSI("\x1b[1;31mred\x1b[22m dim\x1b[0m plain \x1b[38;5;196mx\x1b[38;2;1;2;3my\x1b[39;3mz\x1b[2K\x1b[m")
+	if m.Text != "red dim plain xyz" {
+		t.Fatalf("Bad text %q", m.Text)
+	}
+	if m.Runs.StyleAt(0) != StyleBold || m.Runs.ColorAt(0) != ANSIColors[1] {
+		t.Error("Bad bold red", m.Runs)
+	}
+	if m.Runs.StyleAt(4) != StyleRegular || m.Runs.ColorAt(4) != ANSIColors[1] || m.Runs.ColorAt(8) != nil {
+		t.Error("Bad reset", m.Runs)
+	}
+	if m.Runs.ColorAt(14) != (color.NRGBA{255, 0, 0, 255}) || m.Runs.ColorAt(15) != (color.NRGBA{1, 2, 3, 255}) {
+		t.Error("Bad extended colors", m.Runs.ColorAt(14), m.Runs.ColorAt(15))
+	}
+	if m.Runs.ColorAt(16) != nil || m.Runs.StyleAt(16) != StyleItalic {
+		t.Error("Bad default color", m.Runs)
+	}
+}
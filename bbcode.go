@@ -0,0 +1,125 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseBBCode extracts the styles and links of text marked up with the [b], [i],
+// [color=#RRGGBB], [size=1.5] (or [size=150%]) and [url] or [url=address] tags.
+// Tags may be nested.  Unknown or malformed tags are kept as text and tags left
+// open are closed at the end of the text.
+func ParseBBCode(s string) Markup {
+	type openTag struct {
+		name, value string
+		start       int
+	}
+	var m Markup
+	var text []rune
+	var open []openTag
+	closeTag := func(tag openTag) {
+		end := len(text)
+		switch tag.name {
+		case "b":
+			m.Runs = append(m.Runs, StyleRun{Start: tag.start, End: end, Style: StyleBold})
+		case "i":
+			m.Runs = append(m.Runs, StyleRun{Start: tag.start, End: end, Style: StyleItalic})
+		case "color":
+			c, _ := ParseColor(tag.value)
+			m.Runs = append(m.Runs, StyleRun{Start: tag.start, End: end, Color: c})
+		case "size":
+			size, _ := parseSize(tag.value)
+			m.Runs = append(m.Runs, StyleRun{Start: tag.start, End: end, Size: size})
+		case "url":
+			url := tag.value
+			if url == "" {
+				url = string(text[tag.start:])
+			}
+			m.Links = append(m.Links, Link{Start: tag.start, End: end, URL: url})
+		}
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '[' {
+			text = append(text, runes[i])
+			continue
+		}
+		length := 1
+		for i+length < len(runes) && runes[i+length] != ']' {
+			length++
+		}
+		if i+length == len(runes) {
+			text = append(text, runes[i:]...)
+			break
+		}
+		tag := string(runes[i+1 : i+length])
+		if strings.HasPrefix(tag, "/") {
+			name := strings.ToLower(tag[1:])
+			found := -1
+			for j := len(open) - 1; j >= 0; j-- {
+				if open[j].name == name {
+					found = j
+					break
+				}
+			}
+			if found < 0 {
+				text = append(text, runes[i])
+				continue
+			}
+			closeTag(open[found])
+			open = append(open[:found], open[found+1:]...)
+			i += length
+			continue
+		}
+		name, value := tag, ""
+		if at := strings.IndexByte(tag, '='); at >= 0 {
+			name, value = tag[:at], strings.Trim(tag[at+1:], `"'`)
+		}
+		name = strings.ToLower(name)
+		if !validBBCodeTag(name, value) {
+			text = append(text, runes[i])
+			continue
+		}
+		open = append(open, openTag{name: name, value: value, start: len(text)})
+		i += length
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		closeTag(open[j])
+	}
+	m.Text = string(text)
+	return m
+}
+
+func validBBCodeTag(name, value string) bool {
+	switch name {
+	case "b", "i":
+		return value == ""
+	case "color":
+		_, err := ParseColor(value)
+		return err == nil
+	case "size":
+		_, err := parseSize(value)
+		return err == nil
+	case "url":
+		return true
+	}
+	return false
+}
+
+// parseSize reads a scale such as 1.5 or 150%
+func parseSize(s string) (float32, error) {
+	scale := float64(1)
+	if strings.HasSuffix(s, "%") {
+		s, scale = s[:len(s)-1], 0.01
+	}
+	v, err := strconv.ParseFloat(s, 32)
+	if err == nil && v <= 0 {
+		err = strconv.ErrRange
+	}
+	return float32(v * scale), err
+}
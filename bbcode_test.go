@@ -0,0 +1,55 @@
+package gltext
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseBBCode(t *testing.T) {
+	m := ParseBBCode("[b]Bold [i]both[/i][/b] [color=#f00]red[/color] [size=150%]big[/size] [url=http://x.org]site[/url] [url]http://y.org[/url]")
+	if m.Text != "Bold both red big site http://y.org" {
+		t.Fatalf("Bad text %q", m.Text)
+	}
+	if m.Runs.StyleAt(0) != StyleBold || m.Runs.StyleAt(5) != StyleBoldItalic || m.Runs.StyleAt(10) != StyleRegular {
+		t.Error("Bad styles", m.Runs)
+	}
+	if m.Runs.ColorAt(10) != (color.NRGBA{R: 255, A: 255}) || m.Runs.ColorAt(14) != nil {
+		t.Error("Bad colors", m.Runs.ColorAt(10), m.Runs.ColorAt(14))
+	}
+	if m.Runs.SizeAt(14) != 1.5 || m.Runs.SizeAt(10) != 1 {
+		t.Error("Bad sizes", m.Runs.SizeAt(14))
+	}
+	if len(m.Links) != 2 || m.Links[0] != (Link{Start: 18, End: 22, URL: "http://x.org"}) || m.Links[1].URL != "http://y.org" {
+		t.Error("Bad links", m.Links)
+	}
+}
+
+func TestParseBBCodeMalformed(t *testing.T) {
+	for s, expect := range map[string]string{
+		"[x]a[/x]":           "[x]a[/x]",
+		"a [/b] b":           "a [/b] b",
+		"[color=nope]c":      "[color=nope]c",
+		"[b]unclosed":        "unclosed",
+		"brackets [ and ] [": "brackets [ and ] [",
+	} {
+		if m := ParseBBCode(s); m.Text != expect {
+			t.Errorf("Expecting %q got %q", expect, m.Text)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	for s, expect := range map[string]color.NRGBA{
+		"#fff":     {255, 255, 255, 255},
+		"#102030":  {16, 32, 48, 255},
+		"10203040": {16, 32, 48, 64},
+		"Orange":   {255, 165, 0, 255},
+	} {
+		if c, err := ParseColor(s); err != nil || c != expect {
+			t.Error("Bad color", s, c, err)
+		}
+	}
+	if _, err := ParseColor("#12"); err == nil {
+		t.Error("Expecting an error")
+	}
+}
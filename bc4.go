@@ -0,0 +1,94 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"image"
+)
+
+// AlphaPixels returns the alpha channel of img, one byte per pixel, row by row
+func AlphaPixels(img *image.NRGBA) []byte {
+	b := img.Bounds()
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):]
+		for x := 0; x < b.Dx(); x++ {
+			alpha = append(alpha, row[4*x+3])
+		}
+	}
+	return alpha
+}
+
+// EncodeBC4 compresses a single channel image of width by height bytes into BC4 (RGTC1)
+// blocks, 8 bytes per 4x4 texels.  Blocks overlapping the right or bottom edge repeat the
+// last column or row.
+func EncodeBC4(pix []byte, width, height int) []byte {
+	blocksX, blocksY := (width+3)/4, (height+3)/4
+	out := make([]byte, 0, blocksX*blocksY*8)
+	var block [16]byte
+	for by := 0; by < blocksY; by++ {
+		for bx := 0; bx < blocksX; bx++ {
+			for i := range block {
+				x, y := bx*4+i%4, by*4+i/4
+				if x >= width {
+					x = width - 1
+				}
+				if y >= height {
+					y = height - 1
+				}
+				block[i] = pix[y*width+x]
+			}
+			out = append(out, encodeBC4Block(block)...)
+		}
+	}
+	return out
+}
+
+// encodeBC4Block uses the largest and smallest texels as endpoints, the eight value mode,
+// and picks the closest of the values for every texel
+func encodeBC4Block(block [16]byte) []byte {
+	hi, lo := block[0], block[0]
+	for _, v := range block {
+		if v > hi {
+			hi = v
+		}
+		if v < lo {
+			lo = v
+		}
+	}
+	encoded := make([]byte, 8)
+	encoded[0], encoded[1] = hi, lo
+	if hi == lo {
+		return encoded
+	}
+	values := bc4Values(hi, lo)
+	bits := uint64(0)
+	for i, v := range block {
+		best, distance := 0, 256
+		for j, candidate := range values {
+			d := int(v) - int(candidate)
+			if d < 0 {
+				d = -d
+			}
+			if d < distance {
+				best, distance = j, d
+			}
+		}
+		bits |= uint64(best) << uint(3*i)
+	}
+	for i := 0; i < 6; i++ {
+		encoded[2+i] = byte(bits >> uint(8*i))
+	}
+	return encoded
+}
+
+// bc4Values returns the eight values of a block whose first endpoint is the larger
+func bc4Values(r0, r1 byte) [8]byte {
+	values := [8]byte{r0, r1}
+	for i := 2; i < 8; i++ {
+		values[i] = byte(((8-i)*int(r0) + (i-1)*int(r1) + 3) / 7)
+	}
+	return values
+}
@@ -0,0 +1,57 @@
+package gltext
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// decodeBC4 expands blocks encoded by EncodeBC4, which only uses the eight value mode
+func decodeBC4(blocks []byte, width, height int) []byte {
+	pix := make([]byte, width*height)
+	blocksX := (width + 3) / 4
+	for b := 0; b*8 < len(blocks); b++ {
+		block := blocks[b*8 : b*8+8]
+		values := bc4Values(block[0], block[1])
+		bits := uint64(0)
+		for i := 0; i < 6; i++ {
+			bits |= uint64(block[2+i]) << uint(8*i)
+		}
+		for i := 0; i < 16; i++ {
+			x, y := (b%blocksX)*4+i%4, (b/blocksX)*4+i/4
+			if x < width && y < height {
+				pix[y*width+x] = values[(bits>>uint(3*i))&7]
+			}
+		}
+	}
+	return pix
+}
+
+func TestEncodeBC4(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 6, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 6; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: uint8(x * 50)})
+		}
+	}
+	alpha := AlphaPixels(img)
+	if len(alpha) != 30 || alpha[7] != 50 {
+		t.Fatal("Bad alpha", alpha)
+	}
+
+	blocks := EncodeBC4(alpha, 6, 5)
+	if len(blocks) != 2*2*8 {
+		t.Fatal("Bad block count", len(blocks)/8)
+	}
+	for i, v := range decodeBC4(blocks, 6, 5) {
+		d := int(v) - int(alpha[i])
+		if d < -10 || d > 10 {
+			t.Fatal("Bad texel", i, v, alpha[i])
+		}
+	}
+
+	flat := EncodeBC4(make([]byte, 16), 4, 4)
+	if len(flat) != 8 || flat[0] != 0 || flat[1] != 0 {
+		t.Error("Bad flat block", flat)
+	}
+}
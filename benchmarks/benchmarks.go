@@ -0,0 +1,159 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package benchmarks measures the cost of laying out, uploading and drawing text across
+// font sizes and string lengths.  Layout is measured headless; uploads and draws are
+// measured through the Text interface, which the texts of every GL package implement,
+// so that the same cases can be run against each of them with a context current.
+//
+// The functions are used by the Benchmark functions of this package and by
+// cmd/gltext-bench, which prints a table of results.
+package benchmarks
+
+import (
+	"fmt"
+	"github.com/4ydx/gltext/layout"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Case is a font size and string length being measured.
+type Case struct {
+	Size   int // font scale in points
+	Length int // runes in the string
+}
+
+func (c Case) String() string {
+	return fmt.Sprintf("size=%d/length=%d", c.Size, c.Length)
+}
+
+// Result is the cost of one operation for a case.
+type Result struct {
+	Name string
+	Case
+
+	NsPerOp     int64
+	AllocsPerOp int64
+	BytesPerOp  int64
+
+	// DrawCalls per operation when the operation reports them, see Draw.
+	DrawCalls int
+}
+
+// Text is a text of one of the GL packages, EG *v41.Text.
+type Text interface {
+	SetString(fs string, argv ...interface{})
+	Draw()
+}
+
+// words are the source of Sample, a mix of short and long words
+var words = strings.Fields("the quick brown fox jumps over a lazy dog while seven wizards " +
+	"quietly pack boxes of liquor jugs and sphinxes judge vows")
+
+// Sample returns a string of n runes made of words, with a newline roughly every sixty
+// runes.  The same string is returned for the same n so that runs can be compared.
+func Sample(n int) string {
+	b := make([]rune, 0, n)
+	line := 0
+	for i := 0; len(b) < n; i++ {
+		word := words[i%len(words)]
+		for _, r := range word {
+			b = append(b, r)
+			line++
+		}
+		switch {
+		case line >= 60:
+			b = append(b, '\n')
+			line = 0
+		default:
+			b = append(b, ' ')
+			line++
+		}
+	}
+	return string(b[:n])
+}
+
+// Measure runs op with testing.Benchmark, reporting the allocations it makes.
+func Measure(name string, c Case, op func()) Result {
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			op()
+		}
+	})
+	return Result{
+		Name:        name,
+		Case:        c,
+		NsPerOp:     r.NsPerOp(),
+		AllocsPerOp: r.AllocsPerOp(),
+		BytesPerOp:  r.AllocedBytesPerOp(),
+	}
+}
+
+// Layout measures laying out a sample of c.Length runes with face.  face should have been
+// generated at c.Size, gltext.FontConfig implements layout.Face.
+func Layout(face layout.Face, c Case, opts layout.Options) Result {
+	text := []rune(Sample(c.Length))
+	return Measure("layout", c, func() {
+		layout.Layout(face, text, opts)
+	})
+}
+
+// SetString measures laying out a sample of c.Length runes and uploading its buffers to t.
+// Two samples differing by their first rune are alternated so that no call can be skipped
+// as unchanged.
+func SetString(t Text, c Case) Result {
+	sample := Sample(c.Length)
+	samples := [2]string{sample, sample}
+	if c.Length > 0 {
+		samples[1] = "T" + sample[1:]
+	}
+	i := 0
+	return Measure("setstring", c, func() {
+		t.SetString("%s", samples[i%2])
+		i++
+	})
+}
+
+// Draw measures drawing t once its string has been set to a sample of c.Length runes.
+// finish, usually gl.Finish, is called after every draw so that the time the gpu spends
+// is counted, not only the calls queued.  drawCalls, when not nil, returns the draw calls
+// made so far, EG from the Stats of the font, and the calls per draw are reported.
+func Draw(t Text, c Case, finish func(), drawCalls func() int) Result {
+	t.SetString("%s", Sample(c.Length))
+	before := 0
+	if drawCalls != nil {
+		before = drawCalls()
+	}
+	draws := 0
+	r := Measure("draw", c, func() {
+		t.Draw()
+		if finish != nil {
+			finish()
+		}
+		draws++
+	})
+	if drawCalls != nil && draws > 0 {
+		r.DrawCalls = (drawCalls() - before) / draws
+	}
+	return r
+}
+
+// Format writes results as a table, one line per result.
+func Format(w io.Writer, results []Result) error {
+	if _, err := fmt.Fprintf(w, "%-10s %6s %8s %14s %10s %10s %6s\n",
+		"op", "size", "length", "time/op", "allocs/op", "bytes/op", "calls"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		_, err := fmt.Fprintf(w, "%-10s %6d %8d %14s %10d %10d %6d\n",
+			r.Name, r.Size, r.Length, time.Duration(r.NsPerOp), r.AllocsPerOp, r.BytesPerOp, r.DrawCalls)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package benchmarks
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
+	"golang.org/x/image/math/fixed"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var (
+	sizes   = []int{12, 32, 64}
+	lengths = []int{16, 256, 4096}
+)
+
+func loadConfig(tb testing.TB, size int) *gltext.FontConfig {
+	fd, err := os.Open("../font/font_1_honokamin.ttf")
+	if err != nil {
+		tb.Skip(err)
+	}
+	defer fd.Close()
+	runeRanges := gltext.RuneRanges{{Low: 32, High: 127}}
+	config, err := gltext.NewTruetypeFontConfig(fd, fixed.Int26_6(size), runeRanges, 16, 5)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return config
+}
+
+func BenchmarkLayout(b *testing.B) {
+	for _, size := range sizes {
+		config := loadConfig(b, size)
+		opts := layout.Options{LineHeight: config.LineHeightPixels()}
+		for _, length := range lengths {
+			text := []rune(Sample(length))
+			b.Run(Case{Size: size, Length: length}.String(), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					layout.Layout(config, text, opts)
+				}
+			})
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	for _, n := range []int{0, 1, 63, 1000} {
+		s := Sample(n)
+		if utf8.RuneCountInString(s) != n {
+			t.Errorf("Sample(%d) has %d runes", n, utf8.RuneCountInString(s))
+		}
+		if s != Sample(n) {
+			t.Error("Sample is not deterministic")
+		}
+	}
+	for _, line := range strings.Split(Sample(1000), "\n") {
+		if len(line) > 80 {
+			t.Error("Bad line length", len(line))
+		}
+	}
+}
+
+type fakeText struct {
+	set   []string
+	draws *int
+}
+
+func (t *fakeText) SetString(fs string, argv ...interface{}) {
+	if len(t.set) < 2 {
+		t.set = append(t.set, fmt.Sprintf(fs, argv...))
+	}
+}
+
+func (t *fakeText) Draw() {
+	*t.draws += 2
+}
+
+func TestSetStringAndDraw(t *testing.T) {
+	calls := 0
+	text := &fakeText{draws: &calls}
+	c := Case{Size: 12, Length: 8}
+
+	r := SetString(text, c)
+	if r.Name != "setstring" || r.Case != c {
+		t.Error("Bad result", r)
+	}
+	if len(text.set) < 2 || text.set[0] == text.set[1] || len(text.set[0]) != 8 {
+		t.Error("Consecutive strings should differ", text.set)
+	}
+
+	finished := 0
+	r = Draw(text, c, func() { finished++ }, func() int { return calls })
+	if r.DrawCalls != 2 {
+		t.Error("Bad draw calls", r.DrawCalls)
+	}
+	if finished == 0 || finished != calls/2 {
+		t.Error("finish should follow every draw", finished, calls)
+	}
+
+	var out bytes.Buffer
+	if err := Format(&out, []Result{r}); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], "draw") {
+		t.Error("Bad table", out.String())
+	}
+}
+
+func TestLayout(t *testing.T) {
+	config := loadConfig(t, 12)
+	r := Layout(config, Case{Size: 12, Length: 64}, layout.Options{LineHeight: config.LineHeightPixels()})
+	if r.Name != "layout" || r.NsPerOp <= 0 {
+		t.Error("Bad result", r)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// atlasCacheVersion is part of every key so that atlases generated by older versions of
+// the rasterizer are not reused
+const atlasCacheVersion = 1
+
+// AtlasCache keeps generated font configs in a directory, keyed by a hash of the font data
+// and the options they were generated with, so that glyphs are only rasterized once per
+// machine.  Configs are stored like Save stores them, named by their key.
+type AtlasCache struct {
+	Dir string
+}
+
+// NewAtlasCache creates a cache in the gltext directory of the user's cache directory
+func NewAtlasCache() (*AtlasCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &AtlasCache{Dir: filepath.Join(dir, "gltext")}, nil
+}
+
+// Truetype returns the config NewTruetypeFontConfigWithOptions creates from the font data,
+// reading it from the cache when it was generated before.  Failing to write the cache is
+// not an error, the config is generated again the next time.
+func (c *AtlasCache) Truetype(ttfData []byte, opts TruetypeOptions) (*FontConfig, error) {
+	return c.config("truetype", ttfData, opts, func() (*FontConfig, error) {
+		return NewTruetypeFontConfigWithOptions(bytes.NewReader(ttfData), opts)
+	})
+}
+
+// SDF returns the config GenerateSDF creates from the font data, reading it from the cache
+// when it was generated before
+func (c *AtlasCache) SDF(ttfData []byte, opts SDFOptions) (*FontConfig, error) {
+	return c.config("sdf", ttfData, opts, func() (*FontConfig, error) {
+		return GenerateSDF(ttfData, opts)
+	})
+}
+
+// Clear removes every cached config
+func (c *AtlasCache) Clear() error {
+	return os.RemoveAll(c.Dir)
+}
+
+func (c *AtlasCache) config(kind string, ttfData []byte, opts interface{}, generate func() (*FontConfig, error)) (*FontConfig, error) {
+	key, err := atlasCacheKey(kind, ttfData, opts)
+	if err != nil {
+		return nil, err
+	}
+	// a config that cannot be read, such as one partially written, is generated again
+	fc := &FontConfig{Name: key}
+	if err = fc.LoadFS(os.DirFS(c.Dir), "."); err == nil {
+		return fc, nil
+	}
+	fc, err = generate()
+	if err != nil {
+		return nil, err
+	}
+	fc.Save(c.Dir, key)
+	return fc, nil
+}
+
+// atlasCacheKey hashes the font data together with the kind of atlas and its options
+func atlasCacheKey(kind string, ttfData []byte, opts interface{}) (string, error) {
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte{atlasCacheVersion})
+	h.Write([]byte(kind))
+	h.Write(encoded)
+	h.Write(ttfData)
+	return kind + "_" + hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
@@ -0,0 +1,55 @@
+package gltext
+
+import (
+	"golang.org/x/image/math/fixed"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAtlasCache(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "gltext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := &AtlasCache{Dir: filepath.Join(dir, "cache")}
+	opts := TruetypeOptions{Scale: fixed.Int26_6(24), RuneRanges: RuneRanges{{Low: 'a', High: 'z'}}, RunesPerRow: fixed.Int26_6(8)}
+	generated, err := cache.Truetype(data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(cache.Dir, "truetype_*.config"))
+	if len(files) != 1 {
+		t.Fatal("Expecting the config to be cached", files)
+	}
+
+	cached, err := cache.Truetype(data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached == generated || len(cached.Glyphs) != len(generated.Glyphs) || cached.Glyphs[3] != generated.Glyphs[3] {
+		t.Error("Expecting the cached config", len(cached.Glyphs))
+	}
+	if cached.Image.Bounds() != generated.Image.Bounds() {
+		t.Error("Bad cached image", cached.Image.Bounds())
+	}
+
+	// other options and fonts use other keys
+	opts.Scale = fixed.Int26_6(20)
+	if _, err = cache.Truetype(data, opts); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ = filepath.Glob(filepath.Join(cache.Dir, "truetype_*.config")); len(files) != 2 {
+		t.Error("Expecting a second entry", files)
+	}
+	if err = cache.Clear(); err != nil {
+		t.Error(err)
+	}
+}
@@ -21,6 +21,13 @@ type Glyph struct {
 	// Advance determines the distance to the next glyph.
 	// This is used to properly align non-monospaced fonts.
 	Advance int `json:"advance"`
+
+	// Page is the atlas page holding the glyph (see FontConfig.Pages).
+	Page int `json:"page,omitempty"`
+
+	// OffsetY is the distance from the bottom of the line to the bottom of the glyph.
+	// It is zero for glyphs as tall as the line.
+	OffsetY int `json:"offsetY,omitempty"`
 }
 
 func (g *Glyph) GetTexturePositions(font FontLike) (tP1, tP2 Point) {
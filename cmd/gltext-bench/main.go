@@ -0,0 +1,143 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// gltext-bench measures layout throughput, buffer upload cost and draw-call overhead of
+// the v4.1 package across font sizes and string lengths, printing a table of results.
+// A hidden window is opened for the GL measurements, -gl=false measures layout only.
+//
+//	gltext-bench -ttf font/font_1_honokamin.ttf -sizes 12,32,64 -lengths 16,256,4096
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/benchmarks"
+	"github.com/4ydx/gltext/layout"
+	"github.com/4ydx/gltext/v4.1"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"golang.org/x/image/math/fixed"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	ttf := flag.String("ttf", "font/font_1_honokamin.ttf", "truetype font file")
+	sizes := flag.String("sizes", "12,32,64", "comma separated font scales in points")
+	lengths := flag.String("lengths", "16,256,4096", "comma separated string lengths in runes")
+	useGL := flag.Bool("gl", true, "measure uploads and draws as well as layout")
+	flag.Parse()
+
+	scales, err := parseInts(*sizes)
+	if err != nil {
+		fail(err)
+	}
+	runeCounts, err := parseInts(*lengths)
+	if err != nil {
+		fail(err)
+	}
+
+	var window *glfw.Window
+	if *useGL {
+		runtime.LockOSThread()
+		if window, err = openWindow(); err != nil {
+			fail(err)
+		}
+		defer glfw.Terminate()
+		defer window.Destroy()
+	}
+
+	results := []benchmarks.Result{}
+	for _, size := range scales {
+		config, err := loadConfig(*ttf, size)
+		if err != nil {
+			fail(err)
+		}
+		opts := layout.Options{LineHeight: config.LineHeightPixels()}
+		for _, length := range runeCounts {
+			results = append(results, benchmarks.Layout(config, benchmarks.Case{Size: size, Length: length}, opts))
+		}
+		if window == nil {
+			continue
+		}
+		font, err := v41.NewFont(config)
+		if err != nil {
+			fail(err)
+		}
+		width, height := window.GetSize()
+		font.ResizeWindow(float32(width), float32(height))
+		for _, length := range runeCounts {
+			c := benchmarks.Case{Size: size, Length: length}
+			text := v41.NewText(font, 1, 1)
+			results = append(results, benchmarks.SetString(text, c))
+			drawCalls := func() int { return font.Stats().DrawCalls }
+			results = append(results, benchmarks.Draw(text, c, gl.Finish, drawCalls))
+			text.Release()
+		}
+		font.Release()
+	}
+	if err = benchmarks.Format(os.Stdout, results); err != nil {
+		fail(err)
+	}
+}
+
+func openWindow() (*glfw.Window, error) {
+	if err := glfw.Init(); err != nil {
+		return nil, err
+	}
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	glfw.WindowHint(glfw.ContextVersionMajor, 3)
+	glfw.WindowHint(glfw.ContextVersionMinor, 3)
+	if runtime.GOOS == "darwin" {
+		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	}
+	window, err := glfw.CreateWindow(1280, 960, "gltext-bench", nil, nil)
+	if err != nil {
+		glfw.Terminate()
+		return nil, err
+	}
+	window.MakeContextCurrent()
+	// measure the draws, not the wait for vertical sync
+	glfw.SwapInterval(0)
+	if err = gl.Init(); err != nil {
+		window.Destroy()
+		glfw.Terminate()
+		return nil, err
+	}
+	return window, nil
+}
+
+func loadConfig(ttf string, size int) (*gltext.FontConfig, error) {
+	fd, err := os.Open(ttf)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	runeRanges := gltext.RuneRanges{{Low: 32, High: 127}}
+	return gltext.NewTruetypeFontConfig(fd, fixed.Int26_6(size), runeRanges, 16, 5)
+}
+
+func parseInts(s string) ([]int, error) {
+	values := []int{}
+	for _, part := range strings.Split(s, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("invalid value %d", value)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
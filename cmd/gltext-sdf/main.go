@@ -0,0 +1,88 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// gltext-sdf generates a signed distance field atlas and config from a truetype font.
+// The output can be loaded with gltext.LoadTruetypeFontConfig.
+//
+//	gltext-sdf -ttf font.ttf -scale 32 -ranges 32-127,0x3040-0x309f -out fontconfigs -name font
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/4ydx/gltext"
+	"golang.org/x/image/math/fixed"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	ttf := flag.String("ttf", "", "truetype font file")
+	scale := flag.Int("scale", 32, "font scale in points")
+	ranges := flag.String("ranges", "32-127", "comma separated rune ranges, EG 32-127,0x3000-0x303f")
+	runesPerRow := flag.Int("row", 16, "glyphs per row of the atlas")
+	spread := flag.Float64("spread", 4, "distance in pixels covered by the field")
+	upsample := flag.Int("upsample", 8, "rasterization factor used to compute the field")
+	out := flag.String("out", "fontconfigs", "output directory")
+	name := flag.String("name", "", "output name, defaults to the font file name")
+	flag.Parse()
+
+	if *ttf == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *name == "" {
+		*name = strings.TrimSuffix(filepath.Base(*ttf), filepath.Ext(*ttf))
+	}
+	runeRanges, err := parseRanges(*ranges)
+	if err != nil {
+		fail(err)
+	}
+	data, err := ioutil.ReadFile(*ttf)
+	if err != nil {
+		fail(err)
+	}
+	config, err := gltext.GenerateSDF(data, gltext.SDFOptions{
+		Scale:       fixed.Int26_6(*scale),
+		RuneRanges:  runeRanges,
+		RunesPerRow: fixed.Int26_6(*runesPerRow),
+		Spread:      float32(*spread),
+		Upsample:    *upsample,
+	})
+	if err != nil {
+		fail(err)
+	}
+	if err = config.Save(*out, *name); err != nil {
+		fail(err)
+	}
+	fmt.Printf("wrote %s/%s.config and %s/%s.png\n", *out, *name, *out, *name)
+}
+
+func parseRanges(s string) (gltext.RuneRanges, error) {
+	runeRanges := gltext.RuneRanges{}
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid rune range %q", part)
+		}
+		low, err := strconv.ParseInt(bounds[0], 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		high, err := strconv.ParseInt(bounds[1], 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		runeRanges = append(runeRanges, gltext.RuneRange{Low: rune(low), High: rune(high)})
+	}
+	return runeRanges, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,82 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NamedColors are the colors ParseColor accepts by name, the basic colors of CSS.
+var NamedColors = map[string]color.NRGBA{
+	"transparent": {0, 0, 0, 0},
+	"black":       {0, 0, 0, 255},
+	"silver":      {192, 192, 192, 255},
+	"gray":        {128, 128, 128, 255},
+	"grey":        {128, 128, 128, 255},
+	"white":       {255, 255, 255, 255},
+	"maroon":      {128, 0, 0, 255},
+	"red":         {255, 0, 0, 255},
+	"purple":      {128, 0, 128, 255},
+	"fuchsia":     {255, 0, 255, 255},
+	"magenta":     {255, 0, 255, 255},
+	"green":       {0, 128, 0, 255},
+	"lime":        {0, 255, 0, 255},
+	"olive":       {128, 128, 0, 255},
+	"yellow":      {255, 255, 0, 255},
+	"navy":        {0, 0, 128, 255},
+	"blue":        {0, 0, 255, 255},
+	"teal":        {0, 128, 128, 255},
+	"aqua":        {0, 255, 255, 255},
+	"cyan":        {0, 255, 255, 255},
+	"orange":      {255, 165, 0, 255},
+}
+
+// ParseColor reads a color name of NamedColors, in any case, or a hexadecimal color
+// written as #RGB, #RRGGBB or #RRGGBBAA.  The leading # is optional.
+func ParseColor(s string) (color.NRGBA, error) {
+	if c, ok := NamedColors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("bad color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("bad color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// SRGBToLinear converts a color component in the range 0 to 1 from sRGB, the space of
+// colors picked in image editors and mockups, to linear light.
+func SRGBToLinear(c float32) float32 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return float32(math.Pow((float64(c)+0.055)/1.055, 2.4))
+}
+
+// LinearToSRGB converts a color component in the range 0 to 1 from linear light to sRGB.
+func LinearToSRGB(c float32) float32 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return float32(1.055*math.Pow(float64(c), 1/2.4) - 0.055)
+}
+
+// Palette maps the names of the roles of colors, such as "primary", "error" or "muted",
+// to the colors of a theme.
+type Palette map[string]color.NRGBA
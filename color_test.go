@@ -0,0 +1,17 @@
+package gltext
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSRGB(t *testing.T) {
+	for _, c := range []struct{ srgb, linear float32 }{{0, 0}, {0.5, 0.214}, {1, 1}, {0.02, 0.00155}} {
+		if l := SRGBToLinear(c.srgb); math.Abs(float64(l-c.linear)) > 0.001 {
+			t.Errorf("Expecting %v to be %v in linear light, got %v", c.srgb, c.linear, l)
+		}
+		if s := LinearToSRGB(SRGBToLinear(c.srgb)); math.Abs(float64(s-c.srgb)) > 0.0001 {
+			t.Errorf("Expecting %v to convert back, got %v", c.srgb, s)
+		}
+	}
+}
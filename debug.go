@@ -7,6 +7,15 @@ import (
 
 var IsDebug = false
 
+// LeakWarnings makes the fonts and texts created while it is set print a warning when
+// they are garbage collected without having been released, leaking their gl objects.
+var LeakWarnings = false
+
+// ThreadChecks makes the fonts initialized while it is set record the os thread they were
+// created on, panicking when texts are created, set or drawn from another thread rather
+// than letting the driver crash or silently corrupt its state.
+var ThreadChecks = false
+
 func DebugPrefix() string {
 	_, fn, line, _ := runtime.Caller(1)
 	return fmt.Sprintf("DB: [%s:%d]", fn, line)
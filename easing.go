@@ -0,0 +1,99 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"math"
+)
+
+// Easing maps the linear progress of an animation, ranged 0 to 1, onto the
+// eased progress.  Easing functions return 0 for 0 and 1 for 1 but may leave
+// that range in between (EaseOutBack overshoots for example).
+type Easing func(t float32) float32
+
+func EaseLinear(t float32) float32 { return t }
+
+func EaseInQuad(t float32) float32  { return t * t }
+func EaseOutQuad(t float32) float32 { return t * (2 - t) }
+func EaseInOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+func EaseInCubic(t float32) float32  { return t * t * t }
+func EaseOutCubic(t float32) float32 { t--; return t*t*t + 1 }
+func EaseInOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+func EaseInSine(t float32) float32 { return 1 - float32(math.Cos(float64(t)*math.Pi/2)) }
+func EaseOutSine(t float32) float32 {
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+func EaseInOutSine(t float32) float32 {
+	return -(float32(math.Cos(math.Pi*float64(t))) - 1) / 2
+}
+
+// EaseOutBack overshoots the target slightly before settling.
+func EaseOutBack(t float32) float32 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	t--
+	return 1 + c3*t*t*t + c1*t*t
+}
+
+// EaseOutBounce bounces against the target like a dropped ball.
+func EaseOutBounce(t float32) float32 {
+	const n1, d1 = 7.5625, 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	}
+	t -= 2.625 / d1
+	return n1*t*t + 0.984375
+}
+
+// Tween tracks the progress of an animation lasting Duration seconds.
+type Tween struct {
+	Duration float32
+	Easing   Easing // EaseLinear is used when nil
+	elapsed  float32
+}
+
+// NewTween creates a tween lasting duration seconds.
+func NewTween(duration float32, easing Easing) *Tween {
+	return &Tween{Duration: duration, Easing: easing}
+}
+
+// Update advances the tween by dt seconds and returns the eased progress
+// along with whether the tween has completed.
+func (tw *Tween) Update(dt float32) (progress float32, done bool) {
+	tw.elapsed += dt
+	t := float32(1)
+	if tw.Duration > 0 && tw.elapsed < tw.Duration {
+		t = tw.elapsed / tw.Duration
+	}
+	if tw.Easing == nil {
+		return t, t >= 1
+	}
+	return tw.Easing(t), t >= 1
+}
+
+// Lerp linearly interpolates between a and b.
+func Lerp(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
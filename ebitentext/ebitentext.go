@@ -0,0 +1,157 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ebitentext draws text with Ebiten, so that games built on it use the font
+// configs, layout, wrapping and style runs of gltext.  Glyphs are drawn as triangles
+// with Image.DrawTriangles from the atlas pages of the font.
+//
+//	font := ebitentext.NewFont(config)
+//	text := ebitentext.NewText(font)
+//	text.Options.MaxWidth = 200
+//	text.SetString("Hello")
+//
+//	func (g *Game) Draw(screen *ebiten.Image) {
+//		text.Draw(screen, 10, 10, color.White)
+//	}
+package ebitentext
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+// maxQuads is the number of quads a single DrawTriangles call can index with uint16
+const maxQuads = (1 << 16) / 4
+
+// Font is a font config along with its atlas pages uploaded as Ebiten images.
+type Font struct {
+	Config *gltext.FontConfig
+	pages  []*ebiten.Image
+}
+
+// NewFont creates the Ebiten images of the pages of config
+func NewFont(config *gltext.FontConfig) *Font {
+	f := &Font{Config: config}
+	for _, page := range config.PageImages() {
+		f.pages = append(f.pages, ebiten.NewImageFromImage(page))
+	}
+	return f
+}
+
+// Release disposes of the images of the pages
+func (f *Font) Release() {
+	for _, page := range f.pages {
+		page.Dispose()
+	}
+	f.pages = nil
+}
+
+// Text is a string laid out with a font, ready to be drawn.
+type Text struct {
+	Font *Font
+
+	// Options are used by SetString.  The line height of the font is used when
+	// LineHeight is zero.
+	Options layout.Options
+
+	// Runs color parts of the string, see gltext.StyleRuns.ColorAt
+	Runs gltext.StyleRuns
+
+	String string
+
+	// Width and Height of the laid out string
+	Width, Height float32
+
+	batches []batch
+}
+
+// batch is drawn with a single DrawTriangles call
+type batch struct {
+	page     int
+	vertices []ebiten.Vertex
+	indices  []uint16
+	colors   []color.Color // per quad, nil for the color passed to Draw
+}
+
+// NewText creates an empty text drawn with f
+func NewText(f *Font) *Text {
+	return &Text{Font: f}
+}
+
+// SetString lays out s
+func (t *Text) SetString(s string) {
+	t.String = s
+	opts := t.Options
+	if opts.LineHeight == 0 {
+		opts.LineHeight = t.Font.Config.LineHeightPixels()
+	}
+	placed := layout.Layout(t.Font.Config, []rune(s), opts)
+
+	// quads are placed below the top of the first line, ebiten's y axis growing downwards
+	t.batches = t.batches[:0]
+	t.Width, t.Height = 0, opts.LineHeight
+	for _, p := range placed {
+		q := t.Font.Config.Quad(p.Glyph, p.X, p.Y)
+		if q.X2.X > t.Width {
+			t.Width = q.X2.X
+		}
+		if bottom := opts.LineHeight - p.Y; bottom > t.Height {
+			t.Height = bottom
+		}
+		b := t.batch(q.Page)
+		first := uint16(len(b.vertices))
+		for _, corner := range [4][4]float32{
+			{q.X1.X, q.X1.Y, q.Src1.X, q.Src2.Y},
+			{q.X2.X, q.X1.Y, q.Src2.X, q.Src2.Y},
+			{q.X2.X, q.X2.Y, q.Src2.X, q.Src1.Y},
+			{q.X1.X, q.X2.Y, q.Src1.X, q.Src1.Y},
+		} {
+			b.vertices = append(b.vertices, ebiten.Vertex{DstX: corner[0], DstY: opts.LineHeight - corner[1], SrcX: corner[2], SrcY: corner[3]})
+		}
+		b.indices = append(b.indices, first, first+1, first+2, first, first+2, first+3)
+		b.colors = append(b.colors, t.Runs.ColorAt(p.Index))
+	}
+}
+
+// batch returns the batch the next quad of page is added to
+func (t *Text) batch(page int) *batch {
+	for i := len(t.batches) - 1; i >= 0; i-- {
+		if b := &t.batches[i]; b.page == page {
+			if len(b.colors) < maxQuads {
+				return b
+			}
+			break
+		}
+	}
+	t.batches = append(t.batches, batch{page: page})
+	return &t.batches[len(t.batches)-1]
+}
+
+// Draw draws the text onto dst with the upper left corner of its first line at (x, y)
+func (t *Text) Draw(dst *ebiten.Image, x, y float32, clr color.Color) {
+	options := &ebiten.DrawTrianglesOptions{Filter: ebiten.FilterLinear}
+	for i := range t.batches {
+		b := &t.batches[i]
+		vertices := make([]ebiten.Vertex, len(b.vertices))
+		for j, v := range b.vertices {
+			c := clr
+			if runColor := b.colors[j/4]; runColor != nil {
+				c = runColor
+			}
+			v.DstX += x
+			v.DstY += y
+			v.ColorR, v.ColorG, v.ColorB, v.ColorA = components(c)
+			vertices[j] = v
+		}
+		dst.DrawTriangles(vertices, b.indices, t.Font.pages[b.page], options)
+	}
+}
+
+// components returns the straight alpha components of c in the range 0 to 1
+func components(c color.Color) (r, g, b, a float32) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return float32(n.R) / 255, float32(n.G) / 255, float32(n.B) / 255, float32(n.A) / 255
+}
@@ -0,0 +1,36 @@
+package ebitentext
+
+import (
+	"github.com/4ydx/gltext"
+	"image/color"
+	"testing"
+)
+
+func TestSetString(t *testing.T) {
+	config := &gltext.FontConfig{
+		RuneRanges: gltext.RuneRanges{{Low: 'a', High: 'c'}},
+		Glyphs:     gltext.Charset{{X: 0, Width: 8, Height: 10, Advance: 8}, {X: 8, Width: 6, Height: 10, Advance: 6}},
+	}
+	text := NewText(&Font{Config: config})
+	text.Runs = gltext.StyleRuns{{Start: 1, End: 2, Color: color.White}}
+	text.SetString("ab\na")
+
+	if text.Width != 14 || text.Height != 20 {
+		t.Error("Bad size", text.Width, text.Height)
+	}
+	if len(text.batches) != 1 || len(text.batches[0].indices) != 3*6 {
+		t.Fatal("Expecting a batch of three quads")
+	}
+	b := text.batches[0]
+
+	// the first quad starts at the top left, the last one sits on the second line
+	if v := b.vertices[3]; v.DstX != 0 || v.DstY != 0 || v.SrcX != 0 || v.SrcY != 0 {
+		t.Error("Bad upper left corner", v)
+	}
+	if v := b.vertices[8]; v.DstX != 0 || v.DstY != 20 || v.SrcY != 10 {
+		t.Error("Bad lower left corner of the second line", v)
+	}
+	if b.colors[0] != nil || b.colors[1] != color.White {
+		t.Error("Expecting the second glyph to use the color of its run", b.colors)
+	}
+}
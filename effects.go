@@ -0,0 +1,73 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"math"
+)
+
+// GlyphEffect moves individual glyphs of a text over time.
+type GlyphEffect interface {
+	// Offset returns the displacement of glyph i, t seconds after the effect began.
+	Offset(i int, t float32) (dx, dy float32)
+}
+
+// Wave moves glyphs up and down along a sine wave.  Phase is the offset in radians
+// between two neighbouring glyphs.
+type Wave struct {
+	Amplitude float32 // In pixels.
+	Frequency float32 // In cycles per second.
+	Phase     float32
+}
+
+func (w Wave) Offset(i int, t float32) (dx, dy float32) {
+	angle := 2*math.Pi*float64(w.Frequency*t) + float64(w.Phase)*float64(i)
+	return 0, w.Amplitude * float32(math.Sin(angle))
+}
+
+// Shake jitters glyphs randomly within Amplitude pixels, picking a new offset
+// Frequency times per second.  The same Seed always produces the same jitter.
+type Shake struct {
+	Amplitude float32
+	Frequency float32
+	Seed      uint32
+}
+
+func (s Shake) Offset(i int, t float32) (dx, dy float32) {
+	step := uint32(s.Frequency * t)
+	dx = s.Amplitude * (2*noise(s.Seed, uint32(i), step, 0) - 1)
+	dy = s.Amplitude * (2*noise(s.Seed, uint32(i), step, 1) - 1)
+	return
+}
+
+// Bounce drops each glyph from Height pixels above its place, bouncing as it lands.
+// Each glyph takes Duration seconds and starts Delay seconds after the previous one.
+type Bounce struct {
+	Height   float32
+	Duration float32
+	Delay    float32
+}
+
+func (b Bounce) Offset(i int, t float32) (dx, dy float32) {
+	t -= b.Delay * float32(i)
+	if t <= 0 {
+		return 0, b.Height
+	}
+	if b.Duration <= 0 || t >= b.Duration {
+		return 0, 0
+	}
+	return 0, b.Height * (1 - EaseOutBounce(t/b.Duration))
+}
+
+// noise hashes its input into a value ranged 0 to 1.
+func noise(seed, i, step, axis uint32) float32 {
+	h := seed*0x9e3779b9 ^ i*0x85ebca6b ^ step*0xc2b2ae35 ^ axis*0x27d4eb2f
+	h ^= h >> 16
+	h *= 0x7feb352d
+	h ^= h >> 15
+	h *= 0x846ca68b
+	h ^= h >> 16
+	return float32(h) / float32(math.MaxUint32)
+}
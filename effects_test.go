@@ -0,0 +1,22 @@
+package gltext
+
+import (
+	"testing"
+)
+
+func TestEffects(t *testing.T) {
+	b := Bounce{Height: 10, Duration: 1, Delay: 0.5}
+	if _, dy := b.Offset(1, 0.25); dy != 10 {
+		t.Error("Expecting second glyph to wait", dy)
+	}
+	if _, dy := b.Offset(0, 1); dy != 0 {
+		t.Error("Expecting first glyph to land", dy)
+	}
+
+	s := Shake{Amplitude: 2, Frequency: 10, Seed: 7}
+	dx1, dy1 := s.Offset(3, 0.15)
+	dx2, dy2 := s.Offset(3, 0.15)
+	if dx1 != dx2 || dy1 != dy2 || dx1 < -2 || dx1 > 2 {
+		t.Error("Expecting deterministic jitter", dx1, dx2)
+	}
+}
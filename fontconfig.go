@@ -11,8 +11,11 @@ import (
 	"fmt"
 	"image"
 	"image/png"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path"
 	"time"
 )
 
@@ -34,26 +37,123 @@ type FontConfig struct {
 
 	Image *image.NRGBA `json:"-"`
 
+	// Pages holds every page of a multi-page atlas, the first one being Image.
+	// It may be empty for single page fonts.  Pages after the first are stored
+	// next to the config as <name>_<page>.png.
+	Pages     []*image.NRGBA `json:"-"`
+	PageCount int            `json:",omitempty"`
+
 	Name string
+
+	// LineHeight is the height of a line of text.  When zero the tallest glyph is used.
+	LineHeight int `json:",omitempty"`
+
+	// SDF is set when the image holds a signed distance field (see GenerateSDF)
+	// SDFSpread is the distance in pixels covered by the field on either side of an outline
+	SDF       bool
+	SDFSpread float32
+
+	// The options the config was generated with, which allow it to be regenerated from
+	// an updated font file.  Only one of them is set.
+	TruetypeOptions *TruetypeOptions `json:",omitempty"`
+	SDFOptions      *SDFOptions      `json:",omitempty"`
+}
+
+// Regenerate rasterizes the truetype font read from r using the options this config
+// was generated with.  The name of the config is kept.
+func (fc *FontConfig) Regenerate(r io.Reader) (*FontConfig, error) {
+	var regenerated *FontConfig
+	var err error
+	switch {
+	case fc.TruetypeOptions != nil:
+		regenerated, err = NewTruetypeFontConfigWithOptions(r, *fc.TruetypeOptions)
+	case fc.SDFOptions != nil:
+		var data []byte
+		if data, err = ioutil.ReadAll(r); err == nil {
+			regenerated, err = GenerateSDF(data, *fc.SDFOptions)
+		}
+	default:
+		return nil, errors.New("Config was not generated from a truetype font.")
+	}
+	if err != nil {
+		return nil, err
+	}
+	regenerated.Name = fc.Name
+	return regenerated, nil
 }
 
 // Load reads font configuration data from the given JSON encoded stream.
 func (fc *FontConfig) Load(rootPath string) (err error) {
-	file := fmt.Sprintf("%s/%s.config", rootPath, fc.Name)
-	data, err := ioutil.ReadFile(file)
+	fmt.Printf("%+v\n", time.Now())
+	err = fc.load(func(name string) (io.ReadCloser, error) {
+		return os.Open(fmt.Sprintf("%s/%s", rootPath, name))
+	})
+	fmt.Printf("%+v\n", time.Now())
+	return err
+}
+
+// LoadFS reads the font configuration and images stored in rootPath of fsys,
+// which allows fonts to be embedded in the binary using go:embed.
+func (fc *FontConfig) LoadFS(fsys fs.FS, rootPath string) error {
+	return fc.load(func(name string) (io.ReadCloser, error) {
+		return fsys.Open(path.Join(rootPath, name))
+	})
+}
+
+// LoadFrom reads the JSON encoded font configuration from config and the png encoded
+// images of its pages from pages, the first of which is the font image.
+func (fc *FontConfig) LoadFrom(config io.Reader, pages ...io.Reader) error {
+	return fc.load(func(name string) (io.ReadCloser, error) {
+		if name == fc.Name+".config" {
+			return ioutil.NopCloser(config), nil
+		}
+		for i := range pages {
+			if name == pageName(fc.Name, i)+".png" || (i == 0 && name == fc.Name+".png") {
+				return ioutil.NopCloser(pages[i]), nil
+			}
+		}
+		return nil, fmt.Errorf("no reader supplied for %s", name)
+	})
+}
+
+// load reads the config and its images using open, which is passed file names
+// relative to the directory holding the config
+func (fc *FontConfig) load(open func(name string) (io.ReadCloser, error)) error {
+	name := fc.Name
+	file, err := open(name + ".config")
 	if err != nil {
-		return
+		return err
 	}
-	err = json.Unmarshal(data, fc)
+	err = json.NewDecoder(file).Decode(fc)
+	file.Close()
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%+v\n", time.Now())
-	fc.Image, err = LoadFontImage(rootPath, fc.Name)
+	fc.Name = name
+
+	loadPage := func(name string) (*image.NRGBA, error) {
+		file, err := open(name + ".png")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return DecodeImage(file)
+	}
+	fc.Image, err = loadPage(fc.Name)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%+v\n", time.Now())
+	fc.Pages = nil
+	if fc.PageCount > 1 {
+		fc.Pages = append(fc.Pages, fc.Image)
+		for i := 1; i < fc.PageCount; i++ {
+			page, err := loadPage(pageName(fc.Name, i))
+			if err != nil {
+				return err
+			}
+			fc.Pages = append(fc.Pages, page)
+		}
+	}
 	fc.Glyphs.Scale(1)
 	return nil
 }
@@ -85,10 +185,41 @@ func (fc *FontConfig) Save(rootPath, name string) error {
 	if err != nil {
 		return err
 	}
+	for i, page := range fc.PageImages() {
+		if i == 0 {
+			continue
+		}
+		err = SaveImage(rootPath, pageName(fc.Name, i), page)
+		if err != nil {
+			return err
+		}
+	}
 	err = ioutil.WriteFile(file, data, os.ModePerm)
 	return err
 }
 
+// Glyph returns the index of the glyph of r within Glyphs and its advance, allowing
+// the config to be laid out by the layout package.
+func (fc *FontConfig) Glyph(r rune) (index int, advance float32, ok bool) {
+	glyphIndex := fc.RuneRanges.GetGlyphIndex(r)
+	if glyphIndex < 0 {
+		return 0, 0, false
+	}
+	return int(glyphIndex), float32(fc.Glyphs[glyphIndex].Advance), true
+}
+
+// PageImages returns the images of every atlas page.
+func (fc *FontConfig) PageImages() []*image.NRGBA {
+	if len(fc.Pages) > 0 {
+		return fc.Pages
+	}
+	return []*image.NRGBA{fc.Image}
+}
+
+func pageName(name string, page int) string {
+	return fmt.Sprintf("%s_%d", name, page)
+}
+
 func LoadFontImage(rootPath, name string) (*image.NRGBA, error) {
 	file := fmt.Sprintf("%s/%s.png", rootPath, name)
 	return LoadImage(file)
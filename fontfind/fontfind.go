@@ -0,0 +1,276 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fontfind locates the truetype fonts installed on the system.
+//
+// On Linux fc-match is asked first, so that the user's fontconfig settings and
+// aliases such as "sans" are honoured.  Otherwise, and on Windows and macOS, the
+// standard font directories are scanned and the name table of each font is read.
+package fontfind
+
+import (
+	"bytes"
+	"errors"
+	"github.com/4ydx/gltext"
+	"github.com/golang/freetype/truetype"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Style is the slant of a font.
+type Style int
+
+const (
+	StyleNormal Style = iota
+	StyleItalic
+)
+
+// Common weights, as used by CSS and the OS/2 table of truetype fonts.
+const (
+	WeightThin       = 100
+	WeightExtraLight = 200
+	WeightLight      = 300
+	WeightRegular    = 400
+	WeightMedium     = 500
+	WeightSemiBold   = 600
+	WeightBold       = 700
+	WeightExtraBold  = 800
+	WeightBlack      = 900
+)
+
+// Query describes the font being looked for.  Family may be a generic family:
+// "sans", "serif" or "monospace".  A zero Weight means WeightRegular.
+type Query struct {
+	Family string
+	Weight int
+	Style  Style
+}
+
+// Font is a font file found on the system.
+type Font struct {
+	Path      string
+	Family    string
+	Subfamily string // EG "Bold Italic"
+	Weight    int
+	Style     Style
+}
+
+// ErrNotFound is returned when no installed font belongs to the family queried.
+var ErrNotFound = errors.New("Font not found.")
+
+// generic families and the fonts they are looked up as when fc-match is not available
+var generic = map[string][]string{
+	"sans":      {"DejaVu Sans", "Noto Sans", "Liberation Sans", "Segoe UI", "Arial", "Helvetica", "Verdana"},
+	"serif":     {"DejaVu Serif", "Noto Serif", "Liberation Serif", "Times New Roman", "Times", "Georgia"},
+	"monospace": {"DejaVu Sans Mono", "Noto Sans Mono", "Liberation Mono", "Consolas", "Menlo", "Courier New"},
+}
+
+// Find returns the installed font that best matches q.
+func Find(q Query) (Font, error) {
+	if q.Weight == 0 {
+		q.Weight = WeightRegular
+	}
+	if runtime.GOOS == "linux" {
+		if f, err := fcMatch(q); err == nil {
+			return f, nil
+		}
+	}
+	fonts, err := List()
+	if err != nil {
+		return Font{}, err
+	}
+	return match(fonts, q)
+}
+
+// Load finds the font matching q and rasterizes it using opts.
+func Load(q Query, opts gltext.TruetypeOptions) (*gltext.FontConfig, error) {
+	f, err := Find(q)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return gltext.NewTruetypeFontConfigWithOptions(fd, opts)
+}
+
+// List returns every truetype font found in the font directories of the system.
+func List() ([]Font, error) {
+	return scan(Dirs())
+}
+
+// Dirs returns the directories fonts are installed in on this system, user directories first.
+func Dirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		windir := os.Getenv("WINDIR")
+		if windir == "" {
+			windir = `C:\Windows`
+		}
+		dirs := []string{}
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+		}
+		return append(dirs, filepath.Join(windir, "Fonts"))
+	case "darwin":
+		return []string{
+			filepath.Join(home, "Library", "Fonts"),
+			"/Library/Fonts",
+			"/System/Library/Fonts",
+			"/Network/Library/Fonts",
+		}
+	}
+	return []string{
+		filepath.Join(home, ".local", "share", "fonts"),
+		filepath.Join(home, ".fonts"),
+		"/usr/local/share/fonts",
+		"/usr/share/fonts",
+	}
+}
+
+// scan reads the name table of every font found below dirs.  Files that cannot
+// be parsed, such as CFF based fonts, are skipped.
+func scan(dirs []string) ([]Font, error) {
+	fonts := []Font{}
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if path == dir {
+					// missing font directories are common
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".ttf") {
+				return nil
+			}
+			if f, err := readFont(path); err == nil {
+				fonts = append(fonts, f)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fonts, nil
+}
+
+func readFont(path string) (Font, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Font{}, err
+	}
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return Font{}, err
+	}
+	f := Font{
+		Path:      path,
+		Family:    ttf.Name(truetype.NameIDPreferredFamily),
+		Subfamily: ttf.Name(truetype.NameIDPreferredSubfamily),
+	}
+	if f.Family == "" {
+		f.Family = ttf.Name(truetype.NameIDFontFamily)
+	}
+	if f.Subfamily == "" {
+		f.Subfamily = ttf.Name(truetype.NameIDFontSubfamily)
+	}
+	f.Weight, f.Style = parseSubfamily(f.Subfamily)
+	return f, nil
+}
+
+// parseSubfamily derives the weight and style of a font from names like "Bold Italic"
+func parseSubfamily(subfamily string) (weight int, style Style) {
+	s := strings.ToLower(strings.Replace(strings.Replace(subfamily, " ", "", -1), "-", "", -1))
+	if strings.Contains(s, "italic") || strings.Contains(s, "oblique") {
+		style = StyleItalic
+	}
+	// longer names first so that "semibold" is not taken for "bold"
+	for _, w := range []struct {
+		name   string
+		weight int
+	}{
+		{"extralight", WeightExtraLight}, {"ultralight", WeightExtraLight},
+		{"semibold", WeightSemiBold}, {"demibold", WeightSemiBold},
+		{"extrabold", WeightExtraBold}, {"ultrabold", WeightExtraBold},
+		{"thin", WeightThin}, {"light", WeightLight}, {"medium", WeightMedium},
+		{"bold", WeightBold}, {"black", WeightBlack}, {"heavy", WeightBlack},
+	} {
+		if strings.Contains(s, w.name) {
+			return w.weight, style
+		}
+	}
+	return WeightRegular, style
+}
+
+// match returns the font of the queried family closest in weight and style
+func match(fonts []Font, q Query) (Font, error) {
+	families := generic[strings.ToLower(q.Family)]
+	if families == nil {
+		families = []string{q.Family}
+	}
+	for _, family := range families {
+		best, bestScore := -1, 0
+		for i, f := range fonts {
+			if !strings.EqualFold(f.Family, family) {
+				continue
+			}
+			score := f.Weight - q.Weight
+			if score < 0 {
+				score = -score
+			}
+			if f.Style != q.Style {
+				score += 1000
+			}
+			if best < 0 || score < bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best >= 0 {
+			return fonts[best], nil
+		}
+	}
+	return Font{}, ErrNotFound
+}
+
+// fcMatch asks fontconfig for the font matching q
+func fcMatch(q Query) (Font, error) {
+	pattern := q.Family + ":weight=" + fcWeight(q.Weight)
+	if q.Style == StyleItalic {
+		pattern += ":slant=italic"
+	}
+	out, err := exec.Command("fc-match", "--format=%{file}\n%{family[0]}\n%{style[0]}", pattern).Output()
+	if err != nil {
+		return Font{}, err
+	}
+	lines := strings.SplitN(string(bytes.TrimSpace(out)), "\n", 3)
+	if len(lines) < 3 || !strings.EqualFold(filepath.Ext(lines[0]), ".ttf") {
+		// only truetype outlines can be rasterized
+		return Font{}, ErrNotFound
+	}
+	f := Font{Path: lines[0], Family: lines[1], Subfamily: lines[2]}
+	f.Weight, f.Style = parseSubfamily(f.Subfamily)
+	return f, nil
+}
+
+// fcWeight returns the fontconfig name of the nearest weight
+func fcWeight(weight int) string {
+	names := []string{"thin", "extralight", "light", "regular", "medium", "demibold", "bold", "extrabold", "black"}
+	i := (weight+50)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(names) {
+		i = len(names) - 1
+	}
+	return names[i]
+}
@@ -0,0 +1,67 @@
+package fontfind
+
+import (
+	"testing"
+)
+
+func TestParseSubfamily(t *testing.T) {
+	for subfamily, expected := range map[string]struct {
+		weight int
+		style  Style
+	}{
+		"Regular":          {WeightRegular, StyleNormal},
+		"Bold Italic":      {WeightBold, StyleItalic},
+		"SemiBold":         {WeightSemiBold, StyleNormal},
+		"Extra-Light":      {WeightExtraLight, StyleNormal},
+		"Oblique":          {WeightRegular, StyleItalic},
+		"Black Italic":     {WeightBlack, StyleItalic},
+		"Medium Condensed": {WeightMedium, StyleNormal},
+	} {
+		weight, style := parseSubfamily(subfamily)
+		if weight != expected.weight || style != expected.style {
+			t.Error("Bad subfamily", subfamily, weight, style)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	fonts := []Font{
+		{Path: "a", Family: "DejaVu Sans", Weight: WeightRegular},
+		{Path: "b", Family: "DejaVu Sans", Weight: WeightBold},
+		{Path: "c", Family: "DejaVu Sans", Weight: WeightBold, Style: StyleItalic},
+		{Path: "d", Family: "Other", Weight: WeightBold},
+	}
+	for _, test := range []struct {
+		q    Query
+		path string
+	}{
+		{Query{Family: "dejavu sans", Weight: WeightRegular}, "a"},
+		{Query{Family: "sans", Weight: WeightSemiBold}, "b"},
+		{Query{Family: "DejaVu Sans", Weight: WeightBlack, Style: StyleItalic}, "c"},
+		{Query{Family: "Other", Weight: WeightThin, Style: StyleItalic}, "d"},
+	} {
+		f, err := match(fonts, test.q)
+		if err != nil || f.Path != test.path {
+			t.Error("Bad match", test.q, f, err)
+		}
+	}
+	if _, err := match(fonts, Query{Family: "serif"}); err != ErrNotFound {
+		t.Error("Expecting no match", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	fonts, err := scan([]string{"../font", "does-not-exist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fonts) != 1 || fonts[0].Family == "" {
+		t.Fatal("Expecting the repository font", fonts)
+	}
+}
+
+func TestFcWeight(t *testing.T) {
+	if fcWeight(WeightRegular) != "regular" || fcWeight(WeightBold) != "bold" || fcWeight(0) != "thin" || fcWeight(1000) != "black" {
+		t.Error("Bad fontconfig weights")
+	}
+}
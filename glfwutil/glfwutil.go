@@ -0,0 +1,121 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package glfwutil connects glfw windows to the fonts of the GL packages.  Texts are
+// projected in framebuffer pixels so that glyphs stay sharp on high density displays,
+// where the framebuffer is larger than the window, and cursor positions reported in
+// window coordinates are converted to match.
+//
+//	window := glfwutil.Attach(w, font)
+//	window.OnResize = func(width, height int) {
+//		gl.Viewport(0, 0, int32(width), int32(height))
+//	}
+//	...
+//	if text.Contains(window.CursorPosition()) {
+package glfwutil
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Resizer is implemented by the fonts of every GL package.
+type Resizer interface {
+	ResizeWindow(width float32, height float32)
+}
+
+// Window resizes its fonts whenever the framebuffer of the glfw window changes size,
+// including when the window moves to a display of another density.
+type Window struct {
+	*glfw.Window
+
+	// OnResize is called with the size of the framebuffer once the fonts were resized,
+	// EG to update the gl viewport
+	OnResize func(width, height int)
+
+	fonts    []Resizer
+	previous glfw.FramebufferSizeCallback
+}
+
+// Attach sets the framebuffer size callback of w, calling the one it replaces as well,
+// and resizes fonts to the current framebuffer
+func Attach(w *glfw.Window, fonts ...Resizer) *Window {
+	window := &Window{Window: w, fonts: fonts}
+	window.previous = w.SetFramebufferSizeCallback(window.resized)
+	width, height := w.GetFramebufferSize()
+	for _, f := range fonts {
+		f.ResizeWindow(float32(width), float32(height))
+	}
+	return window
+}
+
+// Add resizes f along with the other fonts of the window
+func (w *Window) Add(f Resizer) {
+	width, height := w.GetFramebufferSize()
+	f.ResizeWindow(float32(width), float32(height))
+	w.fonts = append(w.fonts, f)
+}
+
+func (w *Window) resized(window *glfw.Window, width, height int) {
+	for _, f := range w.fonts {
+		f.ResizeWindow(float32(width), float32(height))
+	}
+	if w.OnResize != nil {
+		w.OnResize(width, height)
+	}
+	if w.previous != nil {
+		w.previous(window, width, height)
+	}
+}
+
+// ContentScale returns the number of framebuffer pixels per window coordinate
+func (w *Window) ContentScale() (x, y float32) {
+	width, height := w.GetSize()
+	fbWidth, fbHeight := w.GetFramebufferSize()
+	return contentScale(width, height, fbWidth, fbHeight)
+}
+
+// Ortho returns the projection fonts use for the framebuffer of the window
+func (w *Window) Ortho() mgl32.Mat4 {
+	return Ortho(w.GetFramebufferSize())
+}
+
+// CursorPosition returns the position of the cursor in the coordinates of
+// Text.SetPosition, ready for hit testing
+func (w *Window) CursorPosition() mgl32.Vec2 {
+	x, y := w.GetCursorPos()
+	return w.TextPosition(x, y)
+}
+
+// TextPosition converts a position in window coordinates, as given to cursor callbacks,
+// to the coordinates of Text.SetPosition
+func (w *Window) TextPosition(x, y float64) mgl32.Vec2 {
+	width, height := w.GetSize()
+	fbWidth, fbHeight := w.GetFramebufferSize()
+	return textPosition(x, y, width, height, fbWidth, fbHeight)
+}
+
+// Ortho returns the projection of Font.ResizeWindow for a framebuffer of the given size:
+// pixels away from its center
+func Ortho(width, height int) mgl32.Mat4 {
+	w, h := float32(width), float32(height)
+	return mgl32.Ortho2D(-w/2, w/2, -h/2, h/2)
+}
+
+func contentScale(width, height, fbWidth, fbHeight int) (x, y float32) {
+	if width == 0 || height == 0 {
+		return 1, 1
+	}
+	return float32(fbWidth) / float32(width), float32(fbHeight) / float32(height)
+}
+
+// textPosition converts window coordinates from the top left corner to framebuffer
+// pixels from the center, y growing upwards
+func textPosition(x, y float64, width, height, fbWidth, fbHeight int) mgl32.Vec2 {
+	sx, sy := contentScale(width, height, fbWidth, fbHeight)
+	return mgl32.Vec2{
+		float32(x)*sx - float32(fbWidth)/2,
+		float32(fbHeight)/2 - float32(y)*sy,
+	}
+}
@@ -0,0 +1,27 @@
+package glfwutil
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestTextPosition(t *testing.T) {
+	// a window of 400x300 with a framebuffer twice as dense
+	if x, y := contentScale(400, 300, 800, 600); x != 2 || y != 2 {
+		t.Error("Bad content scale", x, y)
+	}
+	if x, y := contentScale(0, 0, 0, 0); x != 1 || y != 1 {
+		t.Error("Expecting minimized windows to be unscaled", x, y)
+	}
+	for _, c := range []struct {
+		x, y     float64
+		expected mgl32.Vec2
+	}{{0, 0, mgl32.Vec2{-400, 300}}, {200, 150, mgl32.Vec2{0, 0}}, {400, 300, mgl32.Vec2{400, -300}}} {
+		if p := textPosition(c.x, c.y, 400, 300, 800, 600); p != c.expected {
+			t.Errorf("Expecting (%v, %v) to be at %v, got %v", c.x, c.y, c.expected, p)
+		}
+	}
+	if p := Ortho(800, 600).Mul4x1(mgl32.Vec4{400, -300, 0, 1}); p.X() != 1 || p.Y() != -1 {
+		t.Error("Expecting the corners of the framebuffer to be projected to the edges", p)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+// Highlighter colors source code by turning its tokens into style runs.  See the
+// README for an implementation based on github.com/alecthomas/chroma.
+type Highlighter interface {
+	Highlight(source string) StyleRuns
+}
+
+// HighlighterFunc allows a function to be used as a Highlighter.
+type HighlighterFunc func(source string) StyleRuns
+
+func (f HighlighterFunc) Highlight(source string) StyleRuns {
+	return f(source)
+}
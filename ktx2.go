@@ -0,0 +1,121 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+)
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// the vulkan formats of the atlas pages that can be read
+const (
+	vkFormatR8Unorm       = 9
+	vkFormatR8G8B8A8Unorm = 37
+)
+
+// DecodeKTX2 reads the first mip level of every array layer of a KTX2 texture as atlas
+// pages.  Pages stored as R8 become white pixels with the stored value as alpha.  Only
+// uncompressed RGBA8 and R8 data can be read; supercompressed payloads, such as Basis
+// Universal, should be transcoded by the asset pipeline first.
+func DecodeKTX2(r io.Reader) ([]*image.NRGBA, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || !bytes.Equal(data[:12], ktx2Identifier) {
+		return nil, errors.New("Not a KTX2 file.")
+	}
+	// the 80 bytes of the header are followed by the 24 bytes of the index of the first level
+	if len(data) < 80+24 {
+		return nil, errors.New("KTX2 header is truncated.")
+	}
+	var header struct {
+		VkFormat, TypeSize, Width, Height, Depth, Layers, Faces, Levels, Supercompression uint32
+	}
+	if err = binary.Read(bytes.NewReader(data[12:48]), binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Supercompression != 0 {
+		return nil, errors.New("Supercompressed KTX2 payloads are not supported.")
+	}
+	if header.Depth > 1 || header.Faces > 1 {
+		return nil, errors.New("KTX2 atlas pages should be 2D textures.")
+	}
+	if header.Width == 0 || header.Height == 0 {
+		return nil, errors.New("KTX2 atlas pages should not be empty.")
+	}
+	bytesPerPixel := 0
+	switch header.VkFormat {
+	case vkFormatR8Unorm:
+		bytesPerPixel = 1
+	case vkFormatR8G8B8A8Unorm:
+		bytesPerPixel = 4
+	default:
+		return nil, fmt.Errorf("unsupported KTX2 format %d", header.VkFormat)
+	}
+
+	// the index of the first level follows the offsets of the data format, key/value and
+	// supercompression sections
+	offset := binary.LittleEndian.Uint64(data[80:88])
+	length := binary.LittleEndian.Uint64(data[88:96])
+	layers := int(header.Layers)
+	if layers == 0 {
+		layers = 1
+	}
+	// offset+length and the size of the layers could overflow on corrupt files
+	pixels := uint64(header.Width) * uint64(header.Height)
+	if offset > uint64(len(data)) || length > uint64(len(data))-offset ||
+		pixels > length || pixels*uint64(bytesPerPixel)*uint64(layers) > length {
+		return nil, errors.New("KTX2 level data is truncated.")
+	}
+	width, height := int(header.Width), int(header.Height)
+	size := int(pixels) * bytesPerPixel
+
+	pages := make([]*image.NRGBA, layers)
+	for i := range pages {
+		pix := data[offset+uint64(i*size) : offset+uint64((i+1)*size)]
+		page := image.NewNRGBA(image.Rect(0, 0, width, height))
+		if bytesPerPixel == 4 {
+			copy(page.Pix, pix)
+		} else {
+			for j, a := range pix {
+				copy(page.Pix[4*j:], []byte{255, 255, 255, a})
+			}
+		}
+		pages[i] = page
+	}
+	return pages, nil
+}
+
+// LoadKTX2 reads the JSON encoded font configuration from config, the metrics sidecar
+// written by Save, and its atlas pages from the array layers of a KTX2 texture.
+func (fc *FontConfig) LoadKTX2(config, atlas io.Reader) error {
+	name := fc.Name
+	if err := json.NewDecoder(config).Decode(fc); err != nil {
+		return err
+	}
+	fc.Name = name
+	pages, err := DecodeKTX2(atlas)
+	if err != nil {
+		return err
+	}
+	if fc.PageCount > 1 && len(pages) != fc.PageCount {
+		return fmt.Errorf("expecting %d atlas pages, found %d", fc.PageCount, len(pages))
+	}
+	fc.Image, fc.Pages = pages[0], nil
+	if len(pages) > 1 {
+		fc.Pages = pages
+	}
+	fc.Glyphs.Scale(1)
+	return nil
+}
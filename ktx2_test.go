@@ -0,0 +1,81 @@
+package gltext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// ktx2 builds a texture of the given vulkan format holding one level of layers
+func ktx2(format, width, height uint32, supercompression uint32, layers ...[]byte) []byte {
+	var b bytes.Buffer
+	b.Write(ktx2Identifier)
+	binary.Write(&b, binary.LittleEndian, []uint32{format, 1, width, height, 0, uint32(len(layers)), 1, 1, supercompression})
+	binary.Write(&b, binary.LittleEndian, []uint32{0, 0, 0, 0})
+	binary.Write(&b, binary.LittleEndian, []uint64{0, 0})
+	level := bytes.Join(layers, nil)
+	binary.Write(&b, binary.LittleEndian, []uint64{104, uint64(len(level)), uint64(len(level))})
+	b.Write(level)
+	return b.Bytes()
+}
+
+func TestDecodeKTX2(t *testing.T) {
+	data := ktx2(vkFormatR8Unorm, 2, 1, 0, []byte{10, 20}, []byte{30, 40})
+	pages, err := DecodeKTX2(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 || pages[1].Bounds().Dx() != 2 {
+		t.Fatal("Bad pages", len(pages))
+	}
+	if c := pages[1].NRGBAAt(1, 0); c.R != 255 || c.A != 40 {
+		t.Error("Bad pixel", c)
+	}
+
+	data = ktx2(vkFormatR8G8B8A8Unorm, 1, 1, 0, []byte{1, 2, 3, 4})
+	if pages, err = DecodeKTX2(bytes.NewReader(data)); err != nil || pages[0].NRGBAAt(0, 0).B != 3 {
+		t.Error("Bad rgba page", err)
+	}
+
+	if _, err = DecodeKTX2(bytes.NewReader(ktx2(vkFormatR8Unorm, 1, 1, 1, []byte{1}))); err == nil {
+		t.Error("Expecting supercompression to be rejected")
+	}
+	if _, err = DecodeKTX2(bytes.NewReader(ktx2(vkFormatR8Unorm, 2, 2, 0, []byte{1}))); err == nil {
+		t.Error("Expecting truncated data to be rejected")
+	}
+	if _, err = DecodeKTX2(bytes.NewReader(data[:90])); err == nil {
+		t.Error("Expecting a truncated level index to be rejected")
+	}
+	if _, err = DecodeKTX2(bytes.NewReader(ktx2(vkFormatR8Unorm, 0, 1, 0, []byte{1}))); err == nil {
+		t.Error("Expecting empty pages to be rejected")
+	}
+	data = ktx2(vkFormatR8Unorm, 1, 1, 0, []byte{1})
+	binary.LittleEndian.PutUint64(data[80:], ^uint64(0)-50)
+	if _, err = DecodeKTX2(bytes.NewReader(data)); err == nil {
+		t.Error("Expecting an overflowing level offset to be rejected")
+	}
+	data = ktx2(vkFormatR8G8B8A8Unorm, 1<<31, 1<<31, 0, []byte{1, 2, 3, 4})
+	binary.LittleEndian.PutUint64(data[88:], 1<<62)
+	if _, err = DecodeKTX2(bytes.NewReader(data)); err == nil {
+		t.Error("Expecting a level larger than the file to be rejected")
+	}
+}
+
+func TestLoadKTX2(t *testing.T) {
+	config := `{"RuneRanges":[{"Low":97,"High":98}],"Glyphs":[{"X":0,"Y":0,"Width":1,"Height":1,"Advance":1}],"PageCount":2}`
+	data := ktx2(vkFormatR8Unorm, 1, 1, 0, []byte{10}, []byte{20})
+
+	fc := &FontConfig{Name: "atlas"}
+	if err := fc.LoadKTX2(strings.NewReader(config), bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Name != "atlas" || len(fc.Pages) != 2 || fc.Image != fc.Pages[0] || len(fc.Glyphs) != 1 {
+		t.Error("Bad config", fc.Name, len(fc.Pages), len(fc.Glyphs))
+	}
+
+	data = ktx2(vkFormatR8Unorm, 1, 1, 0, []byte{10})
+	if err := (&FontConfig{}).LoadKTX2(strings.NewReader(config), bytes.NewReader(data)); err == nil {
+		t.Error("Expecting the page count to be checked")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+// DefaultCacheSize is the number of layouts kept by a Cache whose Size is zero.
+const DefaultCacheSize = 256
+
+// Cache memoizes layouts so that strings laid out again with the same face and
+// options, as is common when drawing in immediate mode, cost a map lookup.  When
+// full, the oldest layout is dropped.  Layouts using Options.Face are not cached.
+// The zero value is ready to use.  A Cache is not safe for concurrent use.
+type Cache struct {
+	Size int
+
+	entries map[cacheKey][]Glyph
+	order   []cacheKey // keys in insertion order, used as a ring
+	next    int
+}
+
+type cacheKey struct {
+	face Face
+	text string
+
+	lineHeight, paragraphSpacing, firstLineIndent  float32
+	maxWidth, hangingIndent, tabWidth, cellAdvance float32
+	align                                          Align
+	showWhitespace                                 bool
+}
+
+// Layout returns the glyphs of Layout(face, text, opts), which may be shared with
+// previous calls and should not be modified.
+func (c *Cache) Layout(face Face, text []rune, opts Options) []Glyph {
+	if opts.Face != nil {
+		return Layout(face, text, opts)
+	}
+	key := cacheKey{
+		face: face, text: string(text),
+		lineHeight: opts.LineHeight, paragraphSpacing: opts.ParagraphSpacing, firstLineIndent: opts.FirstLineIndent,
+		maxWidth: opts.MaxWidth, hangingIndent: opts.HangingIndent, tabWidth: opts.TabWidth, cellAdvance: opts.CellAdvance,
+		align: opts.Align, showWhitespace: opts.ShowWhitespace,
+	}
+	if placed, ok := c.entries[key]; ok {
+		return placed
+	}
+	placed := Layout(face, text, opts)
+
+	size := c.Size
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	if c.entries == nil {
+		c.entries = map[cacheKey][]Glyph{}
+	}
+	if len(c.order) < size {
+		c.order = append(c.order, key)
+	} else {
+		delete(c.entries, c.order[c.next])
+		c.order[c.next] = key
+		c.next = (c.next + 1) % len(c.order)
+	}
+	c.entries[key] = placed
+	return placed
+}
+
+// Len returns the number of layouts cached.
+func (c *Cache) Len() int {
+	return len(c.entries)
+}
+
+// Reset drops every layout, which is needed when the metrics of a face change.
+func (c *Cache) Reset() {
+	c.entries, c.order, c.next = nil, nil, 0
+}
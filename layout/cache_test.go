@@ -0,0 +1,31 @@
+package layout
+
+import (
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := &Cache{Size: 2}
+	opts := Options{LineHeight: 20}
+	first := c.Layout(testFace(10), []rune("ab"), opts)
+	if again := c.Layout(testFace(10), []rune("ab"), opts); &again[0] != &first[0] {
+		t.Error("Expecting the layout to be cached")
+	}
+	if other := c.Layout(testFace(10), []rune("ab"), Options{LineHeight: 20, MaxWidth: 5}); &other[0] == &first[0] {
+		t.Error("Expecting options to be part of the key")
+	}
+	c.Layout(testFace(12), []rune("ab"), opts)
+	if c.Len() != 2 {
+		t.Fatal("Expecting the oldest layout to be dropped", c.Len())
+	}
+	if again := c.Layout(testFace(10), []rune("ab"), opts); &again[0] == &first[0] {
+		t.Error("Expecting the first layout to have been dropped")
+	}
+
+	c.Reset()
+	faces := Options{LineHeight: 20, Face: func(int) Face { return nil }}
+	c.Layout(testFace(10), []rune("ab"), faces)
+	if c.Len() != 0 {
+		t.Error("Expecting layouts using faces not to be cached")
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package layout breaks strings into lines and positions their glyphs.  It does not
+// depend on OpenGL, so text can be measured and laid out headless, EG in tests or on
+// a server, with the same results as the GL packages that draw it.
+package layout
+
+import (
+	"sort"
+)
+
+// Face provides the metrics of the glyphs being laid out.  gltext.FontConfig
+// implements this interface.
+type Face interface {
+	// Glyph returns the index of the glyph of r within the face and its advance.  ok is
+	// false when the face does not cover r.
+	Glyph(r rune) (index int, advance float32, ok bool)
+}
+
+// Options determine how a string is broken into lines and paragraphs.
+type Options struct {
+	// LineHeight is the vertical distance between two consecutive lines.
+	LineHeight float32
+
+	// ParagraphSpacing is the extra vertical space placed between paragraphs.
+	// Paragraphs are separated by one or more blank lines.  When this value is
+	// set the blank lines are collapsed and only the spacing separates the
+	// paragraphs.  When it is zero blank lines are laid out as empty lines.
+	ParagraphSpacing float32
+
+	// FirstLineIndent shifts the first line of every paragraph to the right.
+	FirstLineIndent float32
+
+	// MaxWidth wraps lines at word boundaries before they become wider than this value.
+	// Words that do not fit on a line of their own are broken between runes.
+	// Zero disables wrapping.
+	MaxWidth float32
+
+	// HangingIndent is where lines that were wrapped begin.
+	HangingIndent float32
+
+	// TabWidth is the distance between tab stops.  It defaults to four spaces.
+	TabWidth float32
+
+	// CellAdvance, when set, replaces the advance of every glyph so that monospace
+	// columns line up whatever the metrics of the glyphs.
+	CellAdvance float32
+
+	// Align places every line within the width of the block, which is MaxWidth when
+	// wrapping and the width of the widest line otherwise.
+	Align Align
+
+	// ShowWhitespace places the glyph of SpaceSymbol over every space, that of TabSymbol
+	// at the start of every tab and that of NewlineSymbol at the end of every line ended by
+	// a newline, when the face covers them.  Spaces keep their advance.
+	ShowWhitespace bool
+
+	// Face returns the face holding the glyph of the rune at index i, allowing runs of
+	// text to use other faces of a family.  When nil, or when it returns nil, the face
+	// passed to Layout is used.  Glyph indices of the resulting Glyphs refer to that face.
+	Face func(i int) Face
+}
+
+// The symbols drawn for whitespace when Options.ShowWhitespace is set
+var (
+	SpaceSymbol   = '·'
+	TabSymbol     = '→'
+	NewlineSymbol = '¶'
+)
+
+// Align determines where each line is placed within the width of the laid out block.
+type Align int
+
+const (
+	AlignLeft    Align = iota
+	AlignCenter        // lines are centered
+	AlignRight         // the end of every line lines up
+	AlignJustify       // the spaces of wrapped lines are widened so that both of their ends line up
+)
+
+// Glyph is a single glyph that has been positioned by Layout.
+type Glyph struct {
+	Index int  // Index of the rune within the laid out text.
+	Rune  rune // The rune being drawn.
+	Glyph int  // Index of the glyph within its face.
+	Line  int  // Line on which the glyph appears, starting at zero.
+
+	// X, Y: the lower left corner of the glyph quad.  The first line sits on y = 0
+	// and each following line is placed below the previous one.
+	X float32
+	Y float32
+}
+
+// Layout positions each rune of text using the glyph metrics of face.
+// Runes that are not covered by the face are skipped.  Newlines start a new line
+// and tabs move to the next tab stop.
+func Layout(face Face, text []rune, opts Options) []Glyph {
+	l := &layouter{
+		face:           face,
+		text:           text,
+		opts:           opts,
+		placed:         make([]Glyph, 0, len(text)),
+		line:           -1,
+		paragraphStart: true,
+		wrapped:        map[int]bool{},
+	}
+	l.lines(0)
+	if opts.Align != AlignLeft {
+		align(face, text, l.placed, opts, l.wrapped)
+	}
+	return l.placed
+}
+
+// Relayout returns the layout of text given placed, the layout with the same face and
+// options of a text sharing its first from runes.  Only the lines following the last
+// glyph placed before the word holding rune from are laid out again, which spares
+// laying out the whole text when its end changes.  placed is not modified.  Aligned
+// layouts move the glyphs of every line and are laid out again entirely.
+func Relayout(face Face, text []rune, from int, placed []Glyph, opts Options) []Glyph {
+	if from > len(text) {
+		from = len(text)
+	}
+	if opts.Align != AlignLeft || from <= 0 {
+		return Layout(face, text, opts)
+	}
+	lineStart := from
+	for lineStart > 0 && text[lineStart-1] != '\n' {
+		lineStart--
+	}
+	// wrapping depends on the whole word holding rune from
+	wordStart := from
+	for wordStart > lineStart && !isSpace(text[wordStart-1]) {
+		wordStart--
+	}
+
+	kept := sort.Search(len(placed), func(i int) bool { return placed[i].Index >= wordStart })
+	if kept == 0 {
+		return Layout(face, text, opts)
+	}
+	last := placed[kept-1]
+	l := &layouter{
+		face:    face,
+		text:    text,
+		opts:    opts,
+		placed:  append(make([]Glyph, 0, len(text)), placed[:kept]...),
+		line:    last.Line,
+		y:       last.Y,
+		wrapped: map[int]bool{},
+	}
+	if last.Index < lineStart {
+		// continue after the line of the last glyph
+		start := last.Index
+		for text[start] != '\n' {
+			start++
+		}
+		l.lines(start + 1)
+		return l.placed
+	}
+
+	// continue within the line of the last glyph, after the runes that were not placed
+	l.lineStart = kept - 1
+	for l.lineStart > 0 && l.placed[l.lineStart-1].Line == last.Line {
+		l.lineStart--
+	}
+	if text[last.Index] == '\t' {
+		l.x = TabStop(face, last.X, opts)
+	} else {
+		_, a, _ := glyphAt(face, text, last.Index, opts)
+		l.x = last.X + a
+	}
+	for i := last.Index + 1; i < wordStart; i++ {
+		if text[i] == '\t' {
+			l.x = TabStop(face, l.x, opts)
+		}
+	}
+	end := wordStart
+	for end < len(text) && text[end] != '\n' {
+		end++
+	}
+	l.runes(wordStart, end)
+	l.lines(end + 1)
+	return l.placed
+}
+
+// layouter holds the position reached while laying out a text
+type layouter struct {
+	face   Face
+	text   []rune
+	opts   Options
+	placed []Glyph
+
+	line           int
+	x, y           float32
+	gap            float32 // extra space placed above the next line
+	paragraphStart bool
+	lineStart      int          // index within placed of the first glyph of the line
+	wrapped        map[int]bool // lines ended by wrapping rather than by a newline
+}
+
+// lines lays out the lines of the text from start, which begins a line
+func (l *layouter) lines(start int) {
+	text, opts := l.text, l.opts
+	for ; start <= len(text); start++ {
+		end := start
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+
+		if end == start {
+			// a blank line begins a new paragraph
+			l.paragraphStart = true
+			if opts.ParagraphSpacing != 0 {
+				if l.line >= 0 {
+					l.gap = opts.ParagraphSpacing
+				}
+				continue
+			}
+		}
+
+		if l.line >= 0 {
+			l.y -= opts.LineHeight + l.gap
+		}
+		l.line++
+		l.gap = 0
+
+		l.x = 0
+		if l.paragraphStart {
+			l.x = opts.FirstLineIndent
+		}
+		l.lineStart = len(l.placed)
+		l.runes(start, end)
+
+		l.paragraphStart = end == start
+		start = end
+	}
+}
+
+// runes places the runes of the line from i up to end, where the line ends
+func (l *layouter) runes(i, end int) {
+	face, text, opts := l.face, l.text, l.opts
+	for i < end {
+		// keep words together when wrapping
+		j := i + 1
+		if !isSpace(text[i]) {
+			for j < end && !isSpace(text[j]) {
+				j++
+			}
+			if opts.MaxWidth > 0 && len(l.placed) > l.lineStart && l.x+advance(face, text, i, j, opts) > opts.MaxWidth {
+				l.wrap()
+			}
+		}
+		for ; i < j; i++ {
+			if text[i] == '\t' {
+				if opts.ShowWhitespace {
+					l.placed = placeSymbol(l.placed, face, TabSymbol, Glyph{Index: i, Rune: text[i], Line: l.line, X: l.x, Y: l.y}, opts)
+				}
+				l.x = TabStop(face, l.x, opts)
+				continue
+			}
+			glyphIndex, a, ok := glyphAt(face, text, i, opts)
+			if !ok {
+				continue
+			}
+			if opts.ShowWhitespace && text[i] == ' ' {
+				if symbol, _, ok := faceAt(face, i, opts).Glyph(SpaceSymbol); ok {
+					glyphIndex = symbol
+				}
+			}
+			if opts.MaxWidth > 0 && len(l.placed) > l.lineStart && l.x+a > opts.MaxWidth && !isSpace(text[i]) {
+				l.wrap()
+			}
+			l.placed = append(l.placed, Glyph{Index: i, Rune: text[i], Glyph: glyphIndex, Line: l.line, X: l.x, Y: l.y})
+			l.x += a
+		}
+	}
+	if opts.ShowWhitespace && end < len(text) {
+		l.placed = placeSymbol(l.placed, face, NewlineSymbol, Glyph{Index: end, Rune: text[end], Line: l.line, X: l.x, Y: l.y}, opts)
+	}
+}
+
+// wrap moves the pen to the start of a new line
+func (l *layouter) wrap() {
+	l.wrapped[l.line] = true
+	l.line++
+	l.y -= l.opts.LineHeight
+	l.x = l.opts.HangingIndent
+	l.lineStart = len(l.placed)
+}
+
+// TabStop returns the first tab stop after x, tab stops being opts.TabWidth, or four
+// spaces of face, apart.
+func TabStop(face Face, x float32, opts Options) float32 {
+	tabWidth := opts.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = 4 * advance(face, []rune{' '}, 0, 1, Options{CellAdvance: opts.CellAdvance})
+		if tabWidth <= 0 {
+			tabWidth = 1
+		}
+	}
+	return (float32(int(x/tabWidth)) + 1) * tabWidth
+}
+
+// placeSymbol appends g drawn with the glyph of symbol when the face of g covers it
+func placeSymbol(placed []Glyph, face Face, symbol rune, g Glyph, opts Options) []Glyph {
+	if index, _, ok := faceAt(face, g.Index, opts).Glyph(symbol); ok {
+		g.Glyph = index
+		placed = append(placed, g)
+	}
+	return placed
+}
+
+// align moves the glyphs of every line, which are in order, according to opts.Align
+func align(face Face, text []rune, placed []Glyph, opts Options, wrapped map[int]bool) {
+	// the end of a line is that of its last glyph that is not a space
+	type extent struct {
+		first, last int // indices within placed
+		end         float32
+		spaces      int
+	}
+	lines := []extent{}
+	for i, g := range placed {
+		if i == 0 || g.Line != placed[i-1].Line {
+			lines = append(lines, extent{first: i, last: i - 1})
+		}
+		l := &lines[len(lines)-1]
+		if !isSpace(g.Rune) {
+			_, a, _ := glyphAt(face, text, g.Index, opts)
+			l.end, l.last = g.X+a, i
+		}
+	}
+	width := opts.MaxWidth
+	if width <= 0 {
+		for _, l := range lines {
+			if l.end > width {
+				width = l.end
+			}
+		}
+	}
+
+	for _, l := range lines {
+		lineNumber := placed[l.first].Line
+		if opts.Align == AlignJustify {
+			if !wrapped[lineNumber] {
+				continue
+			}
+			// count the spaces between the first and last glyphs that are not spaces
+			spaces, started := 0, false
+			for i := l.first; i <= l.last; i++ {
+				if !isSpace(placed[i].Rune) {
+					started = true
+				} else if started {
+					spaces++
+				}
+			}
+			if spaces == 0 {
+				continue
+			}
+			extra := (width - l.end) / float32(spaces)
+			shift, started := float32(0), false
+			for i := l.first; i < len(placed) && placed[i].Line == lineNumber; i++ {
+				placed[i].X += shift
+				if !isSpace(placed[i].Rune) {
+					started = true
+				} else if started && i <= l.last {
+					shift += extra
+				}
+			}
+			continue
+		}
+		shift := width - l.end
+		if opts.Align == AlignCenter {
+			shift /= 2
+		}
+		for i := l.first; i < len(placed) && placed[i].Line == lineNumber; i++ {
+			placed[i].X += shift
+		}
+	}
+}
+
+// Advance returns the total advance of the runes of text covered by face.
+func Advance(face Face, text []rune) float32 {
+	return advance(face, text, 0, len(text), Options{})
+}
+
+// advance returns the total advance of the runes from i up to j covered by their faces
+func advance(face Face, text []rune, i, j int, opts Options) (width float32) {
+	for ; i < j; i++ {
+		if _, a, ok := glyphAt(face, text, i, opts); ok {
+			width += a
+		}
+	}
+	return
+}
+
+// glyphAt returns the glyph of the rune at index i and its advance
+func glyphAt(face Face, text []rune, i int, opts Options) (int, float32, bool) {
+	index, a, ok := faceAt(face, i, opts).Glyph(text[i])
+	if ok && opts.CellAdvance > 0 {
+		a = opts.CellAdvance
+	}
+	return index, a, ok
+}
+
+// faceAt returns the face holding the glyph of the rune at index i
+func faceAt(face Face, i int, opts Options) Face {
+	if opts.Face != nil {
+		if f := opts.Face(i); f != nil {
+			return f
+		}
+	}
+	return face
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
@@ -0,0 +1,183 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testFace covers ASCII with glyphs of the same advance
+type testFace float32
+
+func (f testFace) Glyph(r rune) (int, float32, bool) {
+	if r < 32 || r > 127 {
+		return 0, 0, false
+	}
+	return int(r - 32), float32(f), true
+}
+
+func TestLayoutParagraphs(t *testing.T) {
+	face := testFace(10)
+	opts := Options{LineHeight: 20, ParagraphSpacing: 5, FirstLineIndent: 15}
+
+	placed := Layout(face, []rune("ab\ncd\n\n\nef"), opts)
+	if len(placed) != 6 {
+		t.Fatal("Expecting 6 glyphs", len(placed))
+	}
+	// first line of the first paragraph is indented
+	if placed[0].X != 15 || placed[0].Y != 0 || placed[1].X != 25 {
+		t.Error("Bad first line", placed[0], placed[1])
+	}
+	// a single newline continues the paragraph
+	if placed[2].X != 0 || placed[2].Y != -20 || placed[2].Line != 1 {
+		t.Error("Bad second line", placed[2])
+	}
+	// blank lines are collapsed into the paragraph spacing
+	if placed[4].X != 15 || placed[4].Y != -45 || placed[4].Line != 2 {
+		t.Error("Bad second paragraph", placed[4])
+	}
+	if placed[4].Index != 8 {
+		t.Error("Bad rune index", placed[4].Index)
+	}
+}
+
+func TestLayoutBlankLines(t *testing.T) {
+	face := testFace(10)
+	opts := Options{LineHeight: 20}
+
+	placed := Layout(face, []rune("ab\n\ncd"), opts)
+	if placed[2].Y != -40 || placed[2].Line != 2 {
+		t.Error("Expecting an empty line", placed[2])
+	}
+}
+
+func TestLayoutWrapping(t *testing.T) {
+	face := testFace(10)
+	opts := Options{LineHeight: 20, MaxWidth: 80, HangingIndent: 20, TabWidth: 20}
+
+	// "-" sits in the gutter, the words wrap to the tab stop
+	placed := Layout(face, []rune("-\tab cd ef"), opts)
+	if placed[1].X != 20 || placed[1].Line != 0 {
+		t.Error("Expecting tab stop", placed[1])
+	}
+	// "ab cd" fills the first line so "ef" wraps and hangs
+	last := placed[len(placed)-1]
+	if last.Rune != 'f' || last.Line != 1 || last.X != 30 || last.Y != -20 {
+		t.Error("Expecting hanging indent", last)
+	}
+
+	// a word wider than the line is broken between runes
+	placed = Layout(face, []rune("abcdefgh"), Options{LineHeight: 20, MaxWidth: 50})
+	if placed[5].Line != 1 || placed[5].X != 0 {
+		t.Error("Expecting a broken word", placed[5])
+	}
+}
+
+func TestRelayout(t *testing.T) {
+	face := testFace(10)
+	texts := []string{
+		"ab cd ef\ngh",
+		"a\tb\n\n\ncd ef gh ij",
+		"abcdefgh ij\n",
+		"ab \u00e9\tcd efgh",
+	}
+	options := []Options{
+		{LineHeight: 20},
+		{LineHeight: 20, ParagraphSpacing: 5, FirstLineIndent: 15},
+		{LineHeight: 20, MaxWidth: 50, HangingIndent: 10, TabWidth: 20},
+		{LineHeight: 20, MaxWidth: 40, ShowWhitespace: true},
+	}
+	for _, opts := range options {
+		for _, text := range texts {
+			runes := []rune(text)
+			for cut := 0; cut <= len(runes); cut++ {
+				// the previous text shares the first cut runes and ends differently
+				previous := append(append([]rune{}, runes[:cut]...), []rune("xyz w\n")...)
+				placed := Layout(face, previous, opts)
+				kept := append([]Glyph{}, placed...)
+				got := Relayout(face, runes, cut, placed, opts)
+				if want := Layout(face, runes, opts); !reflect.DeepEqual(got, want) {
+					t.Fatalf("Bad layout of %q from %d with %+v: %v, expecting %v", text, cut, opts, got, want)
+				}
+				if !reflect.DeepEqual(placed, kept) {
+					t.Fatal("Expecting the previous layout to be kept", placed)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutFaces(t *testing.T) {
+	face := testFace(10)
+	bold := testFace(12)
+
+	placed := Layout(face, []rune("abcd"), Options{LineHeight: 20, Face: func(i int) Face {
+		if i == 1 || i == 2 {
+			return bold
+		}
+		return nil
+	}})
+	if placed[1].X != 10 || placed[2].X != 22 || placed[3].X != 34 {
+		t.Error("Bad bold advances", placed)
+	}
+}
+
+func TestLayoutCellAdvance(t *testing.T) {
+	placed := Layout(testFace(7), []rune("ab\tc"), Options{LineHeight: 20, CellAdvance: 10})
+	if placed[1].X != 10 || placed[2].X != 40 {
+		t.Error("Expecting glyphs and tab stops to use the cell advance", placed)
+	}
+}
+
+func TestLayoutAlign(t *testing.T) {
+	face := testFace(10)
+	opts := Options{LineHeight: 20, Align: AlignRight}
+
+	// every line ends where the widest one does
+	placed := Layout(face, []rune("ab\nabcd"), opts)
+	if placed[0].X != 20 || placed[2].X != 0 {
+		t.Error("Bad right alignment", placed[0], placed[2])
+	}
+	opts.Align = AlignCenter
+	if placed = Layout(face, []rune("ab\nabcd"), opts); placed[0].X != 10 {
+		t.Error("Bad centering", placed[0])
+	}
+
+	// trailing spaces do not count and lines align within MaxWidth when wrapping
+	opts = Options{LineHeight: 20, MaxWidth: 50, Align: AlignRight}
+	if placed = Layout(face, []rune("aa b cc"), opts); placed[0].X != 10 || placed[5].X != 30 {
+		t.Error("Bad wrapped right alignment", placed[0], placed[5])
+	}
+
+	// wrapped lines are justified, the last line of a paragraph is not
+	opts.Align = AlignJustify
+	placed = Layout(face, []rune("aa b cc"), opts)
+	if placed[0].X != 0 || placed[3].X != 40 || placed[5].X != 0 || placed[5].Line != 1 {
+		t.Error("Bad justification", placed)
+	}
+}
+
+func TestLayoutShowWhitespace(t *testing.T) {
+	defer func(space, tab, newline rune) {
+		SpaceSymbol, TabSymbol, NewlineSymbol = space, tab, newline
+	}(SpaceSymbol, TabSymbol, NewlineSymbol)
+	SpaceSymbol, TabSymbol, NewlineSymbol = '.', '>', '$'
+
+	face := testFace(10)
+	text := []rune("a b\tc\nd")
+	if placed := Layout(face, text, Options{LineHeight: 20}); len(placed) != 5 {
+		t.Fatal("Expecting tabs and newlines to be skipped", placed)
+	}
+	placed := Layout(face, text, Options{LineHeight: 20, ShowWhitespace: true})
+	if len(placed) != 7 {
+		t.Fatal("Expecting a glyph for every rune", placed)
+	}
+	if placed[1].Rune != ' ' || placed[1].Glyph != int('.'-32) || placed[1].X != 10 {
+		t.Error("Bad space", placed[1])
+	}
+	if placed[3].Rune != '\t' || placed[3].Glyph != int('>'-32) || placed[3].X != 30 || placed[4].X != 40 {
+		t.Error("Bad tab", placed[3], placed[4])
+	}
+	if placed[5].Rune != '\n' || placed[5].Glyph != int('$'-32) || placed[5].X != 50 || placed[5].Line != 0 || placed[6].Line != 1 {
+		t.Error("Bad newline", placed[5], placed[6])
+	}
+}
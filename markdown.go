@@ -0,0 +1,156 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"image/color"
+	"strings"
+	"unicode"
+)
+
+// MarkdownStyle determines how the elements of markdown text are displayed.
+type MarkdownStyle struct {
+	// HeadingSizes scales headings from level 1 to 6.  Headings are also bold.
+	HeadingSizes [6]float32
+
+	// CodeColor and LinkColor are applied to inline code and links when set
+	CodeColor color.Color
+	LinkColor color.Color
+
+	// Bullet replaces the marker of unordered list items when set
+	Bullet rune
+}
+
+// DefaultMarkdownStyle enlarges headings and colors code and links.
+var DefaultMarkdownStyle = MarkdownStyle{
+	HeadingSizes: [6]float32{2, 1.5, 1.25, 1, 1, 1},
+	CodeColor:    color.NRGBA{R: 200, G: 120, B: 60, A: 255},
+	LinkColor:    color.NRGBA{R: 80, G: 140, B: 230, A: 255},
+}
+
+// ParseMarkdown extracts the styles and links of a subset of markdown: # headings,
+// **bold** and *italic* emphasis (or their underscore forms), `inline code`,
+// [links](address) and unordered list items starting with -, * or +.  Other lines
+// are kept as they are, blank lines separating paragraphs.  Backslashes escape
+// punctuation.
+func ParseMarkdown(s string, style MarkdownStyle) Markup {
+	var m Markup
+	var text []rune
+	for n, line := range strings.Split(s, "\n") {
+		if n > 0 {
+			text = append(text, '\n')
+		}
+		start := len(text)
+		level := headingLevel(line)
+		if level > 0 {
+			line = strings.TrimSpace(line[level:])
+		} else if indent, marker, item, ok := listItem(line); ok {
+			// the marker is not mistaken for emphasis
+			if style.Bullet != 0 {
+				marker = string(style.Bullet)
+			}
+			text = append(text, []rune(indent+marker+" ")...)
+			line = item
+		}
+		text = parseMarkdownInline(&m, text, []rune(line), style)
+		if level > 0 {
+			m.Runs = append(m.Runs, StyleRun{Start: start, End: len(text), Style: StyleBold, Size: style.HeadingSizes[level-1]})
+		}
+	}
+	m.Text = string(text)
+	return m
+}
+
+// headingLevel returns the number of # starting a heading line, or 0
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// listItem splits an unordered list item into its indentation, marker and text
+func listItem(line string) (indent, marker, item string, ok bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if len(trimmed) < 2 || !strings.ContainsRune("-*+", rune(trimmed[0])) || trimmed[1] != ' ' {
+		return "", "", "", false
+	}
+	return line[:len(line)-len(trimmed)], trimmed[:1], trimmed[2:], true
+}
+
+// parseMarkdownInline appends the text of the inline elements of line to text
+func parseMarkdownInline(m *Markup, text, line []rune, style MarkdownStyle) []rune {
+	for i := 0; i < len(line); i++ {
+		r := line[i]
+		switch {
+		case r == '\\' && i+1 < len(line) && (unicode.IsPunct(line[i+1]) || unicode.IsSymbol(line[i+1])):
+			text = append(text, line[i+1])
+			i++
+			continue
+		case r == '`':
+			if end := indexRunes(line, "`", i+1); end > i+1 {
+				start := len(text)
+				text = append(text, line[i+1:end]...)
+				if style.CodeColor != nil {
+					m.Runs = append(m.Runs, StyleRun{Start: start, End: len(text), Color: style.CodeColor})
+				}
+				i = end
+				continue
+			}
+		case r == '[':
+			if close := indexRunes(line, "](", i+1); close > i+1 {
+				if end := indexRunes(line, ")", close+2); end > 0 {
+					start := len(text)
+					text = parseMarkdownInline(m, text, line[i+1:close], style)
+					m.Links = append(m.Links, Link{Start: start, End: len(text), URL: string(line[close+2 : end])})
+					if style.LinkColor != nil {
+						m.Runs = append(m.Runs, StyleRun{Start: start, End: len(text), Color: style.LinkColor})
+					}
+					i = end
+					continue
+				}
+			}
+		case r == '*' || r == '_':
+			// underscores within words are kept, as in snake_case
+			if r == '_' && i > 0 && (unicode.IsLetter(line[i-1]) || unicode.IsDigit(line[i-1])) {
+				break
+			}
+			delimiter, emphasis := string(r), StyleItalic
+			if i+1 < len(line) && line[i+1] == r {
+				delimiter, emphasis = string([]rune{r, r}), StyleBold
+			}
+			// emphasis neither begins nor ends with a space, as in 2 * 3
+			from := i + len(delimiter)
+			end := indexRunes(line, delimiter, from)
+			for end > from && unicode.IsSpace(line[end-1]) {
+				end = indexRunes(line, delimiter, end+1)
+			}
+			if end > from && !unicode.IsSpace(line[from]) {
+				start := len(text)
+				text = parseMarkdownInline(m, text, line[from:end], style)
+				m.Runs = append(m.Runs, StyleRun{Start: start, End: len(text), Style: emphasis})
+				i = end + len(delimiter) - 1
+				continue
+			}
+		}
+		text = append(text, r)
+	}
+	return text
+}
+
+// indexRunes returns the index of the first occurrence of sub in runes at or after from, or -1
+func indexRunes(runes []rune, sub string, from int) int {
+	s := []rune(sub)
+	for i := from; i+len(s) <= len(runes); i++ {
+		if string(runes[i:i+len(s)]) == sub {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,35 @@
+package gltext
+
+import (
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	style := DefaultMarkdownStyle
+	style.Bullet = '•'
+	m := ParseMarkdown("# Title\nSome **bold** and _it_ in snake_case `code`\n\n- item *one*\n  * [site](http://x.org) \\*", style)
+	expect := "Title\nSome bold and it in snake_case code\n\n• item one\n  • site *"
+	if m.Text != expect {
+		t.Fatalf("Expecting %q got %q", expect, m.Text)
+	}
+	if m.Runs.StyleAt(0) != StyleBold || m.Runs.SizeAt(4) != 2 || m.Runs.SizeAt(6) != 1 {
+		t.Error("Bad heading")
+	}
+	if m.Runs.StyleAt(11) != StyleBold || m.Runs.StyleAt(20) != StyleItalic || m.Runs.StyleAt(30) != StyleRegular {
+		t.Error("Bad emphasis", m.Runs)
+	}
+	if m.Runs.ColorAt(40) != style.CodeColor || m.Runs.StyleAt(52) != StyleItalic {
+		t.Error("Bad code or list item", m.Runs)
+	}
+	if len(m.Links) != 1 || m.Links[0] != (Link{Start: 58, End: 62, URL: "http://x.org"}) || m.Runs.ColorAt(58) != style.LinkColor {
+		t.Error("Bad link", m.Links)
+	}
+	if m.Runs.ColorAt(62) != nil {
+		t.Error("Expecting the link color to end with the link")
+	}
+
+	// unclosed emphasis is kept as text
+	if m := ParseMarkdown("2 * 3 and **x", MarkdownStyle{}); m.Text != "2 * 3 and **x" || len(m.Runs) != 0 {
+		t.Error("Bad text", m.Text, m.Runs)
+	}
+}
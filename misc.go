@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"io"
 	"os"
 )
 
@@ -100,7 +101,13 @@ func LoadImage(path string) (*image.NRGBA, error) {
 	if err != nil {
 		return nil, err
 	}
-	pix, _, err := image.Decode(img)
+	defer img.Close()
+	return DecodeImage(img)
+}
+
+// DecodeImage reads an NRGBA image, such as a font image, from r.
+func DecodeImage(r io.Reader) (*image.NRGBA, error) {
+	pix, _, err := image.Decode(r)
 	if err != nil {
 		return nil, err
 	}
@@ -110,3 +117,17 @@ func LoadImage(path string) (*image.NRGBA, error) {
 	}
 	return nil, errors.New("Not a NRGBA image.")
 }
+
+// PremultiplyAlpha returns a copy of src with the colors multiplied by their alpha,
+// as expected by textures blended with ONE, ONE_MINUS_SRC_ALPHA.
+func PremultiplyAlpha(src *image.NRGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	for i := 0; i < len(src.Pix); i += 4 {
+		a := uint32(src.Pix[i+3])
+		for c := 0; c < 3; c++ {
+			dst.Pix[i+c] = uint8((uint32(src.Pix[i+c])*a + 127) / 255)
+		}
+		dst.Pix[i+3] = src.Pix[i+3]
+	}
+	return dst
+}
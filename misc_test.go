@@ -0,0 +1,20 @@
+package gltext
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPremultiplyAlpha(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{255, 255, 255, 128})
+	src.SetNRGBA(1, 0, color.NRGBA{200, 100, 0, 255})
+
+	dst := PremultiplyAlpha(src)
+	for x, expected := range []color.RGBA{{128, 128, 128, 128}, {200, 100, 0, 255}} {
+		if got := dst.RGBAAt(x, 0); got != expected {
+			t.Errorf("Expecting %v at %d, got %v", expected, x, got)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// OutlineFont provides the vector outlines of the glyphs of a truetype font, for
+// geometry that is not drawn from an atlas such as extruded or tessellated text.
+// Outlines are in pixels with y pointing up and the origin on the baseline.
+type OutlineFont struct {
+	// Tolerance is the largest distance in pixels between a curve and the segments it is
+	// flattened into
+	Tolerance float32
+
+	ttf   *truetype.Font
+	scale fixed.Int26_6
+	buf   truetype.GlyphBuf
+}
+
+// GlyphOutline is the outline of a glyph placed within a string.
+type GlyphOutline struct {
+	Index int  // index of the rune within the string
+	Rune  rune // the rune outlined
+
+	// Contours are closed polygons, the last point connecting back to the first.  Outer
+	// contours wind counter-clockwise and holes clockwise.
+	Contours [][]Point
+}
+
+// Curve is a quadratic Bézier curve from Start to End bending towards Control.
+type Curve struct {
+	Start, Control, End Point
+}
+
+// GlyphCurves is the unflattened outline of a glyph placed within a string.
+type GlyphCurves struct {
+	Index int
+	Rune  rune
+
+	// Polygons join the ends of the segments of every contour, in the winding of the font.
+	// Each curve bulges away from the polygon edge between its Start and End.
+	Polygons [][]Point
+	Curves   []Curve
+}
+
+// NewOutlineFont reads a truetype font whose glyphs are outlined at size pixels per em
+func NewOutlineFont(r io.Reader, size float64) (*OutlineFont, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OutlineFont{Tolerance: 0.25, ttf: ttf, scale: fixed.Int26_6(size * 64)}, nil
+}
+
+// LineHeight returns the distance between the baselines of two lines
+func (f *OutlineFont) LineHeight() float32 {
+	b := f.ttf.Bounds(f.scale)
+	return fixed26(b.Max.Y - b.Min.Y)
+}
+
+// Outlines returns the outline of every glyph of s, placed along lines starting at the
+// origin and moving down by LineHeight at each newline.  Runes without contours, such as
+// spaces, are skipped.
+func (f *OutlineFont) Outlines(s string) []GlyphOutline {
+	outlines := []GlyphOutline{}
+	f.place(s, func(i int, r rune, index truetype.Index, x, y float32) {
+		contours := f.contours(index)
+		for _, contour := range contours {
+			for j := range contour {
+				contour[j].X += x
+				contour[j].Y += y
+			}
+		}
+		if len(contours) > 0 {
+			outlines = append(outlines, GlyphOutline{Index: i, Rune: r, Contours: contours})
+		}
+	})
+	return outlines
+}
+
+// CurveOutlines returns the outlines of the glyphs of s, placed like Outlines, without
+// flattening their curves
+func (f *OutlineFont) CurveOutlines(s string) []GlyphCurves {
+	outlines := []GlyphCurves{}
+	f.place(s, func(i int, r rune, index truetype.Index, x, y float32) {
+		if err := f.buf.Load(f.ttf, f.scale, index, font.HintingNone); err != nil {
+			return
+		}
+		g := GlyphCurves{Index: i, Rune: r}
+		offset := func(p Point) Point { return Point{X: p.X + x, Y: p.Y + y} }
+		start := 0
+		for _, end := range f.buf.Ends {
+			polygon := []Point{}
+			first := walk(f.buf.Points[start:end], func(p Point) {
+				polygon = append(polygon, offset(p))
+			}, func(a, b, c Point) {
+				g.Curves = append(g.Curves, Curve{Start: offset(a), Control: offset(b), End: offset(c)})
+				polygon = append(polygon, offset(c))
+			})
+			if len(polygon) > 0 && polygon[len(polygon)-1] == offset(first) {
+				polygon = polygon[:len(polygon)-1]
+			}
+			if len(polygon) > 0 {
+				g.Polygons = append(g.Polygons, append([]Point{offset(first)}, polygon...))
+			}
+			start = end
+		}
+		if len(g.Polygons) > 0 {
+			outlines = append(outlines, g)
+		}
+	})
+	return outlines
+}
+
+// place calls fn with the pen position of every rune of s
+func (f *OutlineFont) place(s string, fn func(i int, r rune, index truetype.Index, x, y float32)) {
+	x, y := float32(0), float32(0)
+	previous := truetype.Index(0)
+	for i, r := range []rune(s) {
+		if r == '\n' {
+			x, y, previous = 0, y-f.LineHeight(), 0
+			continue
+		}
+		index := f.ttf.Index(r)
+		if previous != 0 {
+			x += fixed26(f.ttf.Kern(f.scale, previous, index))
+		}
+		previous = index
+		fn(i, r, index, x, y)
+		x += fixed26(f.ttf.HMetric(f.scale, index).AdvanceWidth)
+	}
+}
+
+// contours returns the flattened contours of the glyph at index, oriented so that outer
+// contours wind counter-clockwise
+func (f *OutlineFont) contours(index truetype.Index) [][]Point {
+	if err := f.buf.Load(f.ttf, f.scale, index, font.HintingNone); err != nil {
+		return nil
+	}
+	contours := [][]Point{}
+	start := 0
+	for _, end := range f.buf.Ends {
+		if contour := f.flatten(f.buf.Points[start:end]); len(contour) >= 3 {
+			contours = append(contours, contour)
+		}
+		start = end
+	}
+	orient(contours)
+	return contours
+}
+
+// flatten turns the quadratic curves of a truetype contour into segments
+func (f *OutlineFont) flatten(points []truetype.Point) []Point {
+	contour := []Point{}
+	start := walk(points, func(p Point) {
+		contour = append(contour, p)
+	}, func(a, b, c Point) {
+		contour = f.curve(contour, a, b, c)
+	})
+	contour = append([]Point{start}, contour...)
+
+	// the contour closes on itself
+	if last := len(contour) - 1; last > 0 && contour[last] == contour[0] {
+		contour = contour[:last]
+	}
+	return contour
+}
+
+// walk calls line and curve with the segments of a truetype contour, in order, and
+// returns the point the contour starts and ends at.  Each segment starts where the previous
+// one ended.
+func walk(points []truetype.Point, line func(to Point), curve func(from, control, to Point)) Point {
+	n := len(points)
+	if n == 0 {
+		return Point{}
+	}
+	at := func(i int) (Point, bool) {
+		p := points[(i%n+n)%n]
+		return Point{X: fixed26(p.X), Y: fixed26(p.Y)}, p.Flags&1 != 0
+	}
+
+	// start at an on-curve point, or between the first two control points when every
+	// point is off the curve
+	first, last := -1, n-1
+	for i := range points {
+		if _, on := at(i); on {
+			first, last = i, i+n-1
+			break
+		}
+	}
+	var start Point
+	if first >= 0 {
+		start, _ = at(first)
+	} else {
+		a, _ := at(0)
+		b, _ := at(1)
+		start, first, last = midpoint(a, b), 0, n
+	}
+
+	current, control, curved := start, Point{}, false
+	for i := first + 1; i <= last; i++ {
+		p, on := at(i)
+		switch {
+		case on && curved:
+			curve(current, control, p)
+			current, curved = p, false
+		case on:
+			line(p)
+			current = p
+		case curved:
+			mid := midpoint(control, p)
+			curve(current, control, mid)
+			current, control = mid, p
+		default:
+			control, curved = p, true
+		}
+	}
+	if curved {
+		curve(current, control, start)
+	} else if current != start {
+		line(start)
+	}
+	return start
+}
+
+// curve appends the quadratic curve from a to c controlled by b, a already being part
+// of the contour
+func (f *OutlineFont) curve(contour []Point, a, b, c Point) []Point {
+	dx, dy := a.X-2*b.X+c.X, a.Y-2*b.Y+c.Y
+	tolerance := f.Tolerance
+	if tolerance <= 0 {
+		tolerance = 0.25
+	}
+	segments := int(math.Ceil(math.Sqrt(math.Hypot(float64(dx), float64(dy)) / float64(4*tolerance))))
+	if segments < 1 {
+		segments = 1
+	}
+	for i := 1; i <= segments; i++ {
+		t := float32(i) / float32(segments)
+		u := 1 - t
+		contour = append(contour, Point{
+			X: u*u*a.X + 2*u*t*b.X + t*t*c.X,
+			Y: u*u*a.Y + 2*u*t*b.Y + t*t*c.Y,
+		})
+	}
+	return contour
+}
+
+func midpoint(a, b Point) Point {
+	return Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+func fixed26(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
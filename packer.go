@@ -0,0 +1,108 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"image"
+)
+
+// Packing selects how glyphs are arranged within the atlas.
+type Packing int
+
+const (
+	// PackGrid places every glyph in a cell large enough for the largest glyph of the font.
+	PackGrid Packing = iota
+
+	// PackSkyline trims each glyph to its advance and ink height and packs the resulting
+	// rectangles tightly, which results in far smaller atlases.
+	PackSkyline
+)
+
+// Skyline packs rectangles into an area of a fixed size using the bottom-left skyline
+// heuristic.  Rectangles are placed as close to the top of the area as possible.
+type Skyline struct {
+	width, height int
+	nodes         []skylineNode
+}
+
+// skylineNode is a horizontal segment of the skyline.  Everything above y is occupied.
+type skylineNode struct {
+	x, y, width int
+}
+
+// NewSkyline creates an empty packer for an area of width by height pixels.
+func NewSkyline(width, height int) *Skyline {
+	return &Skyline{width: width, height: height, nodes: []skylineNode{{0, 0, width}}}
+}
+
+// Pack finds room for a rectangle of w by h pixels and returns its upper left corner.
+// It returns false when the rectangle does not fit.
+func (s *Skyline) Pack(w, h int) (image.Point, bool) {
+	best, bestX, bestY, bestWidth := -1, 0, 0, 0
+	for i := range s.nodes {
+		y, ok := s.fits(i, w, h)
+		if !ok {
+			continue
+		}
+		if best < 0 || y+h < bestY+h || (y+h == bestY+h && s.nodes[i].width < bestWidth) {
+			best, bestX, bestY, bestWidth = i, s.nodes[i].x, y, s.nodes[i].width
+		}
+	}
+	if best < 0 {
+		return image.Point{}, false
+	}
+	s.insert(best, skylineNode{bestX, bestY + h, w})
+	return image.Point{X: bestX, Y: bestY}, true
+}
+
+// fits returns the lowest y at which a rectangle starting at node i can be placed
+func (s *Skyline) fits(i, w, h int) (int, bool) {
+	x := s.nodes[i].x
+	if x+w > s.width {
+		return 0, false
+	}
+	y := 0
+	for remaining := w; remaining > 0; i++ {
+		if s.nodes[i].y > y {
+			y = s.nodes[i].y
+		}
+		if y+h > s.height {
+			return 0, false
+		}
+		remaining -= s.nodes[i].width
+	}
+	return y, true
+}
+
+func (s *Skyline) insert(i int, node skylineNode) {
+	s.nodes = append(s.nodes, skylineNode{})
+	copy(s.nodes[i+1:], s.nodes[i:])
+	s.nodes[i] = node
+
+	// shrink or remove the nodes now covered by the new one
+	for j := i + 1; j < len(s.nodes); {
+		previous := s.nodes[j-1]
+		overlap := previous.x + previous.width - s.nodes[j].x
+		if overlap <= 0 {
+			break
+		}
+		s.nodes[j].x += overlap
+		s.nodes[j].width -= overlap
+		if s.nodes[j].width > 0 {
+			break
+		}
+		s.nodes = append(s.nodes[:j], s.nodes[j+1:]...)
+	}
+
+	// merge neighbours of the same height
+	for j := 0; j < len(s.nodes)-1; {
+		if s.nodes[j].y == s.nodes[j+1].y {
+			s.nodes[j].width += s.nodes[j+1].width
+			s.nodes = append(s.nodes[:j+1], s.nodes[j+2:]...)
+		} else {
+			j++
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+// Quad is the rectangle a glyph is drawn in along with the part of its atlas page copied
+// onto it, both in pixels.  Renderers other than the GL packages use quads to draw text
+// laid out by the layout package the same way.
+type Quad struct {
+	Page int
+
+	// X1, X2: the lower left and upper right corners of the quad, y growing upwards
+	X1, X2 Point
+
+	// Src1, Src2: the upper left and lower right corners of the glyph on its page, y
+	// growing downwards as in images
+	Src1, Src2 Point
+}
+
+// Quad returns the quad of the glyph at index glyph placed on a line at (x, y), the point
+// layout.Glyph holds.  Like the GL packages, quads are as wide as the advance of their
+// glyph so that they do not overlap.
+func (fc *FontConfig) Quad(glyph int, x, y float32) Quad {
+	g := fc.Glyphs[glyph]
+	w, h := float32(g.Advance), float32(g.Height)
+	y += float32(g.OffsetY)
+	return Quad{
+		Page: g.Page,
+		X1:   Point{X: x, Y: y},
+		X2:   Point{X: x + w, Y: y + h},
+		Src1: Point{X: float32(g.X), Y: float32(g.Y)},
+		Src2: Point{X: float32(g.X) + w, Y: float32(g.Y) + h},
+	}
+}
+
+// LineHeightPixels returns the height of a line of text, the tallest glyph when LineHeight
+// is not set.
+func (fc *FontConfig) LineHeightPixels() float32 {
+	if fc.LineHeight > 0 {
+		return float32(fc.LineHeight)
+	}
+	tallest := 0
+	for _, g := range fc.Glyphs {
+		if g.Height > tallest {
+			tallest = g.Height
+		}
+	}
+	return float32(tallest)
+}
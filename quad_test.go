@@ -0,0 +1,24 @@
+package gltext
+
+import (
+	"testing"
+)
+
+func TestQuad(t *testing.T) {
+	fc := &FontConfig{Glyphs: Charset{
+		{X: 0, Y: 0, Width: 8, Height: 12, Advance: 10},
+		{X: 10, Y: 20, Width: 4, Height: 6, Advance: 5, Page: 1, OffsetY: 2},
+	}}
+	q := fc.Quad(1, 30, -12)
+	expected := Quad{Page: 1, X1: Point{X: 30, Y: -10}, X2: Point{X: 35, Y: -4}, Src1: Point{X: 10, Y: 20}, Src2: Point{X: 15, Y: 26}}
+	if q != expected {
+		t.Errorf("Expecting %+v, got %+v", expected, q)
+	}
+	if h := fc.LineHeightPixels(); h != 12 {
+		t.Error("Expecting the tallest glyph to set the line height", h)
+	}
+	fc.LineHeight = 14
+	if h := fc.LineHeightPixels(); h != 14 {
+		t.Error("Expecting the line height of the config", h)
+	}
+}
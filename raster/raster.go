@@ -0,0 +1,95 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package raster draws text into images on the cpu.  Texts are laid out and cut from
+// the atlas of their font config like the GL packages do, so that unit tests, golden
+// images and text rendered on a server match what is drawn on screen.
+package raster
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Text is a string laid out with a font config, ready to be drawn.
+type Text struct {
+	Config *gltext.FontConfig
+
+	// Options are used by SetString.  The line height of the config is used when
+	// LineHeight is zero.
+	Options layout.Options
+
+	// Runs color parts of the string, see gltext.StyleRuns.ColorAt
+	Runs gltext.StyleRuns
+
+	String string
+
+	// Width and Height of the laid out string in pixels
+	Width, Height float32
+
+	lineHeight float32
+	quads      []gltext.Quad
+	indices    []int // index of the rune of each quad
+}
+
+// NewText creates an empty text drawn with the glyphs of config
+func NewText(config *gltext.FontConfig) *Text {
+	return &Text{Config: config}
+}
+
+// SetString lays out s
+func (t *Text) SetString(s string) {
+	t.String = s
+	opts := t.Options
+	if opts.LineHeight == 0 {
+		opts.LineHeight = t.Config.LineHeightPixels()
+	}
+	t.lineHeight = opts.LineHeight
+	t.quads, t.indices = t.quads[:0], t.indices[:0]
+	t.Width, t.Height = 0, opts.LineHeight
+	for _, p := range layout.Layout(t.Config, []rune(s), opts) {
+		q := t.Config.Quad(p.Glyph, p.X, p.Y)
+		if q.X2.X > t.Width {
+			t.Width = q.X2.X
+		}
+		if bottom := opts.LineHeight - p.Y; bottom > t.Height {
+			t.Height = bottom
+		}
+		t.quads = append(t.quads, q)
+		t.indices = append(t.indices, p.Index)
+	}
+}
+
+// Draw draws the text over dst with the upper left corner of its first line at (x, y)
+func (t *Text) Draw(dst draw.Image, x, y int, c color.Color) {
+	pages := t.Config.PageImages()
+	for i, q := range t.quads {
+		qc := c
+		if runColor := t.Runs.ColorAt(t.indices[i]); runColor != nil {
+			qc = runColor
+		}
+		// image rows grow downwards from the top of the first line
+		r := image.Rect(
+			x+round(q.X1.X), y+round(t.lineHeight-q.X2.Y),
+			x+round(q.X2.X), y+round(t.lineHeight-q.X1.Y),
+		)
+		src := image.Pt(round(q.Src1.X), round(q.Src1.Y))
+		draw.DrawMask(dst, r, image.NewUniform(qc), image.Point{}, pages[q.Page], src, draw.Over)
+	}
+}
+
+// Image returns a transparent image as large as the text with the text drawn on it
+func (t *Text) Image(c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(float64(t.Width))), int(math.Ceil(float64(t.Height)))))
+	t.Draw(img, 0, 0, c)
+	return img
+}
+
+func round(v float32) int {
+	return int(math.Floor(float64(v) + 0.5))
+}
@@ -0,0 +1,39 @@
+package raster
+
+import (
+	"github.com/4ydx/gltext"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDraw(t *testing.T) {
+	// two glyphs of 2x2 pixels, the first solid and the second covering its left column
+	atlas := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		atlas.SetNRGBA(0, y, color.NRGBA{255, 255, 255, 255})
+		atlas.SetNRGBA(1, y, color.NRGBA{255, 255, 255, 255})
+		atlas.SetNRGBA(2, y, color.NRGBA{255, 255, 255, 255})
+	}
+	config := &gltext.FontConfig{
+		Image:      atlas,
+		RuneRanges: gltext.RuneRanges{{Low: 'a', High: 'c'}},
+		Glyphs:     gltext.Charset{{X: 0, Width: 2, Height: 2, Advance: 2}, {X: 2, Width: 2, Height: 2, Advance: 2}},
+	}
+	text := NewText(config)
+	text.SetString("ab\nb")
+	if text.Width != 4 || text.Height != 4 {
+		t.Fatal("Bad size", text.Width, text.Height)
+	}
+
+	red := color.RGBA{255, 0, 0, 255}
+	img := text.Image(red)
+	for _, c := range []struct {
+		x, y int
+		set  bool
+	}{{0, 0, true}, {1, 1, true}, {2, 0, true}, {3, 0, false}, {0, 2, true}, {1, 3, false}, {2, 2, false}} {
+		if got := img.RGBAAt(c.x, c.y) == red; got != c.set {
+			t.Errorf("Expecting pixel (%d, %d) to be drawn: %v", c.x, c.y, c.set)
+		}
+	}
+}
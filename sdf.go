@@ -0,0 +1,232 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"errors"
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// SDFOptions describe the signed distance field atlas created by GenerateSDF.
+type SDFOptions struct {
+	// Scale, RuneRanges, RunesPerRow and AdjustHeight have the same meaning as
+	// the parameters of NewTruetypeFontConfig.
+	Scale        fixed.Int26_6
+	RuneRanges   RuneRanges
+	RunesPerRow  fixed.Int26_6
+	AdjustHeight fixed.Int26_6
+
+	// Spread is the distance in pixels, on either side of a glyph's outline, covered by
+	// the field.  Defaults to 4.
+	Spread float32
+
+	// Upsample is the factor by which glyphs are rasterized larger than Scale before
+	// their distance field is sampled down.  Defaults to 8.
+	Upsample int
+}
+
+// GenerateSDF rasterizes the glyphs of a truetype font into a signed distance field atlas.
+// The distance to each glyph's outline is stored in the alpha channel of the image, 0.5 being
+// the outline itself, which allows the glyphs to remain sharp when the text is scaled.  Fonts
+// created from the resulting config are drawn using the SDF shader.
+func GenerateSDF(ttfData []byte, opts SDFOptions) (*FontConfig, error) {
+	if !opts.RuneRanges.Validate() {
+		return nil, errors.New("Invalid rune ranges supplied.")
+	}
+	if opts.RunesPerRow <= 0 {
+		return nil, errors.New("RunesPerRow should be larger than zero.")
+	}
+	if opts.Spread <= 0 {
+		opts.Spread = 4
+	}
+	if opts.Upsample <= 0 {
+		opts.Upsample = 8
+	}
+	ttf, err := truetype.Parse(ttfData)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &FontConfig{SDF: true, SDFSpread: opts.Spread, SDFOptions: &opts}
+	length := rune(0)
+	for _, r := range opts.RuneRanges {
+		length += r.High - r.Low + 1
+	}
+	fc.RuneRanges = opts.RuneRanges
+	fc.Glyphs = make(Charset, int(length))
+
+	gb := ttf.Bounds(opts.Scale)
+	gw := int(gb.Max.X - gb.Min.X)
+	gh := int(gb.Max.Y-gb.Min.Y) + int(opts.AdjustHeight)
+
+	gc := fixed.Int26_6(len(fc.Glyphs))
+	runesPerCol := (gc / opts.RunesPerRow) + 1
+	iw := Pow2(uint32(gw * int(opts.RunesPerRow)))
+	ih := Pow2(uint32(gh * int(runesPerCol)))
+	if iw > ih {
+		ih = iw
+	} else {
+		iw = ih
+	}
+	fc.Image = image.NewNRGBA(image.Rect(0, 0, int(iw), int(ih)))
+
+	// glyphs are drawn one at a time into a large cell
+	u := opts.Upsample
+	hiScale := opts.Scale * fixed.Int26_6(u)
+	cell := image.NewAlpha(image.Rect(0, 0, gw*u, gh*u))
+	field := newDistanceField(gw*u, gh*u)
+
+	c := freetype.NewContext()
+	c.SetDPI(72)
+	c.SetFont(ttf)
+	c.SetFontSize(float64(hiScale))
+	c.SetClip(cell.Bounds())
+	c.SetDst(cell)
+	c.SetSrc(image.Opaque)
+
+	gi := 0
+	for _, runeRange := range fc.RuneRanges {
+		for ch := runeRange.Low; ch <= runeRange.High; ch++ {
+			metric := ttf.HMetric(opts.Scale, ttf.Index(ch))
+			gx, gy := (gi%int(opts.RunesPerRow))*gw, (gi/int(opts.RunesPerRow))*gh
+			fc.Glyphs[gi] = Glyph{X: gx, Y: gy, Width: gw, Height: gh, Advance: int(metric.AdvanceWidth)}
+
+			draw.Draw(cell, cell.Bounds(), image.Transparent, image.ZP, draw.Src)
+			pt := freetype.Pt(0, int(c.PointToFixed(float64(hiScale))>>6))
+			if _, err := c.DrawString(string(ch), pt); err != nil {
+				return nil, err
+			}
+			field.compute(cell)
+			field.sample(fc.Image, gx, gy, gw, gh, u, opts.Spread)
+			gi++
+		}
+	}
+	return fc, nil
+}
+
+// distanceField holds the signed distance, in pixels, from each pixel of a
+// cell to the nearest glyph outline, positive values being inside the glyph
+type distanceField struct {
+	w, h     int
+	distance []float64
+
+	// scratch space for the distance transform
+	inside, outside []float64
+	f, d, z         []float64
+	v               []int
+}
+
+func newDistanceField(w, h int) *distanceField {
+	n := w
+	if h > n {
+		n = h
+	}
+	return &distanceField{
+		w: w, h: h,
+		distance: make([]float64, w*h),
+		inside:   make([]float64, w*h),
+		outside:  make([]float64, w*h),
+		f:        make([]float64, n),
+		d:        make([]float64, n),
+		z:        make([]float64, n+1),
+		v:        make([]int, n),
+	}
+}
+
+func (df *distanceField) compute(cell *image.Alpha) {
+	for y := 0; y < df.h; y++ {
+		for x := 0; x < df.w; x++ {
+			at := y*df.w + x
+			if cell.AlphaAt(x, y).A >= 128 {
+				df.inside[at], df.outside[at] = 0, math.MaxFloat32
+			} else {
+				df.inside[at], df.outside[at] = math.MaxFloat32, 0
+			}
+		}
+	}
+	// squared distance to the nearest pixel inside and outside of the glyph
+	df.transform(df.inside)
+	df.transform(df.outside)
+	for i := range df.distance {
+		if df.inside[i] == 0 {
+			df.distance[i] = math.Sqrt(df.outside[i]) - 0.5
+		} else {
+			df.distance[i] = 0.5 - math.Sqrt(df.inside[i])
+		}
+	}
+}
+
+// transform computes the squared euclidean distance transform of grid in place
+// using the algorithm of Felzenszwalb and Huttenlocher
+func (df *distanceField) transform(grid []float64) {
+	for x := 0; x < df.w; x++ {
+		for y := 0; y < df.h; y++ {
+			df.f[y] = grid[y*df.w+x]
+		}
+		df.transform1D(df.h)
+		for y := 0; y < df.h; y++ {
+			grid[y*df.w+x] = df.d[y]
+		}
+	}
+	for y := 0; y < df.h; y++ {
+		copy(df.f, grid[y*df.w:(y+1)*df.w])
+		df.transform1D(df.w)
+		copy(grid[y*df.w:(y+1)*df.w], df.d[:df.w])
+	}
+}
+
+func (df *distanceField) transform1D(n int) {
+	f, d, v, z := df.f, df.d, df.v, df.z
+	k := 0
+	v[0] = 0
+	z[0], z[1] = math.Inf(-1), math.Inf(1)
+	for q := 1; q < n; q++ {
+		s := ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		for s <= z[k] {
+			k--
+			s = ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		}
+		k++
+		v[k] = q
+		z[k], z[k+1] = s, math.Inf(1)
+	}
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		d[q] = float64((q-v[k])*(q-v[k])) + f[v[k]]
+	}
+}
+
+// sample averages blocks of u by u distances of the field into the w by h pixels of dst at (x,y)
+func (df *distanceField) sample(dst *image.NRGBA, x, y, w, h, u int, spread float32) {
+	for py := 0; py < h; py++ {
+		for px := 0; px < w; px++ {
+			sum := 0.0
+			for sy := 0; sy < u; sy++ {
+				at := (py*u+sy)*df.w + px*u
+				for sx := 0; sx < u; sx++ {
+					sum += df.distance[at+sx]
+				}
+			}
+			distance := sum / float64(u*u) / float64(u)
+			a := 0.5 + distance/(2*float64(spread))
+			if a < 0 {
+				a = 0
+			}
+			if a > 1 {
+				a = 1
+			}
+			dst.SetNRGBA(x+px, y+py, color.NRGBA{R: 255, G: 255, B: 255, A: uint8(a*255 + 0.5)})
+		}
+	}
+}
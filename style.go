@@ -0,0 +1,82 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"image/color"
+)
+
+// FontStyle selects one of the faces of a font family.  Styles may be combined.
+type FontStyle uint8
+
+const (
+	StyleBold FontStyle = 1 << iota
+	StyleItalic
+
+	StyleRegular    FontStyle = 0
+	StyleBoldItalic           = StyleBold | StyleItalic
+)
+
+// StyleRun applies a style to the runes of a string from Start up to, but not including, End.
+// Indices count runes, not bytes.
+//
+// Color replaces the color of the text when set.  Size scales the glyphs and their
+// advance, zero leaving them unscaled.
+type StyleRun struct {
+	Start, End int
+	Style      FontStyle
+	Color      color.Color
+	Size       float32
+}
+
+// StyleRuns may overlap, in which case their styles are combined.  The color and
+// size of the last run setting them win.
+type StyleRuns []StyleRun
+
+// StyleAt returns the combined style of the runs covering the rune at index i.
+func (runs StyleRuns) StyleAt(i int) (style FontStyle) {
+	for _, run := range runs {
+		if i >= run.Start && i < run.End {
+			style |= run.Style
+		}
+	}
+	return
+}
+
+// ColorAt returns the color of the rune at index i, or nil when no run covering it
+// sets a color.
+func (runs StyleRuns) ColorAt(i int) (c color.Color) {
+	for _, run := range runs {
+		if i >= run.Start && i < run.End && run.Color != nil {
+			c = run.Color
+		}
+	}
+	return
+}
+
+// SizeAt returns the scale of the rune at index i, which is 1 when no run covering
+// it sets a size.
+func (runs StyleRuns) SizeAt(i int) (size float32) {
+	size = 1
+	for _, run := range runs {
+		if i >= run.Start && i < run.End && run.Size > 0 {
+			size = run.Size
+		}
+	}
+	return
+}
+
+// Link is a range of runes, from Start up to End, pointing to URL.
+type Link struct {
+	Start, End int
+	URL        string
+}
+
+// Markup is a plain string along with the styles and links parsed out of marked up text.
+type Markup struct {
+	Text  string
+	Runs  StyleRuns
+	Links []Link
+}
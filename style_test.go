@@ -0,0 +1,12 @@
+package gltext
+
+import (
+	"testing"
+)
+
+func TestStyleRuns(t *testing.T) {
+	runs := StyleRuns{{Start: 1, End: 3, Style: StyleBold}, {Start: 2, End: 4, Style: StyleItalic}}
+	if runs.StyleAt(0) != StyleRegular || runs.StyleAt(1) != StyleBold || runs.StyleAt(2) != StyleBoldItalic || runs.StyleAt(3) != StyleItalic {
+		t.Error("Bad styles")
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"sort"
+)
+
+// Triangulate fills the closed contours of a glyph with triangles wound
+// counter-clockwise.  Contours nested inside an odd number of other contours are holes.
+func Triangulate(contours [][]Point) [][3]Point {
+	triangles := [][3]Point{}
+	for _, polygon := range polygons(contours) {
+		triangles = clipEars(polygon, triangles)
+	}
+	return triangles
+}
+
+// orient winds the contours counter-clockwise, holes clockwise
+func orient(contours [][]Point) {
+	for i, contour := range contours {
+		hole := depth(contours, i)%2 == 1
+		if (signedArea(contour) < 0) != hole {
+			reverse(contour)
+		}
+	}
+}
+
+// depth returns how many of the other contours enclose contour i
+func depth(contours [][]Point, i int) int {
+	d := 0
+	for j, other := range contours {
+		if j != i && encloses(other, contours[i]) {
+			d++
+		}
+	}
+	return d
+}
+
+// encloses reports whether contour inner lies within outer, judging by its vertices
+func encloses(outer, inner []Point) bool {
+	inside, outside := 0, 0
+	for _, p := range inner {
+		if pointInPolygon(outer, p) {
+			inside++
+		} else {
+			outside++
+		}
+	}
+	return inside > outside
+}
+
+// polygons merges every hole into its enclosing outer contour, producing simple
+// polygons wound counter-clockwise
+func polygons(contours [][]Point) [][]Point {
+	type outline struct {
+		points []Point
+		holes  [][]Point
+	}
+	outlines := []*outline{}
+	depths := make([]int, len(contours))
+	for i, contour := range contours {
+		depths[i] = depth(contours, i)
+		if depths[i]%2 == 0 {
+			outlines = append(outlines, &outline{points: ccw(contour, false)})
+		}
+	}
+	for i, contour := range contours {
+		if depths[i]%2 == 0 {
+			continue
+		}
+		// the hole belongs to the smallest outer contour enclosing it
+		var parent *outline
+		for _, o := range outlines {
+			if encloses(o.points, contour) && (parent == nil || abs32(signedArea(o.points)) < abs32(signedArea(parent.points))) {
+				parent = o
+			}
+		}
+		if parent != nil {
+			parent.holes = append(parent.holes, ccw(contour, true))
+		}
+	}
+
+	result := make([][]Point, 0, len(outlines))
+	for _, o := range outlines {
+		// holes are bridged from right to left so that earlier bridges do not cross later ones
+		sort.Slice(o.holes, func(i, j int) bool {
+			return o.holes[i][rightmost(o.holes[i])].X > o.holes[j][rightmost(o.holes[j])].X
+		})
+		polygon := o.points
+		for _, hole := range o.holes {
+			polygon = bridge(polygon, hole)
+		}
+		result = append(result, polygon)
+	}
+	return result
+}
+
+// ccw returns a copy of the contour wound counter-clockwise, or clockwise for holes
+func ccw(contour []Point, clockwise bool) []Point {
+	c := append([]Point(nil), contour...)
+	if (signedArea(c) < 0) != clockwise {
+		reverse(c)
+	}
+	return c
+}
+
+// bridge connects the hole to the polygon through a pair of coincident edges running
+// from the rightmost vertex of the hole to a vertex of the polygon it can see
+func bridge(polygon, hole []Point) []Point {
+	mi := rightmost(hole)
+	m := hole[mi]
+
+	// cast a ray towards +x and find the closest edge it hits
+	n := len(polygon)
+	hit, pi := float32(0), -1
+	for i := range polygon {
+		a, b := polygon[i], polygon[(i+1)%n]
+		if a.Y == b.Y || (a.Y-m.Y)*(b.Y-m.Y) > 0 {
+			continue
+		}
+		x := a.X + (m.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if x < m.X || (pi >= 0 && x >= hit) {
+			continue
+		}
+		hit = x
+		switch {
+		case a.Y == m.Y:
+			pi = i
+		case b.Y == m.Y:
+			pi = (i + 1) % n
+		case a.X > b.X:
+			pi = i
+		default:
+			pi = (i + 1) % n
+		}
+	}
+	if pi < 0 {
+		return polygon
+	}
+
+	// a reflex vertex within the triangle formed by the ray may block the view, in which
+	// case the one closest in angle to the ray is visible instead
+	p, intersection := polygon[pi], Point{X: hit, Y: m.Y}
+	if p != intersection {
+		best := float32(-1)
+		for i, v := range polygon {
+			if !reflex(polygon, i) || !inTriangle(v, m, intersection, p) || v == p {
+				continue
+			}
+			dx, dy := v.X-m.X, abs32(v.Y-m.Y)
+			if dx <= 0 {
+				continue
+			}
+			if cosine := dx * dx / (dx*dx + dy*dy); cosine > best {
+				best, pi = cosine, i
+			}
+		}
+	}
+
+	merged := make([]Point, 0, len(polygon)+len(hole)+2)
+	merged = append(merged, polygon[:pi+1]...)
+	for i := 0; i <= len(hole); i++ {
+		merged = append(merged, hole[(mi+i)%len(hole)])
+	}
+	merged = append(merged, polygon[pi])
+	merged = append(merged, polygon[pi+1:]...)
+	return merged
+}
+
+// clipEars triangulates a simple counter-clockwise polygon by repeatedly cutting off
+// convex vertices whose triangle contains no other vertex
+func clipEars(polygon []Point, triangles [][3]Point) [][3]Point {
+	indices := make([]int, len(polygon))
+	for i := range indices {
+		indices[i] = i
+	}
+	for len(indices) > 3 {
+		n := len(indices)
+		clipped := false
+		for i := 0; i < n; i++ {
+			a, b, c := polygon[indices[(i+n-1)%n]], polygon[indices[i]], polygon[indices[(i+1)%n]]
+			turn := cross(a, b, c)
+			if turn == 0 {
+				// collinear vertices add nothing to the fill
+				indices = append(indices[:i], indices[i+1:]...)
+				clipped = true
+				break
+			}
+			if turn < 0 || !isEar(polygon, indices, a, b, c) {
+				continue
+			}
+			triangles = append(triangles, [3]Point{a, b, c})
+			indices = append(indices[:i], indices[i+1:]...)
+			clipped = true
+			break
+		}
+		if !clipped {
+			// the polygon is not simple, cut the first convex vertex so that it still ends
+			for i := 0; i < n; i++ {
+				a, b, c := polygon[indices[(i+n-1)%n]], polygon[indices[i]], polygon[indices[(i+1)%n]]
+				if cross(a, b, c) > 0 || i == n-1 {
+					triangles = append(triangles, [3]Point{a, b, c})
+					indices = append(indices[:i], indices[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	if len(indices) == 3 {
+		a, b, c := polygon[indices[0]], polygon[indices[1]], polygon[indices[2]]
+		if cross(a, b, c) > 0 {
+			triangles = append(triangles, [3]Point{a, b, c})
+		}
+	}
+	return triangles
+}
+
+func isEar(polygon []Point, indices []int, a, b, c Point) bool {
+	for _, i := range indices {
+		p := polygon[i]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if inTriangle(p, a, b, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func reflex(polygon []Point, i int) bool {
+	n := len(polygon)
+	return cross(polygon[(i+n-1)%n], polygon[i], polygon[(i+1)%n]) < 0
+}
+
+func rightmost(contour []Point) int {
+	r := 0
+	for i, p := range contour {
+		if p.X > contour[r].X {
+			r = i
+		}
+	}
+	return r
+}
+
+// inTriangle reports whether p lies within or on the edges of triangle abc, wound either way
+func inTriangle(p, a, b, c Point) bool {
+	d1, d2, d3 := cross(a, b, p), cross(b, c, p), cross(c, a, p)
+	negative := d1 < 0 || d2 < 0 || d3 < 0
+	positive := d1 > 0 || d2 > 0 || d3 > 0
+	return !(negative && positive)
+}
+
+func pointInPolygon(polygon []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func cross(a, b, c Point) float32 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func signedArea(contour []Point) float32 {
+	area := float32(0)
+	for i, j := 0, len(contour)-1; i < len(contour); j, i = i, i+1 {
+		area += contour[j].X*contour[i].Y - contour[i].X*contour[j].Y
+	}
+	return area / 2
+}
+
+func reverse(contour []Point) {
+	for i, j := 0, len(contour)-1; i < j; i, j = i+1, j-1 {
+		contour[i], contour[j] = contour[j], contour[i]
+	}
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,75 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltext
+
+import (
+	"math"
+)
+
+// TextMesh is an indexed triangle mesh of solid 3D text.  Positions and normals hold
+// x, y, z per vertex, in pixels with y pointing up and the front of the text facing +z.
+type TextMesh struct {
+	Positions []float32
+	Normals   []float32
+	Indices   []uint32
+}
+
+// TextMesh extrudes the glyphs of s by depth pixels, from z = 0 towards -z.  The front and
+// back caps are the triangulated outlines and the sides are flat shaded quads along every
+// contour.
+func (f *OutlineFont) TextMesh(s string, depth float32) *TextMesh {
+	m := &TextMesh{}
+	for _, outline := range f.Outlines(s) {
+		triangles := Triangulate(outline.Contours)
+		for _, t := range triangles {
+			m.triangle(
+				[3]float32{t[0].X, t[0].Y, 0}, [3]float32{t[1].X, t[1].Y, 0}, [3]float32{t[2].X, t[2].Y, 0},
+				[3]float32{0, 0, 1},
+			)
+		}
+		if depth <= 0 {
+			continue
+		}
+		for _, t := range triangles {
+			m.triangle(
+				[3]float32{t[2].X, t[2].Y, -depth}, [3]float32{t[1].X, t[1].Y, -depth}, [3]float32{t[0].X, t[0].Y, -depth},
+				[3]float32{0, 0, -1},
+			)
+		}
+		for _, contour := range outline.Contours {
+			for i, a := range contour {
+				b := contour[(i+1)%len(contour)]
+				m.side(a, b, depth)
+			}
+		}
+	}
+	return m
+}
+
+// side adds the quad extruded from the contour edge ab.  Contours wind counter-clockwise
+// around the solid, so the outward normal is the edge turned clockwise.
+func (m *TextMesh) side(a, b Point, depth float32) {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	normal := [3]float32{dy / length, -dx / length, 0}
+	first := uint32(len(m.Positions) / 3)
+	for _, v := range [4][3]float32{{a.X, a.Y, 0}, {a.X, a.Y, -depth}, {b.X, b.Y, -depth}, {b.X, b.Y, 0}} {
+		m.Positions = append(m.Positions, v[0], v[1], v[2])
+		m.Normals = append(m.Normals, normal[0], normal[1], normal[2])
+	}
+	m.Indices = append(m.Indices, first, first+1, first+2, first, first+2, first+3)
+}
+
+func (m *TextMesh) triangle(a, b, c, normal [3]float32) {
+	first := uint32(len(m.Positions) / 3)
+	for _, v := range [3][3]float32{a, b, c} {
+		m.Positions = append(m.Positions, v[0], v[1], v[2])
+		m.Normals = append(m.Normals, normal[0], normal[1], normal[2])
+	}
+	m.Indices = append(m.Indices, first, first+1, first+2)
+}
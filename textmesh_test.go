@@ -0,0 +1,129 @@
+package gltext
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+func trianglesArea(triangles [][3]Point) float32 {
+	area := float32(0)
+	for _, t := range triangles {
+		turn := cross(t[0], t[1], t[2])
+		if turn < 0 {
+			return -1
+		}
+		area += turn / 2
+	}
+	return area
+}
+
+func TestTriangulateHole(t *testing.T) {
+	square := []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	hole := []Point{{X: 3, Y: 3}, {X: 7, Y: 3}, {X: 7, Y: 7}, {X: 3, Y: 7}}
+
+	triangles := Triangulate([][]Point{square})
+	if len(triangles) != 2 || trianglesArea(triangles) != 100 {
+		t.Error("Bad square", triangles)
+	}
+	// the winding of the input does not matter
+	triangles = Triangulate([][]Point{square, hole})
+	if area := trianglesArea(triangles); area != 84 {
+		t.Error("Bad area", area, triangles)
+	}
+	if len(triangles) != 8 {
+		t.Error("Bad triangle count", len(triangles))
+	}
+}
+
+func TestTextMesh(t *testing.T) {
+	fd, err := os.Open("font/font_1_honokamin.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	f, err := NewOutlineFont(fd, 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outlines := f.Outlines("o B\nA")
+	if len(outlines) != 3 {
+		t.Fatal("Bad outline count", len(outlines))
+	}
+	if outlines[0].Rune != 'o' || outlines[1].Index != 2 || outlines[2].Rune != 'A' {
+		t.Error("Bad outlines", outlines[0].Rune, outlines[1].Index, outlines[2].Rune)
+	}
+	if outlines[2].Contours[0][0].Y > -f.LineHeight()/2 {
+		t.Error("The second line is not below the first")
+	}
+
+	for _, o := range outlines {
+		filled := float32(0)
+		for _, contour := range o.Contours {
+			filled += signedArea(contour)
+		}
+		area := trianglesArea(Triangulate(o.Contours))
+		if filled <= 0 || math.Abs(float64(area-filled)) > float64(filled)*1e-3 {
+			t.Errorf("Bad fill of %q: %f, outlines %f", o.Rune, area, filled)
+		}
+	}
+
+	m := f.TextMesh("o", 5)
+	if len(m.Positions) != len(m.Normals) || len(m.Indices)%3 != 0 {
+		t.Fatal("Bad mesh", len(m.Positions), len(m.Normals), len(m.Indices))
+	}
+	for i := 0; i < len(m.Normals); i += 3 {
+		n := m.Normals[i : i+3]
+		if l := n[0]*n[0] + n[1]*n[1] + n[2]*n[2]; math.Abs(float64(l-1)) > 1e-4 {
+			t.Fatal("Bad normal", n)
+		}
+	}
+	for _, z := range []float32{m.Positions[2], m.Positions[len(m.Positions)-1]} {
+		if z != 0 && z != -5 {
+			t.Error("Bad depth", z)
+		}
+	}
+	flat := f.TextMesh("o", 0)
+	if len(flat.Indices)*2 >= len(m.Indices) {
+		t.Error("Flat text has backs or sides", len(flat.Indices), len(m.Indices))
+	}
+}
+
+func TestCurveOutlines(t *testing.T) {
+	fd, err := os.Open("font/font_1_honokamin.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	f, err := NewOutlineFont(fd, 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	curves := f.CurveOutlines("oB")
+	outlines := f.Outlines("oB")
+	if len(curves) != len(outlines) {
+		t.Fatal("Bad outline count", len(curves), len(outlines))
+	}
+	for i, g := range curves {
+		if len(g.Curves) == 0 {
+			t.Errorf("No curves in %q", g.Rune)
+		}
+		// the area between a quadratic curve and its chord is 2/3 of its control triangle
+		area := float32(0)
+		for _, polygon := range g.Polygons {
+			area += signedArea(polygon)
+		}
+		for _, c := range g.Curves {
+			area += cross(c.Start, c.Control, c.End) / 3
+		}
+		flattened := float32(0)
+		for _, contour := range outlines[i].Contours {
+			flattened += signedArea(contour)
+		}
+		if math.Abs(float64(abs32(area)-flattened)) > float64(flattened)*1e-2 {
+			t.Errorf("Bad area of %q: %f, flattened %f", g.Rune, area, flattened)
+		}
+	}
+}
@@ -8,12 +8,17 @@ import (
 	"errors"
 	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 	"image"
 	"image/draw"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"math"
+	"runtime"
 	"sort"
+	"sync"
 )
 
 // RuneRanges specify the rune ranges for ordered disjoint subsets of the ttf
@@ -44,6 +49,35 @@ func (rr RuneRanges) Validate() bool {
 	return true
 }
 
+// NewRuneRanges returns the smallest set of ranges covering exactly the given runes,
+// which may be unordered and contain duplicates.  Consecutive runes share a range.
+func NewRuneRanges(runes []rune) RuneRanges {
+	sorted := append([]rune{}, runes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rr := RuneRanges{}
+	for _, r := range sorted {
+		if last := len(rr) - 1; last >= 0 && r <= rr[last].High+1 {
+			if r > rr[last].High {
+				rr[last].High = r
+			}
+			continue
+		}
+		rr = append(rr, RuneRange{Low: r, High: r})
+	}
+	return rr
+}
+
+// RuneRangesOf returns the ranges covering the runes used by the given strings, such
+// as the string tables of a game, so that only the glyphs needed are rasterized.
+func RuneRangesOf(text ...string) RuneRanges {
+	runes := []rune{}
+	for _, s := range text {
+		runes = append(runes, []rune(s)...)
+	}
+	return NewRuneRanges(runes)
+}
+
 // GetGlyphIndex returns the location of the glyph data within
 // the compressed rune ranges covered by the font
 // EG if runes 0-25, 100-110 are supported by the font then
@@ -60,6 +94,45 @@ func (rr RuneRanges) GetGlyphIndex(char rune) rune {
 	return index
 }
 
+// TruetypeOptions describe how the glyphs of a truetype font are rasterized into an atlas.
+type TruetypeOptions struct {
+	// Scale is the font scale in points.
+	Scale fixed.Int26_6
+
+	// RuneRanges are the runes included in the atlas.
+	RuneRanges RuneRanges
+
+	// Runes, when set, replaces RuneRanges with exactly these runes (see NewRuneRanges).
+	Runes []rune `json:",omitempty"`
+
+	// RunesPerRow is the number of glyphs placed next to one another in the atlas.
+	RunesPerRow fixed.Int26_6
+
+	// AdjustHeight is added to the height of each glyph.
+	AdjustHeight fixed.Int26_6
+
+	// MaxTextureSize limits the width and height of the atlas.  Glyphs that do not fit
+	// are placed on further pages (see FontConfig.Pages).  Zero places every glyph on
+	// a single page no matter how large it becomes.
+	MaxTextureSize int
+
+	// Packing determines how glyphs are arranged within the atlas.  RunesPerRow only
+	// applies to PackGrid.
+	Packing Packing
+
+	// Padding is the number of empty pixels kept on every side of each glyph so that
+	// linear filtering never samples a neighbouring glyph.  Each mipmap level halves the
+	// padding, so mipmapped fonts need 2^levels pixels to remain free of bleeding.
+	Padding int
+
+	// Dilate spreads the color of each glyph's edge into the surrounding transparent pixels,
+	// leaving their alpha untouched, so that filtering does not darken the outline of glyphs.
+	Dilate bool
+
+	// Workers is the number of goroutines rasterizing glyphs, zero using one per cpu.
+	Workers int `json:"-"`
+}
+
 // http://www.freetype.org/freetype2/docs/tutorial/step2.html
 
 // LoadTruetype loads a truetype font from the given stream and
@@ -68,6 +141,21 @@ func (rr RuneRanges) GetGlyphIndex(char rune) rune {
 // The low and high values determine the lower and upper rune limits
 // we should load for this font. For standard ASCII this would be: 32, 127.
 func NewTruetypeFontConfig(r io.Reader, scale fixed.Int26_6, runeRanges RuneRanges, runesPerRow, adjustHeight fixed.Int26_6) (*FontConfig, error) {
+	return NewTruetypeFontConfigWithOptions(r, TruetypeOptions{
+		Scale:        scale,
+		RuneRanges:   runeRanges,
+		RunesPerRow:  runesPerRow,
+		AdjustHeight: adjustHeight,
+	})
+}
+
+// NewTruetypeFontConfigWithOptions loads a truetype font from the given stream and
+// rasterizes the glyphs described by opts.
+func NewTruetypeFontConfigWithOptions(r io.Reader, opts TruetypeOptions) (*FontConfig, error) {
+	if len(opts.Runes) > 0 {
+		opts.RuneRanges = NewRuneRanges(opts.Runes)
+	}
+	scale, runeRanges, runesPerRow, adjustHeight := opts.Scale, opts.RuneRanges, opts.RunesPerRow, opts.AdjustHeight
 	if !runeRanges.Validate() {
 		return nil, errors.New("Invalid rune ranges supplied.")
 	}
@@ -84,7 +172,7 @@ func NewTruetypeFontConfig(r io.Reader, scale fixed.Int26_6, runeRanges RuneRang
 	}
 
 	// Create our FontConfig type.
-	fc := &FontConfig{}
+	fc := &FontConfig{TruetypeOptions: &opts}
 	length := rune(0)
 	for _, r := range runeRanges {
 		length += r.High - r.Low + 1
@@ -92,40 +180,78 @@ func NewTruetypeFontConfig(r io.Reader, scale fixed.Int26_6, runeRanges RuneRang
 	fc.RuneRanges = runeRanges
 	fc.Glyphs = make(Charset, int(length))
 
-	// Create an image, large enough to store all requested glyphs.
-	// The resulting image is set to power of 2 dimensions so it might be wise to adjust the runesPerRow
-	// parameter to ensure that unnecessary space isn't created based on the character set being used
-	gc := fixed.Int26_6(len(fc.Glyphs))
-	runesPerCol := (gc / runesPerRow) + 1
+	if opts.Padding < 0 {
+		return nil, errors.New("Padding should not be negative.")
+	}
 
 	gb := ttf.Bounds(scale)
 	gw := (gb.Max.X - gb.Min.X)
 	gh := (gb.Max.Y - gb.Min.Y) + adjustHeight
+	if opts.Packing == PackSkyline {
+		fc, err = packSkyline(fc, ttf, opts, int(gh))
+		if err == nil && opts.Dilate {
+			dilatePages(fc, opts.Padding)
+		}
+		return fc, err
+	}
 
-	iw := Pow2(uint32(gw * runesPerRow))
-	ih := Pow2(uint32(gh * runesPerCol))
+	// each cell holds a glyph surrounded by its padding
+	pad := fixed.Int26_6(opts.Padding)
+	cellW, cellH := gw+2*pad, gh+2*pad
+
+	// Limit the number of rows and columns so that each page fits within the maximum size.
+	runesPerPage := fixed.Int26_6(len(fc.Glyphs))
+	if opts.MaxTextureSize > 0 {
+		max := fixed.Int26_6(opts.MaxTextureSize)
+		if cellW > max || cellH > max {
+			return nil, errors.New("Glyphs are larger than the maximum texture size.")
+		}
+		if runesPerRow > max/cellW {
+			runesPerRow = max / cellW
+		}
+		if rows := max / cellH; rows*runesPerRow < runesPerPage {
+			runesPerPage = rows * runesPerRow
+		}
+	}
+
+	// Create an image, large enough to store all requested glyphs.
+	// The resulting image is set to power of 2 dimensions so it might be wise to adjust the runesPerRow
+	// parameter to ensure that unnecessary space isn't created based on the character set being used
+	gc := runesPerPage
+	runesPerCol := (gc / runesPerRow) + 1
+
+	iw := Pow2(uint32(cellW * runesPerRow))
+	ih := Pow2(uint32(cellH * runesPerCol))
 	if iw > ih {
 		ih = iw
 	} else {
 		iw = ih
 	}
+	if opts.MaxTextureSize > 0 && iw > uint32(opts.MaxTextureSize) {
+		iw, ih = uint32(opts.MaxTextureSize), uint32(opts.MaxTextureSize)
+	}
 	fg, bg := image.White, image.Transparent
 	rect := image.Rect(0, 0, int(iw), int(ih))
-	fc.Image = image.NewNRGBA(rect)
-	draw.Draw(fc.Image, fc.Image.Bounds(), bg, image.ZP, draw.Src)
 
 	// Use a freetype context to do the drawing.
 	c := freetype.NewContext()
 	c.SetDPI(72) // Do not change this.  It is required in order to have a properly aligned bounding box!!!
 	c.SetFont(ttf)
 	c.SetFontSize(float64(scale))
-	c.SetClip(fc.Image.Bounds())
-	c.SetDst(fc.Image)
 	c.SetSrc(fg)
 
+	newPage := func() {
+		page := image.NewNRGBA(rect)
+		draw.Draw(page, page.Bounds(), bg, image.ZP, draw.Src)
+		fc.Pages = append(fc.Pages, page)
+	}
+	newPage()
+	fc.Image = fc.Pages[0]
+
 	// Iterate over all relevant glyphs in the truetype font and draw them all to the image buffer
 	// Add Glyph objects to track various glyph values
-	var gi fixed.Int26_6
+	var gi, pi fixed.Int26_6
+	jobs := make([]glyphJob, 0, len(fc.Glyphs))
 	var gx, gy fixed.Int26_6
 
 	for _, runeRange := range fc.RuneRanges {
@@ -133,25 +259,42 @@ func NewTruetypeFontConfig(r io.Reader, scale fixed.Int26_6, runeRanges RuneRang
 			index := ttf.Index(ch)
 			metric := ttf.HMetric(scale, index)
 
-			if gi%runesPerRow == 0 {
+			if pi == runesPerPage {
+				newPage()
+				pi = 0
+			}
+			if pi%runesPerRow == 0 {
 				gx = 0
-				if gi > 0 {
-					gy += gh
+				if pi > 0 {
+					gy += cellH
+				} else {
+					gy = 0
 				}
 			} else {
-				gx += gw
+				gx += cellW
 			}
 			fc.Glyphs[gi].Advance = int(metric.AdvanceWidth)
-			fc.Glyphs[gi].X = int(gx)
-			fc.Glyphs[gi].Y = int(gy)
+			fc.Glyphs[gi].X = int(gx + pad)
+			fc.Glyphs[gi].Y = int(gy + pad)
 			fc.Glyphs[gi].Width = int(gw)
 			fc.Glyphs[gi].Height = int(gh)
+			fc.Glyphs[gi].Page = len(fc.Pages) - 1
 
-			pt := freetype.Pt(int(gx), int(gy)+int(c.PointToFixed(float64(scale))>>6))
-			c.DrawString(string(ch), pt)
+			// keeping the ink out of the padding and the neighbouring cells lets the glyphs be
+			// rasterized in parallel
+			job := glyphJob{ch: ch, page: fc.Pages[len(fc.Pages)-1]}
+			job.clip = image.Rect(int(gx+pad), int(gy+pad), int(gx+pad+gw), int(gy+pad+gh))
+			job.pt = freetype.Pt(int(gx+pad), int(gy+pad)+int(c.PointToFixed(float64(scale))>>6))
+			jobs = append(jobs, job)
 			gi++
+			pi++
 		}
 	}
+	rasterizeGlyphs(ttf, scale, jobs, opts.Workers)
+	fc.PageCount = len(fc.Pages)
+	if opts.Dilate {
+		dilatePages(fc, opts.Padding)
+	}
 	return fc, nil
 }
 
@@ -165,3 +308,237 @@ func LoadTruetypeFontConfig(rootPath, name string) (*FontConfig, error) {
 	}
 	return fc, nil
 }
+
+// LoadTruetypeFontConfigFS loads a font config previously saved in rootPath of fsys.
+func LoadTruetypeFontConfigFS(fsys fs.FS, rootPath, name string) (*FontConfig, error) {
+	fc := &FontConfig{}
+	fc.Name = name
+
+	err := fc.LoadFS(fsys, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	return fc, nil
+}
+
+// packSkyline rasterizes each glyph into a rectangle trimmed to its advance and ink height
+func packSkyline(fc *FontConfig, ttf *truetype.Font, opts TruetypeOptions, lineHeight int) (*FontConfig, error) {
+	fc.LineHeight = lineHeight
+	scale := opts.Scale
+	pad := opts.Padding
+
+	c := freetype.NewContext()
+	c.SetDPI(72) // Do not change this.  It is required in order to have a properly aligned bounding box!!!
+	c.SetFont(ttf)
+	c.SetFontSize(float64(scale))
+	c.SetSrc(image.White)
+
+	// the baseline sits this far below the top of a line
+	baseline := int(c.PointToFixed(float64(scale)) >> 6)
+
+	// measure the ink of every glyph, in pixels above the baseline
+	type ink struct{ top, bottom int }
+	inks := make([]ink, len(fc.Glyphs))
+	area := 0
+	var buf truetype.GlyphBuf
+	gi := 0
+	for _, runeRange := range fc.RuneRanges {
+		for ch := runeRange.Low; ch <= runeRange.High; ch++ {
+			index := ttf.Index(ch)
+			fc.Glyphs[gi].Advance = int(ttf.HMetric(scale, index).AdvanceWidth)
+			if err := buf.Load(ttf, scale, index, font.HintingNone); err != nil {
+				return nil, err
+			}
+			if len(buf.Points) > 0 {
+				// round outwards and keep within the line
+				top, bottom := int(buf.Bounds.Max.Y)+1, int(buf.Bounds.Min.Y)-1
+				if top > baseline {
+					top = baseline
+				}
+				if bottom < baseline-lineHeight {
+					bottom = baseline - lineHeight
+				}
+				inks[gi] = ink{top, bottom}
+			}
+			fc.Glyphs[gi].Width = fc.Glyphs[gi].Advance
+			fc.Glyphs[gi].Height = inks[gi].top - inks[gi].bottom
+			fc.Glyphs[gi].OffsetY = lineHeight - (baseline - inks[gi].top) - fc.Glyphs[gi].Height
+			area += (fc.Glyphs[gi].Width + 2*pad) * (fc.Glyphs[gi].Height + 2*pad)
+			gi++
+		}
+	}
+
+	// start with a square page roughly matching the area of the glyphs
+	size := int(Pow2(uint32(math.Sqrt(float64(area) * 1.1))))
+	if opts.MaxTextureSize > 0 && size > opts.MaxTextureSize {
+		size = opts.MaxTextureSize
+	}
+	for i := range fc.Glyphs {
+		for fc.Glyphs[i].Width+2*pad > size || fc.Glyphs[i].Height+2*pad > size {
+			if opts.MaxTextureSize > 0 && size >= opts.MaxTextureSize {
+				return nil, errors.New("Glyphs are larger than the maximum texture size.")
+			}
+			size *= 2
+		}
+	}
+
+	for {
+		fc.Pages = fc.Pages[:0]
+		packer := NewSkyline(size, size)
+		fits := true
+		for i := range fc.Glyphs {
+			g := &fc.Glyphs[i]
+			if g.Width == 0 || g.Height == 0 {
+				g.X, g.Y, g.Page = 0, 0, 0
+				continue
+			}
+			at, ok := packer.Pack(g.Width+2*pad, g.Height+2*pad)
+			if !ok {
+				if opts.MaxTextureSize == 0 || size < opts.MaxTextureSize {
+					fits = false
+					break
+				}
+				// the page is full
+				fc.Pages = append(fc.Pages, nil)
+				packer = NewSkyline(size, size)
+				at, _ = packer.Pack(g.Width+2*pad, g.Height+2*pad)
+			}
+			g.X, g.Y, g.Page = at.X+pad, at.Y+pad, len(fc.Pages)
+		}
+		if fits {
+			break
+		}
+		// grow the page and pack again
+		size *= 2
+		if opts.MaxTextureSize > 0 && size > opts.MaxTextureSize {
+			size = opts.MaxTextureSize
+		}
+	}
+
+	fc.Pages = fc.Pages[:0]
+	for i := range fc.Glyphs {
+		for fc.Glyphs[i].Page >= len(fc.Pages) {
+			page := image.NewNRGBA(image.Rect(0, 0, size, size))
+			draw.Draw(page, page.Bounds(), image.Transparent, image.ZP, draw.Src)
+			fc.Pages = append(fc.Pages, page)
+		}
+	}
+	if len(fc.Pages) == 0 {
+		fc.Pages = append(fc.Pages, image.NewNRGBA(image.Rect(0, 0, size, size)))
+	}
+	fc.Image = fc.Pages[0]
+	fc.PageCount = len(fc.Pages)
+
+	gi = 0
+	jobs := make([]glyphJob, 0, len(fc.Glyphs))
+	for _, runeRange := range fc.RuneRanges {
+		for ch := runeRange.Low; ch <= runeRange.High; ch++ {
+			g := fc.Glyphs[gi]
+			gi++
+			if g.Width == 0 || g.Height == 0 {
+				continue
+			}
+			// clip to the glyph's own rectangle so that neighbours are never touched
+			jobs = append(jobs, glyphJob{
+				ch:   ch,
+				page: fc.Pages[g.Page],
+				clip: image.Rect(g.X, g.Y, g.X+g.Width, g.Y+g.Height),
+				pt:   freetype.Pt(g.X, g.Y+inks[gi-1].top),
+			})
+		}
+	}
+	rasterizeGlyphs(ttf, scale, jobs, opts.Workers)
+	return fc, nil
+}
+
+// glyphJob is a glyph drawn at pt on page, touching no pixel outside of clip
+type glyphJob struct {
+	ch   rune
+	page *image.NRGBA
+	clip image.Rectangle
+	pt   fixed.Point26_6
+}
+
+// rasterizeGlyphs draws the glyphs of jobs on workers goroutines, each with its own
+// context.  Jobs drawn by more than one worker should not overlap.
+func rasterizeGlyphs(ttf *truetype.Font, scale fixed.Int26_6, jobs []glyphJob, workers int) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			c := freetype.NewContext()
+			c.SetDPI(72)
+			c.SetFont(ttf)
+			c.SetFontSize(float64(scale))
+			c.SetSrc(image.White)
+			for i := first; i < len(jobs); i += workers {
+				c.SetDst(jobs[i].page)
+				c.SetClip(jobs[i].clip)
+				c.DrawString(string(jobs[i].ch), jobs[i].pt)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// dilatePages copies the color of opaque pixels into the transparent pixels up to
+// distance pixels away, at least one, without changing their alpha
+func dilatePages(fc *FontConfig, distance int) {
+	if distance < 1 {
+		distance = 1
+	}
+	for _, page := range fc.PageImages() {
+		dilate(page, distance)
+	}
+}
+
+func dilate(img *image.NRGBA, distance int) {
+	b := img.Bounds()
+	filled := make([]bool, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			filled[(y-b.Min.Y)*b.Dx()+x-b.Min.X] = img.Pix[img.PixOffset(x, y)+3] > 0
+		}
+	}
+
+	next := make([]bool, len(filled))
+	for pass := 0; pass < distance; pass++ {
+		copy(next, filled)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				at := (y-b.Min.Y)*b.Dx() + x - b.Min.X
+				if filled[at] {
+					continue
+				}
+				// average the color of the filled neighbours
+				var r, g, bl, n int
+				for ny := y - 1; ny <= y+1; ny++ {
+					for nx := x - 1; nx <= x+1; nx++ {
+						if nx < b.Min.X || ny < b.Min.Y || nx >= b.Max.X || ny >= b.Max.Y {
+							continue
+						}
+						if !filled[(ny-b.Min.Y)*b.Dx()+nx-b.Min.X] {
+							continue
+						}
+						o := img.PixOffset(nx, ny)
+						r, g, bl, n = r+int(img.Pix[o]), g+int(img.Pix[o+1]), bl+int(img.Pix[o+2]), n+1
+					}
+				}
+				if n == 0 {
+					continue
+				}
+				o := img.PixOffset(x, y)
+				img.Pix[o], img.Pix[o+1], img.Pix[o+2] = uint8(r/n), uint8(g/n), uint8(bl/n)
+				next[at] = true
+			}
+		}
+		filled, next = next, filled
+	}
+}
@@ -1,7 +1,11 @@
 package gltext
 
 import (
+	"bytes"
 	"golang.org/x/image/math/fixed"
+	"image"
+	"io"
+	"io/ioutil"
 	"os"
 	"testing"
 )
@@ -119,3 +123,313 @@ func TestGetGlyphIndexEdge(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestGenerateSDF(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	opts := SDFOptions{
+		Scale:       fixed.Int26_6(24),
+		RuneRanges:  RuneRanges{{Low: 'A', High: 'B'}},
+		RunesPerRow: fixed.Int26_6(2),
+		Upsample:    4,
+	}
+	config, err := GenerateSDF(data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.SDF || len(config.Glyphs) != 2 {
+		t.Fatal("Expecting an SDF config with 2 glyphs")
+	}
+
+	// the field should cover both the inside and the outside of the outline
+	inside, outside := false, false
+	g := config.Glyphs[0]
+	for y := g.Y; y < g.Y+g.Height; y++ {
+		for x := g.X; x < g.X+g.Width; x++ {
+			a := config.Image.NRGBAAt(x, y).A
+			inside = inside || a > 128
+			outside = outside || a == 0
+		}
+	}
+	if !inside || !outside {
+		t.Error("Expecting a distance field", inside, outside)
+	}
+}
+
+func TestTruetypePages(t *testing.T) {
+	fd, err := os.Open("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	defer fd.Close()
+
+	opts := TruetypeOptions{
+		Scale:          fixed.Int26_6(24),
+		RuneRanges:     RuneRanges{{Low: 32, High: 127}},
+		RunesPerRow:    fixed.Int26_6(16),
+		MaxTextureSize: 128,
+	}
+	config, err := NewTruetypeFontConfigWithOptions(fd, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.PageCount < 2 || len(config.Pages) != config.PageCount || config.Pages[0] != config.Image {
+		t.Fatal("Expecting several pages", config.PageCount)
+	}
+	for _, page := range config.Pages {
+		if page.Bounds().Dx() > 128 || page.Bounds().Dy() > 128 {
+			t.Error("Page too large", page.Bounds())
+		}
+	}
+	last := config.Glyphs[len(config.Glyphs)-1]
+	if last.Page != config.PageCount-1 || last.Y+last.Height > 128 {
+		t.Error("Bad last glyph", last)
+	}
+}
+
+func TestTruetypeSkyline(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	opts := TruetypeOptions{
+		Scale:       fixed.Int26_6(24),
+		RuneRanges:  RuneRanges{{Low: 32, High: 127}},
+		RunesPerRow: fixed.Int26_6(16),
+	}
+	grid, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Packing = PackSkyline
+	packed, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packed.Image.Bounds().Dx() >= grid.Image.Bounds().Dx() {
+		t.Error("Expecting a smaller atlas", packed.Image.Bounds(), grid.Image.Bounds())
+	}
+	if packed.LineHeight != grid.Glyphs[0].Height {
+		t.Error("Bad line height", packed.LineHeight)
+	}
+
+	// no two glyphs may overlap
+	for i, a := range packed.Glyphs {
+		ra := image.Rect(a.X, a.Y, a.X+a.Width, a.Y+a.Height)
+		if a.OffsetY < 0 || a.OffsetY+a.Height > packed.LineHeight {
+			t.Error("Glyph outside of the line", i, a)
+		}
+		for _, b := range packed.Glyphs[i+1:] {
+			rb := image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height)
+			if !ra.Empty() && !rb.Empty() && ra.Overlaps(rb) {
+				t.Fatal("Overlapping glyphs", a, b)
+			}
+		}
+	}
+}
+
+func TestTruetypePadding(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	for _, packing := range []Packing{PackGrid, PackSkyline} {
+		fc, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), TruetypeOptions{
+			Scale:       fixed.Int26_6(24),
+			RuneRanges:  RuneRanges{{Low: 32, High: 127}},
+			RunesPerRow: fixed.Int26_6(16),
+			Packing:     packing,
+			Padding:     2,
+			Dilate:      true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		inked := make(map[image.Point]bool)
+		for _, g := range fc.Glyphs {
+			if g.Height == 0 {
+				continue
+			}
+			if g.X < 2 || g.Y < 2 {
+				t.Fatal("Glyph within the padding", packing, g)
+			}
+			for y := g.Y; y < g.Y+g.Height; y++ {
+				for x := g.X; x < g.X+g.Width; x++ {
+					inked[image.Pt(x, y)] = true
+				}
+			}
+		}
+		colored := false
+		b := fc.Image.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := fc.Image.NRGBAAt(x, y)
+				if !inked[image.Pt(x, y)] && c.A != 0 {
+					t.Fatal("Ink outside of a glyph", packing, x, y, c)
+				}
+				if c.A == 0 && c.R == 255 {
+					colored = true
+				}
+			}
+		}
+		if !colored {
+			t.Error("Expecting dilated pixels", packing)
+		}
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	fc, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), TruetypeOptions{
+		Scale:       fixed.Int26_6(24),
+		RuneRanges:  RuneRanges{{Low: 32, High: 127}},
+		RunesPerRow: fixed.Int26_6(16),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc.Name = "honokamin"
+	regenerated, err := fc.Regenerate(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if regenerated.Name != fc.Name || len(regenerated.Glyphs) != len(fc.Glyphs) || regenerated.TruetypeOptions.Scale != 24 {
+		t.Error("Expecting an identical config", regenerated.Name, len(regenerated.Glyphs))
+	}
+	if _, err = (&FontConfig{}).Regenerate(bytes.NewReader(data)); err == nil {
+		t.Error("Expecting an error without generation options.")
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	config, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), TruetypeOptions{
+		Scale:          fixed.Int26_6(24),
+		RuneRanges:     RuneRanges{{Low: 32, High: 127}},
+		RunesPerRow:    fixed.Int26_6(16),
+		MaxTextureSize: 128,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "gltext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = config.Save(dir+"/fonts", "honokamin"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadTruetypeFontConfigFS(os.DirFS(dir), "fonts", "honokamin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Pages) != config.PageCount || len(loaded.Glyphs) != len(config.Glyphs) {
+		t.Error("Expecting every page and glyph", len(loaded.Pages), len(loaded.Glyphs))
+	}
+
+	readers := []io.Reader{}
+	for i := 0; i < config.PageCount; i++ {
+		name := "honokamin"
+		if i > 0 {
+			name = pageName(name, i)
+		}
+		fd, err := os.Open(dir + "/fonts/" + name + ".png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fd.Close()
+		readers = append(readers, fd)
+	}
+	fd, err := os.Open(dir + "/fonts/honokamin.config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	loaded = &FontConfig{Name: "honokamin"}
+	if err = loaded.LoadFrom(fd, readers...); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Pages[1].Bounds() != config.Pages[1].Bounds() {
+		t.Error("Bad page", loaded.Pages[1].Bounds())
+	}
+}
+
+func TestNewRuneRanges(t *testing.T) {
+	rr := RuneRangesOf("cab", "zz", "日本")
+	expected := RuneRanges{{Low: 'a', High: 'c'}, {Low: 'z', High: 'z'}, {Low: '日', High: '日'}, {Low: '本', High: '本'}}
+	if len(rr) != len(expected) {
+		t.Fatal("Bad ranges", rr)
+	}
+	for i := range rr {
+		if rr[i] != expected[i] {
+			t.Error("Bad range", i, rr[i])
+		}
+	}
+	if !rr.Validate() || len(NewRuneRanges(nil)) != 0 {
+		t.Error("Expecting valid ranges")
+	}
+
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		panic(err)
+	}
+	fc, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), TruetypeOptions{
+		Scale:       fixed.Int26_6(24),
+		Runes:       []rune("Hello, world"),
+		RunesPerRow: fixed.Int26_6(16),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Glyphs) != 9 || fc.RuneRanges.GetGlyphIndex('w') < 0 || fc.RuneRanges.GetGlyphIndex('x') >= 0 {
+		t.Error("Expecting only the runes requested", len(fc.Glyphs))
+	}
+}
+
+func TestParallelRasterization(t *testing.T) {
+	data, err := ioutil.ReadFile("font/font_1_honokamin.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the default grid packing has no padding
+	for _, packing := range []Packing{PackGrid, PackSkyline} {
+		for _, padding := range []int{0, 1} {
+			opts := TruetypeOptions{
+				Scale:          fixed.Int26_6(24),
+				RuneRanges:     RuneRanges{{Low: 32, High: 127}},
+				RunesPerRow:    fixed.Int26_6(16),
+				MaxTextureSize: 256,
+				Packing:        packing,
+				Padding:        padding,
+				Workers:        1,
+			}
+			sequential, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.Workers = 4
+			parallel, err := NewTruetypeFontConfigWithOptions(bytes.NewReader(data), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(parallel.Pages) != len(sequential.Pages) {
+				t.Fatal("Bad page count", packing, padding, len(parallel.Pages))
+			}
+			for i, page := range parallel.Pages {
+				if !bytes.Equal(page.Pix, sequential.Pages[i].Pix) {
+					t.Error("Expecting identical pages", packing, padding, i)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"sort"
+)
+
+// accessible is how a registered text is presented to assistive technology
+type accessible struct {
+	label string
+	order int
+}
+
+// the texts registered by RegisterAccessible.  Like gl, it should only be used from the
+// thread drawing the texts.
+var accessibleTexts = map[*Text]accessible{}
+
+// AccessibleText describes a registered text for screen readers and ui automation.
+type AccessibleText struct {
+	Text *Text
+
+	// Label is the label given to RegisterAccessible, or the string of the text
+	Label string
+
+	// Min and Max are the top left and bottom right corners of the box holding the text
+	// as drawn, in pixels from the top left corner of the window like cursor positions.
+	Min, Max gltext.Point
+
+	// Order is the reading order given to RegisterAccessible
+	Order int
+}
+
+// RegisterAccessible lists the text in AccessibleTexts until Release or
+// UnregisterAccessible.  label replaces the string of the text when not empty, such as
+// for icons.  Texts are read by increasing order, those of the same order from top to
+// bottom and left to right.
+func (t *Text) RegisterAccessible(label string, order int) {
+	accessibleTexts[t] = accessible{label: label, order: order}
+}
+
+// UnregisterAccessible removes the text from AccessibleTexts
+func (t *Text) UnregisterAccessible() {
+	delete(accessibleTexts, t)
+}
+
+// AccessibleTexts returns the registered texts in reading order, hosts feeding them to
+// accessibility apis such as AccessKit once per frame or when they change.
+func AccessibleTexts() []AccessibleText {
+	texts := make([]AccessibleText, 0, len(accessibleTexts))
+	for t, a := range accessibleTexts {
+		label := a.label
+		if label == "" {
+			label = t.String
+		}
+		X1, X2 := t.drawnBounds()
+		w, h := t.Font.WindowWidth, t.Font.WindowHeight
+		texts = append(texts, AccessibleText{
+			Text:  t,
+			Label: label,
+			Min:   gltext.Point{X: X1.X + w/2, Y: h/2 - X2.Y},
+			Max:   gltext.Point{X: X2.X + w/2, Y: h/2 - X1.Y},
+			Order: a.order,
+		})
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		a, b := texts[i], texts[j]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		if a.Min.Y != b.Min.Y {
+			return a.Min.Y < b.Min.Y
+		}
+		return a.Min.X < b.Min.X
+	})
+	return texts
+}
@@ -0,0 +1,26 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SetWorldPosition places the text over the point p of a 3D scene seen through
+// viewProjection, the product of the projection and view matrices of its camera.  The
+// text keeps its size in pixels whatever the distance to the camera, as expected of the
+// labels of editor gizmos and map markers, and should be positioned again whenever the
+// camera moves.  False is returned, leaving the text in place, when p is behind the camera.
+func (t *Text) SetWorldPosition(p mgl32.Vec3, viewProjection mgl32.Mat4) bool {
+	clip := viewProjection.Mul4x1(p.Vec4(1))
+	if clip.W() <= 0 {
+		return false
+	}
+	t.SetPosition(mgl32.Vec2{
+		clip.X() / clip.W() * t.Font.WindowWidth / 2,
+		clip.Y() / clip.W() * t.Font.WindowHeight / 2,
+	})
+	return true
+}
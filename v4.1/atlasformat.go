@@ -0,0 +1,97 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"image"
+)
+
+// AtlasFormat is the texture format the atlas pages of a font are stored in.  Every
+// shader only samples the alpha of the atlas, coverage or distance, so the single channel
+// formats keep it alone and return it as alpha.
+type AtlasFormat int
+
+const (
+	AFRGBA AtlasFormat = iota // 4 bytes per texel, the default
+	AFR8                      // 1 byte per texel
+	AFBC4                     // half a byte per texel, compressed as RGTC1 blocks
+)
+
+// bitsPerTexel returns the memory used by a texel stored in the format
+func (af AtlasFormat) bitsPerTexel() int {
+	switch af {
+	case AFR8:
+		return 8
+	case AFBC4:
+		return 4
+	}
+	return 32
+}
+
+// SetAtlasFormat uploads the atlas pages again in the given format.  The compressed format
+// falls back to AFR8 when the driver rejects it, AtlasFormat reporting the format used.
+// Compressed pages have no mipmaps, mipmapped filters sampling them linearly instead.
+func (f *Font) SetAtlasFormat(format AtlasFormat) error {
+	if format == f.atlasFormat {
+		return nil
+	}
+	if f.sources != nil {
+		// used by Init
+		f.atlasFormat = format
+		return nil
+	}
+	previous, previousFormat := f.pageTextureIDs, f.atlasFormat
+	f.atlasFormat = format
+	if err := f.loadConfig(f.Config); err != nil {
+		f.atlasFormat = previousFormat
+		return err
+	}
+	gl.DeleteTextures(int32(len(previous)), &previous[0])
+	f.SetTextureFilter(f.minFilter, f.magFilter)
+	return nil
+}
+
+// AtlasFormat returns the format the atlas pages are stored in
+func (f *Font) AtlasFormat() AtlasFormat {
+	return f.atlasFormat
+}
+
+// uploadPage fills the bound texture with the page in the format of the font
+func (f *Font) uploadPage(page *image.NRGBA) {
+	w, h := int32(page.Bounds().Dx()), int32(page.Bounds().Dy())
+	if f.atlasFormat == AFRGBA {
+		pix := page.Pix
+		if f.premultiplied {
+			pix = gltext.PremultiplyAlpha(page).Pix
+		}
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, w, h, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+		return
+	}
+
+	// premultiplied colors are the alpha repeated, which the swizzle also produces
+	alpha := gltext.AlphaPixels(page)
+	swizzle := [4]int32{gl.ONE, gl.ONE, gl.ONE, gl.RED}
+	if f.premultiplied {
+		swizzle = [4]int32{gl.RED, gl.RED, gl.RED, gl.RED}
+	}
+	gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+
+	if f.atlasFormat == AFBC4 {
+		// clear previous errors so that only the upload is checked
+		for gl.GetError() != gl.NO_ERROR {
+		}
+		blocks := gltext.EncodeBC4(alpha, int(w), int(h))
+		gl.CompressedTexImage2D(gl.TEXTURE_2D, 0, gl.COMPRESSED_RED_RGTC1, w, h, 0, int32(len(blocks)), gl.Ptr(blocks))
+		if gl.GetError() == gl.NO_ERROR {
+			return
+		}
+		f.atlasFormat = AFR8
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, w, h, 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(alpha))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+}
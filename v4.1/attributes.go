@@ -0,0 +1,87 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"errors"
+	"fmt"
+	"github.com/4ydx/gltext/layout"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// AttributeFunc fills values, which holds one float per component of the attribute, for a
+// vertex of a glyph.  Vertices are numbered counter-clockwise from the lower left corner (0 to 3).
+type AttributeFunc func(glyph layout.Glyph, vertex int, values []float32)
+
+type customAttribute struct {
+	name     string
+	size     int32
+	fill     AttributeFunc
+	location uint32
+	vbo      uint32
+	data     []float32
+}
+
+// AddAttribute registers an additional per-vertex attribute consumed by a custom shader
+// (see NewFontWithShaders).  size is the number of float components (1 to 4).  The values
+// are generated by fill whenever the string changes, allowing per-character effects to be
+// computed entirely on the GPU.
+func (t *Text) AddAttribute(name string, size int32, fill AttributeFunc) error {
+	if size < 1 || size > 4 {
+		return errors.New("Attribute size should be between 1 and 4.")
+	}
+	location := gl.GetAttribLocation(t.Font.program, gl.Str(name+"\x00"))
+	if location < 0 {
+		return fmt.Errorf("shader program does not use attribute %s", name)
+	}
+	a := &customAttribute{name: name, size: size, fill: fill, location: uint32(location)}
+
+	gl.GenBuffers(1, &a.vbo)
+	gl.BindVertexArray(t.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, a.vbo)
+	gl.EnableVertexAttribArray(a.location)
+	gl.VertexAttribPointer(a.location, size, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	t.attributes = append(t.attributes, a)
+	t.fillAttribute(a)
+	return nil
+}
+
+// fillAttributes is called by SetString once the glyphs have been laid out
+func (t *Text) fillAttributes() {
+	for _, a := range t.attributes {
+		t.fillAttribute(a)
+	}
+}
+
+func (t *Text) fillAttribute(a *customAttribute) {
+	// match the vertex count of the position data, 4 vertices per quad
+	count := t.vboIndexCount / 4 * int(a.size)
+	if count == 0 {
+		return
+	}
+	if len(a.data) != count {
+		a.data = make([]float32, count)
+	}
+	at := 0
+	for _, glyph := range t.glyphs {
+		for vertex := 0; vertex < 4; vertex++ {
+			a.fill(glyph, vertex, a.data[at:at+int(a.size)])
+			at += int(a.size)
+		}
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, a.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(a.data), gl.Ptr(a.data), gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+func (t *Text) releaseAttributes() {
+	for _, a := range t.attributes {
+		gl.DeleteBuffers(1, &a.vbo)
+	}
+	t.attributes = nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// BlendMode determines how a text is combined with what was drawn before it.
+type BlendMode int
+
+const (
+	BMAlpha         BlendMode = iota // colors are weighted by the alpha of the glyphs
+	BMPremultiplied                  // for fonts created by NewFontPremultiplied and shaders writing premultiplied colors
+	BMAdditive                       // colors are added, brightening the background
+	BMHost                           // blending is left as enabled and set up by the caller
+)
+
+// factors returns the source and destination factors of glBlendFunc
+func (m BlendMode) factors() (src, dst uint32) {
+	switch m {
+	case BMPremultiplied:
+		return gl.ONE, gl.ONE_MINUS_SRC_ALPHA
+	case BMAdditive:
+		return gl.SRC_ALPHA, gl.ONE
+	}
+	return gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA
+}
+
+// begin enables blending with the mode
+func (m BlendMode) begin() {
+	if m == BMHost {
+		return
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(m.factors())
+}
+
+// end disables the blending enabled by begin
+func (m BlendMode) end() {
+	if m != BMHost {
+		gl.Disable(gl.BLEND)
+	}
+}
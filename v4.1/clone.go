@@ -0,0 +1,94 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Clone creates a text sharing the font of t and a copy of its layout, style and
+// custom attributes, but with its own GL buffers.  Repeated labels can be stamped
+// out this way without laying out the string again.  The clone should be released
+// separately.
+func (t *Text) Clone() *Text {
+	c := NewText(t.Font, t.ScaleMin, t.ScaleMax)
+	t.copyTo(c)
+	if c.vertexFormat != VFFloat32 || c.vertexColored {
+		gl.BindVertexArray(c.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+		c.setVertexPointers()
+		gl.BindVertexArray(0)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	}
+	for _, a := range t.attributes {
+		// the location is known to be valid since t uses it
+		c.AddAttribute(a.name, a.size, a.fill)
+	}
+	c.upload(nil, nil)
+	if c.static {
+		// the buffers were allocated for dynamic drawing
+		c.static = false
+		c.SetStatic(true)
+	}
+	if gltext.IsDebug {
+		c.BoundingBox, _ = loadBoundingBox(c.Font, c.X1, c.X2)
+	}
+	c.SetPosition(c.Position)
+	return c
+}
+
+// copyTo copies the cpu side state of t to c while keeping the gl objects of c
+func (t *Text) copyTo(c *Text) {
+	vao, vbo, ebo := c.vao, c.vbo, c.ebo
+	*c = *t
+	c.vao, c.vbo, c.ebo = vao, vbo, ebo
+	c.vboCapacity, c.eboCapacity = 0, 0
+	c.packedData, c.interleavedData = nil, nil
+	c.attributes = nil
+	c.BoundingBox = nil
+	c.transformedData = nil
+	c.decorations = nil
+	if c.paletteColor != "" {
+		palettedTexts[c] = struct{}{}
+	}
+	if a, ok := accessibleTexts[t]; ok {
+		accessibleTexts[c] = a
+	}
+
+	vboData, eboData := t.vertices()
+	c.vboData = append([]float32(nil), vboData...)
+	c.eboData = append([]int32(nil), eboData...)
+	c.CharSpacing = append([]float32(nil), t.CharSpacing...)
+	c.glyphs = append(c.glyphs[:0:0], t.glyphs...)
+	c.glyphBounds = append(c.glyphBounds[:0:0], t.glyphBounds...)
+	c.styleRuns = append(c.styleRuns[:0:0], t.styleRuns...)
+	c.effects = append(c.effects[:0:0], t.effects...)
+	c.glyphOffsets = append(c.glyphOffsets[:0:0], t.glyphOffsets...)
+	c.highlights = append(c.highlights[:0:0], t.highlights...)
+	c.squiggles = append(c.squiggles[:0:0], t.squiggles...)
+	c.links = append(c.links[:0:0], t.links...)
+	c.linkClicks = append(c.linkClicks[:0:0], t.linkClicks...)
+
+	c.pageRanges = make([]pageRange, len(t.pageRanges))
+	for i, r := range t.pageRanges {
+		r.quads = append([]int(nil), r.quads...)
+		c.pageRanges[i] = r
+	}
+	if t.uniforms != nil {
+		c.uniforms = make(map[string]interface{}, len(t.uniforms))
+		for name, value := range t.uniforms {
+			c.uniforms[name] = value
+		}
+	}
+	if t.scaleTween != nil {
+		tween := *t.scaleTween
+		c.scaleTween = &tween
+	}
+	if t.positionTween != nil {
+		tween := *t.positionTween
+		c.positionTween = &tween
+	}
+}
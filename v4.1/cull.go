@@ -0,0 +1,63 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Culled reports whether the text lies outside of the window of its font, or of the
+// camera rect given by CullX1 and CullX2, and would not be drawn when Cull is set.
+func (t *Text) Culled() bool {
+	return t.culled(t.Font.OrthographicMatrix)
+}
+
+// culled compares the drawn bounds of the text with the area seen through projection
+func (t *Text) culled(projection mgl32.Mat4) bool {
+	X1, X2 := t.CullX1, t.CullX2
+	if X1 == X2 {
+		X1, X2 = viewArea(projection)
+	}
+	B1, B2 := t.drawnBounds()
+	return B2.X < X1.X || B1.X > X2.X || B2.Y < X1.Y || B1.Y > X2.Y
+}
+
+// viewArea returns the corners of the area projected onto clip space
+func viewArea(projection mgl32.Mat4) (X1, X2 gltext.Point) {
+	inverse := projection.Inv()
+	a := inverse.Mul4x1(mgl32.Vec4{-1, -1, 0, 1})
+	b := inverse.Mul4x1(mgl32.Vec4{1, 1, 0, 1})
+	return bounds(gltext.Point{X: a.X(), Y: a.Y()}, gltext.Point{X: b.X(), Y: b.Y()})
+}
+
+// drawnBounds returns the box holding the bounding box of the text as drawn, scaled
+// and rotated
+func (t *Text) drawnBounds() (X1, X2 gltext.Point) {
+	return bounds(
+		t.drawnPoint(t.X1.X, t.X1.Y), t.drawnPoint(t.X2.X, t.X1.Y),
+		t.drawnPoint(t.X2.X, t.X2.Y), t.drawnPoint(t.X1.X, t.X2.Y),
+	)
+}
+
+// bounds returns the lower left and upper right corners of the box holding points
+func bounds(points ...gltext.Point) (X1, X2 gltext.Point) {
+	X1, X2 = points[0], points[0]
+	for _, p := range points[1:] {
+		if p.X < X1.X {
+			X1.X = p.X
+		}
+		if p.Y < X1.Y {
+			X1.Y = p.Y
+		}
+		if p.X > X2.X {
+			X2.X = p.X
+		}
+		if p.Y > X2.Y {
+			X2.Y = p.Y
+		}
+	}
+	return
+}
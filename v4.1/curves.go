@@ -0,0 +1,221 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var curveVertexShaderSource string = `
+#version 330
+
+uniform mat4 orthographic_matrix;
+uniform mat4 model_matrix;
+
+in vec2 position;
+in vec2 curve;
+
+out vec2 fragment_curve;
+
+void main() {
+  fragment_curve = curve;
+  gl_Position = orthographic_matrix * model_matrix * vec4(position, 0, 1);
+}
+` + "\x00"
+
+// fragments outside of the curve, where u^2 > v, are not part of the glyph.  triangles
+// filling polygons use (0, 1) and are never discarded.
+var curveFragmentShaderSource string = `
+#version 330
+
+uniform vec4 color;
+
+in vec2 fragment_curve;
+out vec4 fragment_color;
+
+void main() {
+  if (fragment_curve.x * fragment_curve.x - fragment_curve.y > 0.0) {
+    discard;
+  }
+  fragment_color = color;
+}
+` + "\x00"
+
+// CurveText draws text from the quadratic curves of its glyph outlines, testing in the
+// fragment shader which side of a curve each pixel lies on, so that edges stay exact at
+// any zoom without flattening or an atlas.
+//
+// Glyphs are filled through the stencil buffer, which the framebuffer must have.  The
+// stencil values under the text are left at zero.
+type CurveText struct {
+	Font *gltext.OutlineFont
+
+	String string
+	Color  mgl32.Vec4
+
+	// Position is the screen position of the center of the text, in pixels from the
+	// center of the window.  Scale multiplies the size the outline font was loaded at.
+	Position mgl32.Vec2
+	Scale    float32
+
+	// the bounding box of the laid out text, centered on (0,0)
+	X1 gltext.Point
+	X2 gltext.Point
+
+	OrthographicMatrix mgl32.Mat4
+
+	program      uint32
+	vao, vbo     uint32
+	vertices     []float32 // x, y, u, v per vertex; the stencil triangles then the covering quad
+	dirty        bool
+	colorUniform int32
+	modelUniform int32
+	orthoUniform int32
+}
+
+// NewCurveText creates an empty curve text drawn in a window of the given size
+func NewCurveText(f *gltext.OutlineFont, windowWidth, windowHeight float32) (*CurveText, error) {
+	program, err := NewProgram(curveVertexShaderSource, curveFragmentShaderSource)
+	if err != nil {
+		return nil, err
+	}
+	c := &CurveText{Font: f, Color: mgl32.Vec4{1, 1, 1, 1}, Scale: 1, program: program}
+	c.ResizeWindow(windowWidth, windowHeight)
+	c.colorUniform = gl.GetUniformLocation(program, gl.Str("color\x00"))
+	c.modelUniform = gl.GetUniformLocation(program, gl.Str("model_matrix\x00"))
+	c.orthoUniform = gl.GetUniformLocation(program, gl.Str("orthographic_matrix\x00"))
+
+	gl.GenVertexArrays(1, &c.vao)
+	gl.GenBuffers(1, &c.vbo)
+	gl.BindVertexArray(c.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+	position := uint32(gl.GetAttribLocation(program, gl.Str("position\x00")))
+	curve := uint32(gl.GetAttribLocation(program, gl.Str("curve\x00")))
+	gl.EnableVertexAttribArray(position)
+	gl.VertexAttribPointer(position, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(curve)
+	gl.VertexAttribPointer(curve, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(2*4))
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	return c, nil
+}
+
+// ResizeWindow updates the projection after the window changed size
+func (c *CurveText) ResizeWindow(width, height float32) {
+	c.OrthographicMatrix = mgl32.Ortho2D(-width/2, width/2, -height/2, height/2)
+}
+
+// SetString builds the triangles of s.  Nothing is done when s did not change.
+func (c *CurveText) SetString(s string) {
+	if s == c.String && c.vertices != nil {
+		return
+	}
+	c.String = s
+	c.vertices, c.X1, c.X2 = curveVertices(c.Font.CurveOutlines(s))
+	c.dirty = true
+}
+
+// curveVertices returns a fan per contour polygon and a triangle per curve, centered on
+// (0,0), followed by the two triangles covering their bounds.  The fans and curves count the
+// winding of every pixel in the stencil buffer, which the cover then fills.
+func curveVertices(outlines []gltext.GlyphCurves) (vertices []float32, x1, x2 gltext.Point) {
+	points := []gltext.Point{}
+	for _, g := range outlines {
+		for _, polygon := range g.Polygons {
+			points = append(points, polygon...)
+		}
+		for _, curve := range g.Curves {
+			points = append(points, curve.Control)
+		}
+	}
+	vertices = []float32{}
+	if len(points) == 0 {
+		return vertices, x1, x2
+	}
+	x1, x2 = bounds(points...)
+	center := gltext.Point{X: (x1.X + x2.X) / 2, Y: (x1.Y + x2.Y) / 2}
+	add := func(p gltext.Point, u, v float32) {
+		vertices = append(vertices, p.X-center.X, p.Y-center.Y, u, v)
+	}
+	for _, g := range outlines {
+		for _, polygon := range g.Polygons {
+			for i := 1; i+1 < len(polygon); i++ {
+				add(polygon[0], 0, 1)
+				add(polygon[i], 0, 1)
+				add(polygon[i+1], 0, 1)
+			}
+		}
+		for _, curve := range g.Curves {
+			add(curve.Start, 0, 0)
+			add(curve.Control, 0.5, 0)
+			add(curve.End, 1, 1)
+		}
+	}
+	corners := [4]gltext.Point{x1, {X: x2.X, Y: x1.Y}, x2, {X: x1.X, Y: x2.Y}}
+	for _, i := range [6]int{0, 1, 2, 0, 2, 3} {
+		add(corners[i], 0, 1)
+	}
+	x1 = gltext.Point{X: x1.X - center.X, Y: x1.Y - center.Y}
+	x2 = gltext.Point{X: x2.X - center.X, Y: x2.Y - center.Y}
+	return vertices, x1, x2
+}
+
+// Draw fills the glyphs, uploading them first when the string changed
+func (c *CurveText) Draw() {
+	if len(c.vertices) == 0 {
+		return
+	}
+	if c.dirty {
+		gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, 4*len(c.vertices), gl.Ptr(c.vertices), gl.STATIC_DRAW)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		c.dirty = false
+	}
+	model := mgl32.Translate3D(c.Position.X(), c.Position.Y(), 0).Mul4(mgl32.Scale3D(c.Scale, c.Scale, 1))
+
+	gl.UseProgram(c.program)
+	gl.UniformMatrix4fv(c.orthoUniform, 1, false, &c.OrthographicMatrix[0])
+	gl.UniformMatrix4fv(c.modelUniform, 1, false, &model[0])
+	gl.Uniform4fv(c.colorUniform, 1, &c.Color[0])
+	gl.BindVertexArray(c.vao)
+
+	// culling would drop the triangles that wind the other way
+	culling := gl.IsEnabled(gl.CULL_FACE)
+	gl.Disable(gl.CULL_FACE)
+	stencilVertices := int32(len(c.vertices)/4 - 6)
+
+	// count the nonzero winding of every pixel without touching the color buffer
+	gl.Enable(gl.STENCIL_TEST)
+	gl.StencilMask(0xff)
+	gl.ColorMask(false, false, false, false)
+	gl.StencilFunc(gl.ALWAYS, 0, 0xff)
+	gl.StencilOpSeparate(gl.FRONT, gl.KEEP, gl.KEEP, gl.INCR_WRAP)
+	gl.StencilOpSeparate(gl.BACK, gl.KEEP, gl.KEEP, gl.DECR_WRAP)
+	gl.DrawArrays(gl.TRIANGLES, 0, stencilVertices)
+
+	// fill the covered pixels, clearing the stencil behind them
+	gl.ColorMask(true, true, true, true)
+	gl.StencilFunc(gl.NOTEQUAL, 0, 0xff)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.ZERO)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.DrawArrays(gl.TRIANGLES, stencilVertices, 6)
+	gl.Disable(gl.BLEND)
+	gl.Disable(gl.STENCIL_TEST)
+
+	if culling {
+		gl.Enable(gl.CULL_FACE)
+	}
+	gl.BindVertexArray(0)
+}
+
+// Release releases the program and buffers of the text
+func (c *CurveText) Release() {
+	gl.DeleteBuffers(1, &c.vbo)
+	gl.DeleteVertexArrays(1, &c.vao)
+	gl.DeleteProgram(c.program)
+}
@@ -5,10 +5,15 @@
 package v41
 
 import (
+	"errors"
+	"fmt"
 	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"image"
+	"io"
+	"strings"
 )
 
 var fontVertexShaderSource string = `
@@ -17,25 +22,47 @@ var fontVertexShaderSource string = `
 uniform mat4 scale_matrix;
 uniform mat4 orthographic_matrix;
 uniform vec2 final_position;
+uniform vec2 glyph_origin;
 
 in vec4 centered_position;
 in vec2 uv;
+in vec4 glyph_color;
 
 out vec2 fragment_uv;
+out vec2 clip_position;
+out vec4 fragment_glyph_color;
 
 // The orthographic projection uses a lower left-hand point of (0,0)
-// 1) We center the text on screen.
+// 1) We center the text on screen, the vertices being laid out from glyph_origin.
 // 2) We perform othographic transformation and then scaling.
 // 3) We move the text to its final resting place.
 // This is all pretty standard I would imagine, but it took me a bit to sort out what has to happen :P
 
 void main() {
   fragment_uv = uv;
-  vec4 scaled = scale_matrix * orthographic_matrix * centered_position;
+  fragment_glyph_color = glyph_color;
+  vec4 scaled = scale_matrix * orthographic_matrix * (centered_position + vec4(glyph_origin, 0.0, 0.0));
   gl_Position = vec4(scaled.x + final_position.x, scaled.y + final_position.y, scaled.z, scaled.w);
+  clip_position = gl_Position.xy / gl_Position.w;
 }
 ` + "\x00"
 
+// edge_mask fades glyphs out towards the edges of the rectangle mask_outer, being fully
+// visible within mask_inner.  Both are given as lower left and upper right corners in
+// clip space.  See Text.SetFadeMask.
+var edgeMaskShaderSource string = `
+uniform vec4 mask_outer;
+uniform vec4 mask_inner;
+
+in vec2 clip_position;
+
+float edge_mask() {
+  vec2 low  = clamp((clip_position - mask_outer.xy) / max(mask_inner.xy - mask_outer.xy, 0.000001), 0.0, 1.0);
+  vec2 high = clamp((mask_outer.zw - clip_position) / max(mask_outer.zw - mask_inner.zw, 0.000001), 0.0, 1.0);
+  return low.x * low.y * high.x * high.y;
+}
+`
+
 var fontFragmentShaderSource string = `
 #version 330
 
@@ -44,30 +71,161 @@ uniform float fadeout;
 uniform vec4 fragment_color_adjustment;
 
 in vec2 fragment_uv;
+in vec4 fragment_glyph_color;
 out vec4 fragment_color;
-
+` + edgeMaskShaderSource + `
 void main() {
+  vec4 adjustment = fragment_color_adjustment * fragment_glyph_color;
   vec4 color     = texture(fragment_texture, fragment_uv);
-  color.xyz      = fragment_color_adjustment.xyz;
-	color.w        = color.w - fadeout;
+  color.xyz      = adjustment.xyz;
+	color.w        = color.w * adjustment.w * edge_mask() - fadeout;
   fragment_color = color;
 }
 ` + "\x00"
 
+// The distance field stores the outline at 0.5.  Antialiasing covers roughly one
+// screen pixel, whatever the scale of the text, widened by sdf_softness.
+// Lowering sdf_threshold makes the glyphs bolder.
+var sdfFragmentShaderSource string = `
+#version 330
+
+uniform sampler2D fragment_texture;
+uniform float fadeout;
+uniform vec4 fragment_color_adjustment;
+uniform float sdf_threshold;
+uniform float sdf_softness;
+
+in vec2 fragment_uv;
+in vec4 fragment_glyph_color;
+out vec4 fragment_color;
+` + edgeMaskShaderSource + `
+void main() {
+  vec4 adjustment = fragment_color_adjustment * fragment_glyph_color;
+  float distance = texture(fragment_texture, fragment_uv).w;
+  float width    = max(fwidth(distance) + sdf_softness, 0.0001);
+  float alpha    = smoothstep(sdf_threshold - width, sdf_threshold + width, distance);
+  fragment_color = vec4(adjustment.xyz, alpha * adjustment.w * edge_mask() - fadeout);
+}
+` + "\x00"
+
+// Glyphs of fonts created by NewFontPremultiplied are stored with premultiplied alpha and
+// their shaders write premultiplied colors, which avoids dark fringes around the
+// antialiased edges of colored glyphs when the atlas is filtered.
+var premultipliedFontFragmentShaderSource string = `
+#version 330
+
+uniform sampler2D fragment_texture;
+uniform float fadeout;
+uniform vec4 fragment_color_adjustment;
+
+in vec2 fragment_uv;
+in vec4 fragment_glyph_color;
+out vec4 fragment_color;
+` + edgeMaskShaderSource + `
+void main() {
+  float alpha    = texture(fragment_texture, fragment_uv).w;
+  vec4 adjustment = fragment_color_adjustment * fragment_glyph_color;
+  alpha          = clamp(alpha * adjustment.w * edge_mask() - fadeout, 0.0, 1.0);
+  fragment_color = vec4(adjustment.xyz * alpha, alpha);
+}
+` + "\x00"
+
+var premultipliedSdfFragmentShaderSource string = `
+#version 330
+
+uniform sampler2D fragment_texture;
+uniform float fadeout;
+uniform vec4 fragment_color_adjustment;
+uniform float sdf_threshold;
+uniform float sdf_softness;
+
+in vec2 fragment_uv;
+in vec4 fragment_glyph_color;
+out vec4 fragment_color;
+` + edgeMaskShaderSource + `
+void main() {
+  float distance = texture(fragment_texture, fragment_uv).w;
+  float width    = max(fwidth(distance) + sdf_softness, 0.0001);
+  float alpha    = smoothstep(sdf_threshold - width, sdf_threshold + width, distance);
+  vec4 adjustment = fragment_color_adjustment * fragment_glyph_color;
+  alpha          = clamp(alpha * adjustment.w * edge_mask() - fadeout, 0.0, 1.0);
+  fragment_color = vec4(adjustment.xyz * alpha, alpha);
+}
+` + "\x00"
+
 type Font struct {
 	Config         *gltext.FontConfig // Character set for this font.
 	textureID      uint32             // Holds the glyph texture id.
+	pageTextureIDs []uint32           // Texture ids of every atlas page, the first being textureID.
 	maxGlyphWidth  int                // Largest glyph width.
 	maxGlyphHeight int                // Largest glyph height.
 	program        uint32             // program compiled from shaders
+	rectProgram    uint32             // program drawing the rectangles of Rects, compiled when first needed
+	gridProgram    uint32             // program drawing terminal grids, compiled when first needed
+	pickProgram    uint32             // program drawing the pick ids of texts, compiled when first needed
+
+	// the element buffer shared by texts drawing their quads in order and the number of
+	// quads it holds indices for, see quadIndices
+	quadEBO      uint32
+	quadCapacity int
+
+	// increased whenever Reload replaces the atlas so that texts are laid out again
+	generation int
+
+	// layouts of the strings drawn with the font
+	layouts layout.Cache
+
+	// the state shared by texts drawn between RenderState.Begin and End
+	state *RenderState
+
+	// the atlas is uploaded with premultiplied alpha
+	premultiplied bool
+
+	// the texture format of the atlas pages
+	atlasFormat AtlasFormat
+
+	// sources is set until Init creates the gl objects of a lazy font, initErr being the
+	// error it returned
+	sources *fontSources
+	initErr error
+
+	// shared is set for fonts uploaded on another context, see NewFontShared
+	shared *sharedUpload
+
+	// the os thread owning the gl objects, recorded when gltext.ThreadChecks is set
+	thread uint64
+
+	// the work done drawing texts, timed on the gpu when timer is set
+	stats Stats
+	timer *gpuTimer
+
+	// SRGBFramebuffer should be set when texts are drawn with GL_FRAMEBUFFER_SRGB enabled.
+	// Text colors are given in sRGB and converted to linear light before being blended,
+	// so that they match the colors of mockups once the framebuffer encodes them again.
+	SRGBFramebuffer bool
+
+	// filters applied to every page texture
+	minFilter TextureFilter
+	magFilter TextureFilter
 
 	// attributes
 	centeredPositionAttribute uint32 // vertex centered_position required for scaling around the orthographic projections center
 	uvAttribute               uint32 // texture position
+	colorAttribute            uint32 // glyph color, when vertexColors is set
+
+	// vertexColors is set when the program reads the color of each glyph from the vbo rather
+	// than from fragment_color_adjustment alone, so that differently colored texts batch and
+	// colored runs are drawn at once, see Text.interleavesColors
+	vertexColors bool
 
 	// The final screen position post-scaling
 	finalPositionUniform int32
 
+	// The offset centering the vertices, which are kept in the coordinates of the layout so
+	// that a change of the extent of a text does not move all of them.  Programs without it
+	// are sent centered vertices, see Text.interleave.
+	originUniform int32
+
 	// Position of the shaders fragment texture variable
 	fragmentTextureUniform int32
 
@@ -75,6 +233,14 @@ type Font struct {
 	colorUniform   int32
 	fadeoutUniform int32
 
+	// Outline threshold and antialiasing width of distance field fonts
+	sdfThresholdUniform int32
+	sdfSoftnessUniform  int32
+
+	// Edges of the fade mask of texts
+	maskOuterUniform int32
+	maskInnerUniform int32
+
 	// View matrix
 	orthographicMatrixUniform int32
 	OrthographicMatrix        mgl32.Mat4
@@ -82,12 +248,36 @@ type Font struct {
 	// Scale the resulting text
 	scaleMatrixUniform int32
 
+	// locations of uniforms set by Text.SetUniform and the values they are restored to
+	// once the text is drawn
+	uniformLocations map[string]int32
+	uniformDefaults  map[string]interface{}
+
 	textureWidth  float32
 	textureHeight float32
 	WindowWidth   float32
 	WindowHeight  float32
 }
 
+// lineHeight is the vertical distance between lines of text
+func (f *Font) lineHeight() float32 {
+	if f.Config != nil && f.Config.LineHeight > 0 {
+		return float32(f.Config.LineHeight)
+	}
+	return float32(f.maxGlyphHeight)
+}
+
+// cellAdvance is the widest advance of the glyphs, used as the cell width of monospace text
+func (f *Font) cellAdvance() float32 {
+	cell := 0
+	for _, glyph := range f.Config.Glyphs {
+		if glyph.Advance > cell {
+			cell = glyph.Advance
+		}
+	}
+	return float32(cell)
+}
+
 func (f *Font) GetTextureWidth() float32 {
 	return f.textureWidth
 }
@@ -96,74 +286,206 @@ func (f *Font) GetTextureHeight() float32 {
 	return f.textureHeight
 }
 
+// NewFont creates a font from the given config.  Signed distance field configs
+// are drawn with a shader that keeps the glyph outlines sharp when scaled.
 func NewFont(config *gltext.FontConfig) (f *Font, err error) {
-	if config == nil {
-		panic("Nil config")
+	if config != nil && config.SDF {
+		return NewFontWithShaders(config, fontVertexShaderSource, sdfFragmentShaderSource)
 	}
-	f = &Font{}
-	f.Config = config
-
-	// Resize image to next power-of-two.
-	config.Image = gltext.Pow2Image(config.Image).(*image.NRGBA)
-	ib := config.Image.Bounds()
+	return NewFontWithShaders(config, fontVertexShaderSource, fontFragmentShaderSource)
+}
 
-	f.textureWidth = float32(ib.Dx())
-	f.textureHeight = float32(ib.Dy())
+// NewFontPremultiplied creates a font whose atlas is stored with premultiplied alpha.
+// Its texts are blended with BMPremultiplied.
+func NewFontPremultiplied(config *gltext.FontConfig) (f *Font, err error) {
+	fragmentShaderSource := premultipliedFontFragmentShaderSource
+	if config != nil && config.SDF {
+		fragmentShaderSource = premultipliedSdfFragmentShaderSource
+	}
+	return newFont(config, fontVertexShaderSource, fragmentShaderSource, true)
+}
 
-	for _, glyph := range config.Glyphs {
-		if glyph.Width > f.maxGlyphWidth {
-			f.maxGlyphWidth = glyph.Width
-		}
-		if glyph.Height > f.maxGlyphHeight {
-			f.maxGlyphHeight = glyph.Height
-		}
+// outputColor returns the color sent to the shader for the sRGB color c
+func (f *Font) outputColor(c mgl32.Vec3, alpha float32) mgl32.Vec4 {
+	if f.SRGBFramebuffer {
+		c = mgl32.Vec3{gltext.SRGBToLinear(c[0]), gltext.SRGBToLinear(c[1]), gltext.SRGBToLinear(c[2])}
 	}
+	return c.Vec4(alpha)
+}
 
-	// save to disk for testing
-	if gltext.IsDebug {
-		err = gltext.SaveImage(".", "Debug", config.Image)
-		if err != nil {
-			return f, err
-		}
+// Premultiplied reports whether the atlas of the font is stored with premultiplied alpha
+func (f *Font) Premultiplied() bool {
+	return f.premultiplied
+}
+
+// NewFontWithShaders creates a font drawn by a custom shader program.  The shaders
+// receive the same inputs as the built-in ones:
+//
+//	in vec4 centered_position          vertex position, text centered around (0,0)    (required)
+//	in vec2 uv                         texture position of the glyph                  (required)
+//	in vec4 glyph_color                color and alpha of the glyph
+//	uniform mat4 orthographic_matrix   window projection                              (required)
+//	uniform mat4 scale_matrix          text scaling applied after the projection      (required)
+//	uniform vec2 final_position        screen position in the range -1 to 1           (required)
+//	uniform vec2 glyph_origin          added to centered_position to center the text
+//	uniform sampler2D fragment_texture glyph texture
+//	uniform vec4 fragment_color_adjustment text color and alpha
+//	uniform float fadeout              alpha to subtract while fading out
+//	uniform vec4 mask_outer, mask_inner edges of the fade mask in clip space
+//
+// The required inputs must be used by the shaders, otherwise an error is returned.
+// Programs using glyph_color multiply it with fragment_color_adjustment, which then only
+// carries the transparency of the text; those that do not receive each color as a uniform.
+// Programs without glyph_origin receive centered positions, each change of the extent of
+// a text sending all of its vertices again.
+// Sources do not need to be null terminated.
+func NewFontWithShaders(config *gltext.FontConfig, vertexShaderSource, fragmentShaderSource string) (f *Font, err error) {
+	return newFont(config, vertexShaderSource, fragmentShaderSource, false)
+}
+
+func newFont(config *gltext.FontConfig, vertexShaderSource, fragmentShaderSource string, premultiplied bool) (f *Font, err error) {
+	f = newLazyFont(config, vertexShaderSource, fragmentShaderSource, premultiplied)
+	return f, f.Init()
+}
+
+// newLazyFont creates a font whose gl objects are created by Init
+func newLazyFont(config *gltext.FontConfig, vertexShaderSource, fragmentShaderSource string, premultiplied bool) *Font {
+	if config == nil {
+		panic("Nil config")
 	}
+	f := &Font{minFilter: FilterLinear, magFilter: FilterLinear, premultiplied: premultiplied}
+	f.sources = &fontSources{config: config, vertex: vertexShaderSource, fragment: fragmentShaderSource}
+	pow2Pages(config)
+	f.setMetrics(config)
+	watchFontLeak(f)
+	return f
+}
 
-	// generate texture
-	gl.GenTextures(1, &f.textureID)
-	gl.BindTexture(gl.TEXTURE_2D, f.textureID)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexImage2D(
-		gl.TEXTURE_2D,
-		0,
-		gl.RGBA,
-		int32(ib.Dx()),
-		int32(ib.Dy()),
-		0,
-		gl.RGBA,
-		gl.UNSIGNED_BYTE,
-		gl.Ptr(config.Image.Pix),
-	)
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+// createObjects uploads the atlas and compiles the program of the font
+func (f *Font) createObjects(config *gltext.FontConfig, vertexShaderSource, fragmentShaderSource string) (err error) {
+	if err = f.loadConfig(config); err != nil {
+		return err
+	}
 
 	// create shader program and define attributes and uniforms
-	f.program, err = NewProgram(fontVertexShaderSource, fontFragmentShaderSource)
+	f.program, err = NewProgram(nullTerminate(vertexShaderSource), nullTerminate(fragmentShaderSource))
 	if err != nil {
-		return f, err
+		return err
+	}
+	if err = validateProgram(f.program); err != nil {
+		gl.DeleteProgram(f.program)
+		return err
 	}
 
 	// attributes
 	f.centeredPositionAttribute = uint32(gl.GetAttribLocation(f.program, gl.Str("centered_position\x00")))
 	f.uvAttribute = uint32(gl.GetAttribLocation(f.program, gl.Str("uv\x00")))
+	colorAttribute := gl.GetAttribLocation(f.program, gl.Str("glyph_color\x00"))
+	f.colorAttribute, f.vertexColors = uint32(colorAttribute), colorAttribute >= 0
 
 	// uniforms
 	f.finalPositionUniform = gl.GetUniformLocation(f.program, gl.Str("final_position\x00"))
+	f.originUniform = gl.GetUniformLocation(f.program, gl.Str("glyph_origin\x00"))
 	f.orthographicMatrixUniform = gl.GetUniformLocation(f.program, gl.Str("orthographic_matrix\x00"))
 	f.scaleMatrixUniform = gl.GetUniformLocation(f.program, gl.Str("scale_matrix\x00"))
 	f.fragmentTextureUniform = gl.GetUniformLocation(f.program, gl.Str("fragment_texture\x00"))
 	f.colorUniform = gl.GetUniformLocation(f.program, gl.Str("fragment_color_adjustment\x00"))
 	f.fadeoutUniform = gl.GetUniformLocation(f.program, gl.Str("fadeout\x00"))
+	f.sdfThresholdUniform = gl.GetUniformLocation(f.program, gl.Str("sdf_threshold\x00"))
+	f.sdfSoftnessUniform = gl.GetUniformLocation(f.program, gl.Str("sdf_softness\x00"))
+	f.maskOuterUniform = gl.GetUniformLocation(f.program, gl.Str("mask_outer\x00"))
+	f.maskInnerUniform = gl.GetUniformLocation(f.program, gl.Str("mask_inner\x00"))
+	return nil
+}
+
+// loadConfig uploads the pages of config and adopts its metrics.  The font is
+// left unchanged when an error is returned.
+func (f *Font) loadConfig(config *gltext.FontConfig) (err error) {
+	pow2Pages(config)
+	ib := config.Image.Bounds()
+
+	// save to disk for testing
+	if gltext.IsDebug {
+		err = gltext.SaveImage(".", "Debug", config.Image)
+		if err != nil {
+			return err
+		}
+	}
 
-	return f, nil
+	// generate a texture per page, every page shares the size of the first
+	pages := config.PageImages()
+	textureIDs := make([]uint32, len(pages))
+	gl.GenTextures(int32(len(pages)), &textureIDs[0])
+	for i, page := range pages {
+		if page.Bounds() != ib {
+			gl.DeleteTextures(int32(len(pages)), &textureIDs[0])
+			return errors.New("Atlas pages should all have the same size.")
+		}
+		gl.BindTexture(gl.TEXTURE_2D, textureIDs[i])
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+
+		// never sample beyond the edges of the atlas
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		f.uploadPage(page)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	f.pageTextureIDs = textureIDs
+	f.textureID = textureIDs[0]
+	f.setMetrics(config)
+	return nil
+}
+
+// pow2Pages resizes the first page of config to the next power-of-two
+func pow2Pages(config *gltext.FontConfig) {
+	config.Image = gltext.Pow2Image(config.Image).(*image.NRGBA)
+	if len(config.Pages) > 0 {
+		config.Pages[0] = config.Image
+	}
+}
+
+// setMetrics adopts config and the sizes of its atlas and glyphs
+func (f *Font) setMetrics(config *gltext.FontConfig) {
+	ib := config.Image.Bounds()
+	f.Config = config
+	f.textureWidth = float32(ib.Dx())
+	f.textureHeight = float32(ib.Dy())
+	f.maxGlyphWidth, f.maxGlyphHeight = 0, 0
+	for _, glyph := range config.Glyphs {
+		if glyph.Width > f.maxGlyphWidth {
+			f.maxGlyphWidth = glyph.Width
+		}
+		if glyph.Height > f.maxGlyphHeight {
+			f.maxGlyphHeight = glyph.Height
+		}
+	}
+}
+
+// Reload rebuilds the atlas and metrics of the font from an updated truetype file, using the
+// options the current config was generated with.  Texts using the font are laid out again
+// the next time they are drawn.  On error the font is left unchanged.
+func (f *Font) Reload(r io.Reader) error {
+	if err := f.Init(); err != nil {
+		return err
+	}
+	config, err := f.Config.Regenerate(r)
+	if err != nil {
+		return err
+	}
+	if config.SDF != f.Config.SDF {
+		return errors.New("Reloaded config should use the same shader.")
+	}
+	previous := f.pageTextureIDs
+	if err = f.loadConfig(config); err != nil {
+		return err
+	}
+	gl.DeleteTextures(int32(len(previous)), &previous[0])
+	f.SetTextureFilter(f.minFilter, f.magFilter)
+	f.layouts.Reset()
+	f.generation++
+	return nil
 }
 
 func (f *Font) ResizeWindow(width float32, height float32) {
@@ -172,6 +494,93 @@ func (f *Font) ResizeWindow(width float32, height float32) {
 	f.OrthographicMatrix = mgl32.Ortho2D(-f.WindowWidth/2, f.WindowWidth/2, -f.WindowHeight/2, f.WindowHeight/2)
 }
 
+// TextureFilter determines how the glyph texture is sampled
+type TextureFilter int32
+
+const (
+	FilterLinear  TextureFilter = gl.LINEAR
+	FilterNearest TextureFilter = gl.NEAREST
+
+	// Mipmapped filters only apply to minification.  Mipmaps are generated when selected.
+	FilterLinearMipmapLinear   TextureFilter = gl.LINEAR_MIPMAP_LINEAR
+	FilterNearestMipmapNearest TextureFilter = gl.NEAREST_MIPMAP_NEAREST
+)
+
+// SetTextureFilter changes how the glyph texture is sampled when the text is drawn
+// smaller (min) or larger (mag) than the atlas.  Fonts use FilterLinear by default;
+// pixel-art fonts should use FilterNearest to stay crisp.
+func (f *Font) SetTextureFilter(min, mag TextureFilter) {
+	if mag != FilterNearest {
+		mag = FilterLinear
+	}
+	if f.atlasFormat == AFBC4 && (min == FilterLinearMipmapLinear || min == FilterNearestMipmapNearest) {
+		min = FilterLinear
+	}
+	f.minFilter, f.magFilter = min, mag
+	if f.sources != nil {
+		// applied by Init
+		return
+	}
+	for _, id := range f.pageTextureIDs {
+		gl.BindTexture(gl.TEXTURE_2D, id)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(min))
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(mag))
+		if min == FilterLinearMipmapLinear || min == FilterNearestMipmapNearest {
+			gl.GenerateMipmap(gl.TEXTURE_2D)
+		}
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// Release deletes the atlas textures and shader programs of the font, which should no
+// longer be used afterwards.  Texts drawn with the font are released separately.
 func (f *Font) Release() {
-	gl.DeleteTextures(1, &f.textureID)
+	unwatchLeak(f)
+	if f.sources != nil {
+		// no gl object was created
+		f.sources, f.initErr = nil, errors.New("Font was released.")
+		return
+	}
+	gl.DeleteTextures(int32(len(f.pageTextureIDs)), &f.pageTextureIDs[0])
+	gl.DeleteProgram(f.program)
+	if f.rectProgram != 0 {
+		gl.DeleteProgram(f.rectProgram)
+	}
+	if f.gridProgram != 0 {
+		gl.DeleteProgram(f.gridProgram)
+	}
+	if f.pickProgram != 0 {
+		gl.DeleteProgram(f.pickProgram)
+	}
+	if f.quadEBO != 0 {
+		gl.DeleteBuffers(1, &f.quadEBO)
+		f.quadEBO, f.quadCapacity = 0, 0
+	}
+	f.SetGPUTiming(false)
+}
+
+func nullTerminate(source string) string {
+	if strings.HasSuffix(source, "\x00") {
+		return source
+	}
+	return source + "\x00"
+}
+
+// validateProgram makes sure that the inputs the font binds are present in the program
+func validateProgram(program uint32) error {
+	missing := []string{}
+	for _, name := range []string{"centered_position", "uv"} {
+		if gl.GetAttribLocation(program, gl.Str(name+"\x00")) < 0 {
+			missing = append(missing, "attribute "+name)
+		}
+	}
+	for _, name := range []string{"orthographic_matrix", "scale_matrix", "final_position"} {
+		if gl.GetUniformLocation(program, gl.Str(name+"\x00")) < 0 {
+			missing = append(missing, "uniform "+name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("shader program does not use %s", strings.Join(missing, ", "))
+	}
+	return nil
 }
@@ -0,0 +1,72 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+)
+
+// fontSources holds what Init needs to create the gl objects of a lazy font
+type fontSources struct {
+	config           *gltext.FontConfig
+	vertex, fragment string
+}
+
+// NewFontLazy creates a font like NewFont without touching gl, so that fonts can be
+// created and configured by goroutines loading assets.  Its atlas and program are created
+// by Init, which NewText and the first draw of a text call on the gl thread.  Metrics,
+// filters and the atlas format can be set beforehand.
+func NewFontLazy(config *gltext.FontConfig) *Font {
+	if config != nil && config.SDF {
+		return newLazyFont(config, fontVertexShaderSource, sdfFragmentShaderSource, false)
+	}
+	return newLazyFont(config, fontVertexShaderSource, fontFragmentShaderSource, false)
+}
+
+// Init creates the gl objects of a font created by NewFontLazy and must be called on the
+// gl thread.  Fonts created by the other constructors are already initialized.  Calling it
+// again returns the error of the first call.  Fonts created by NewFontShared are uploaded
+// by Upload instead, Init returning an error until it completed.
+func (f *Font) Init() error {
+	if f.shared != nil {
+		if err := f.shared.wait(); err != nil {
+			return err
+		}
+		// the thread of the render context owns the font from now on
+		f.claimThread()
+		return nil
+	}
+	return f.init()
+}
+
+// init creates the gl objects of a lazy font on the current context
+func (f *Font) init() error {
+	if f.sources == nil {
+		return f.initErr
+	}
+	sources := f.sources
+	f.sources = nil
+	if f.initErr = f.createObjects(sources.config, sources.vertex, sources.fragment); f.initErr != nil {
+		return f.initErr
+	}
+	if f.shared == nil {
+		f.claimThread()
+	}
+	if f.minFilter != FilterLinear || f.magFilter != FilterLinear {
+		f.SetTextureFilter(f.minFilter, f.magFilter)
+	}
+	return nil
+}
+
+// Initialized reports whether the gl objects of the font were created, and for fonts
+// created by NewFontShared uploaded
+func (f *Font) Initialized() bool {
+	if s := f.shared; s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.done && s.err == nil
+	}
+	return f.sources == nil && f.initErr == nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
+	"github.com/go-gl/mathgl/mgl32"
+	"image/color"
+)
+
+// SetMarkup displays the text of m using its style runs and links.  Bold and italic
+// runs require a font family (see SetFamily).
+func (t *Text) SetMarkup(m gltext.Markup) {
+	t.styleRuns = m.Runs
+	t.links = m.Links
+	t.linkClicks = nil
+	t.SetString("%s", m.Text)
+}
+
+// SetBBCode displays text marked up with BBCode tags, see gltext.ParseBBCode.
+func (t *Text) SetBBCode(s string) {
+	t.SetMarkup(gltext.ParseBBCode(s))
+}
+
+// Links returns the links of the markup being displayed.
+func (t *Text) Links() []gltext.Link {
+	return t.links
+}
+
+// LinkAt returns the link drawn under p, given in the coordinates of SetPosition.
+func (t *Text) LinkAt(p mgl32.Vec2) (gltext.Link, bool) {
+	if i := t.linkIndexAt(p); i >= 0 {
+		return t.links[i], true
+	}
+	return gltext.Link{}, false
+}
+
+// scaledFace enlarges the advance of the glyphs of a face
+type scaledFace struct {
+	layout.Face
+	size float32
+}
+
+func (f scaledFace) Glyph(r rune) (int, float32, bool) {
+	index, advance, ok := f.Face.Glyph(r)
+	return index, advance * f.size, ok
+}
+
+func colorVec3(c color.Color) mgl32.Vec3 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return mgl32.Vec3{float32(n.R) / 255, float32(n.G) / 255, float32(n.B) / 255}
+}
+
+// SetANSIString displays text holding ANSI escape sequences using their colors and
+// styles, see gltext.ParseANSI.
+func (t *Text) SetANSIString(s string) {
+	t.SetMarkup(gltext.ParseANSI(s))
+}
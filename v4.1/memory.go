@@ -0,0 +1,53 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
+	"unsafe"
+)
+
+// MemoryUsage is an estimate in bytes of the memory held by a font or text.
+type MemoryUsage struct {
+	Texture int // gpu memory of the atlas pages, including their mipmaps
+	Buffers int // gpu memory of vertex and element buffers
+	CPU     int // memory of the images, vertices and glyphs kept by Go
+}
+
+// Total returns the sum of the gpu and cpu memory
+func (m MemoryUsage) Total() int {
+	return m.Texture + m.Buffers + m.CPU
+}
+
+// MemoryUsage estimates the memory held by the atlas, config and shared element buffer of
+// the font.  Programs and the layouts cached by the font are not counted.
+func (f *Font) MemoryUsage() (m MemoryUsage) {
+	page := int(f.textureWidth) * int(f.textureHeight) * f.atlasFormat.bitsPerTexel() / 8
+	if f.minFilter == FilterLinearMipmapLinear || f.minFilter == FilterNearestMipmapNearest {
+		// the mipmap chain adds a third of the base level
+		page += page / 3
+	}
+	m.Texture = page * len(f.pageTextureIDs)
+	if f.Config != nil {
+		for _, img := range f.Config.PageImages() {
+			m.CPU += cap(img.Pix)
+		}
+		m.CPU += cap(f.Config.Glyphs) * int(unsafe.Sizeof(gltext.Glyph{}))
+	}
+	m.Buffers = f.quadCapacity * 6 * 4
+	return m
+}
+
+// MemoryUsage estimates the memory held by the buffers and laid out glyphs of the text,
+// not including the font it shares with other texts.
+func (t *Text) MemoryUsage() (m MemoryUsage) {
+	m.Buffers = t.vboCapacity/4*t.vertexValues()*t.vertexFormat.valueSize() + t.eboCapacity*4
+	m.CPU = (cap(t.vboData)+cap(t.eboData)+cap(t.transformedData)+cap(t.interleavedData)+cap(t.CharSpacing))*4 + cap(t.packedData)*2 +
+		cap(t.glyphs)*int(unsafe.Sizeof(layout.Glyph{})) +
+		cap(t.glyphBounds)*int(unsafe.Sizeof([2]gltext.Point{})) +
+		cap(t.glyphOffsets)*int(unsafe.Sizeof(gltext.Point{}))
+	return m
+}
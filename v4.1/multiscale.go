@@ -0,0 +1,118 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"errors"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"sort"
+)
+
+// MultiScaleFont holds atlases of the same typeface baked at several pixel sizes.  Texts
+// drawn with it switch to the atlas closest to their size on screen, avoiding both the
+// blur of upscaled small atlases and the aliasing of downscaled big ones.  Distance field
+// fonts scale well on their own and rarely need this.
+type MultiScaleFont struct {
+	// Fonts ordered by increasing line height
+	Fonts []*Font
+}
+
+// NewMultiScaleFont groups fonts baked at different sizes.  Every font is drawn using the
+// same kind of shader, so all of them must either be SDF fonts or not.
+func NewMultiScaleFont(fonts ...*Font) (*MultiScaleFont, error) {
+	if len(fonts) == 0 {
+		return nil, errors.New("At least one font is required.")
+	}
+	for _, f := range fonts[1:] {
+		if f.Config.SDF != fonts[0].Config.SDF {
+			return nil, errors.New("Fonts should all be SDF fonts or not.")
+		}
+	}
+	sorted := append([]*Font(nil), fonts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].lineHeight() < sorted[j].lineHeight()
+	})
+	return &MultiScaleFont{Fonts: sorted}, nil
+}
+
+// Pick returns the font whose line height is the closest to lineHeight pixels, comparing
+// their ratio so that a font drawn at half its size is as close as one drawn at double.
+func (m *MultiScaleFont) Pick(lineHeight float32) *Font {
+	best, distance := m.Fonts[0], math.Inf(1)
+	for _, f := range m.Fonts {
+		if d := math.Abs(math.Log(float64(lineHeight / f.lineHeight()))); d < distance {
+			best, distance = f, d
+		}
+	}
+	return best
+}
+
+// ResizeWindow resizes the window of every font
+func (m *MultiScaleFont) ResizeWindow(width float32, height float32) {
+	for _, f := range m.Fonts {
+		f.ResizeWindow(width, height)
+	}
+}
+
+// Release releases every font
+func (m *MultiScaleFont) Release() {
+	for _, f := range m.Fonts {
+		f.Release()
+	}
+}
+
+// MultiScaleText is a text drawn with the font of a MultiScaleFont closest to its size on
+// screen.  Its scale is relative to the font currently used, which is picked again
+// whenever the scale changes.
+type MultiScaleText struct {
+	*Text
+
+	Fonts *MultiScaleFont
+}
+
+// NewMultiScaleText creates a text drawn with the smallest font of m using the same
+// scaling boundaries as NewText.
+func NewMultiScaleText(m *MultiScaleFont, scaleMin, scaleMax float32) *MultiScaleText {
+	return &MultiScaleText{Text: NewText(m.Fonts[0], scaleMin, scaleMax), Fonts: m}
+}
+
+// SetScale scales the text, switching fonts when another one is closer to the size of
+// the text on screen
+func (m *MultiScaleText) SetScale(s float32) bool {
+	if !m.Text.SetScale(s) {
+		return false
+	}
+	m.pickFont()
+	return true
+}
+
+// Update advances the animations of the text, switching fonts as the scale changes
+func (m *MultiScaleText) Update(dt float32) {
+	m.Text.Update(dt)
+	m.pickFont()
+}
+
+// pickFont changes the font of the text to the one closest to its size on screen, keeping
+// that size by adjusting the scale and its boundaries
+func (m *MultiScaleText) pickFont() {
+	t := m.Text
+	f := m.Fonts.Pick(t.Scale * t.Font.lineHeight())
+	if f == t.Font {
+		return
+	}
+	ratio := t.Font.lineHeight() / f.lineHeight()
+	t.setFont(f)
+	t.ScaleMin *= ratio
+	t.ScaleMax *= ratio
+	t.Scale *= ratio
+	t.scaleMatrix = mgl32.Scale3D(t.Scale, t.Scale, t.Scale)
+	if t.scaleTween != nil {
+		t.scaleFrom *= ratio
+		t.scaleTo *= ratio
+	}
+	t.SetString("%s", t.String)
+	t.SetPosition(t.Position)
+}
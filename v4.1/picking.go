@@ -0,0 +1,115 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// the attributes are placed at the locations of the font program so that the vao of a
+// text can be drawn by either program.  gl_VertexID / 4 is the quad being drawn.
+var pickVertexShaderSource string = `
+#version 330
+
+uniform mat4 scale_matrix;
+uniform mat4 orthographic_matrix;
+uniform vec2 final_position;
+uniform vec2 glyph_origin;
+
+layout(location = %d) in vec4 centered_position;
+layout(location = %d) in vec2 uv;
+
+out vec2 fragment_uv;
+flat out int fragment_quad;
+
+void main() {
+  fragment_uv = uv;
+  fragment_quad = gl_VertexID / 4;
+  vec4 scaled = scale_matrix * orthographic_matrix * (centered_position + vec4(glyph_origin, 0.0, 0.0));
+  gl_Position = vec4(scaled.x + final_position.x, scaled.y + final_position.y, scaled.z, scaled.w);
+}
+` + "\x00"
+
+// the id is stored in red and green, the quad plus one in blue and alpha.  both the
+// alpha of bitmap fonts and the distance of sdf fonts are above 0.5 within the glyphs.
+var pickFragmentShaderSource string = `
+#version 330
+
+uniform sampler2D fragment_texture;
+uniform int pick_id;
+
+in vec2 fragment_uv;
+flat in int fragment_quad;
+out vec4 fragment_color;
+
+void main() {
+  if (texture(fragment_texture, fragment_uv).w < 0.5) {
+    discard;
+  }
+  int quad = fragment_quad + 1;
+  fragment_color = vec4((pick_id >> 8) & 255, pick_id & 255, (quad >> 8) & 255, quad & 255) / 255.0;
+}
+` + "\x00"
+
+// DrawPick draws the glyphs of the text encoding id and the glyph under each pixel
+// into the color buffer, for applications identifying what is under the cursor
+// by reading back a picking framebuffer.  The framebuffer should be cleared to zero and
+// hold 8 bits per channel.  Blending is left disabled.  See DecodePick.
+func (t *Text) DrawPick(id uint16) error {
+	f := t.Font
+	if f.pickProgram == 0 {
+		vertexShaderSource := fmt.Sprintf(pickVertexShaderSource, f.centeredPositionAttribute, f.uvAttribute)
+		program, err := NewProgram(vertexShaderSource, pickFragmentShaderSource)
+		if err != nil {
+			return err
+		}
+		f.pickProgram = program
+	}
+	drawCount := t.drawCount()
+	if drawCount <= 0 {
+		return nil
+	}
+	gl.UseProgram(f.pickProgram)
+	uniform := func(name string) int32 {
+		return gl.GetUniformLocation(f.pickProgram, gl.Str(name+"\x00"))
+	}
+	scaleMatrix := t.pivotedScale(f.OrthographicMatrix)
+	gl.UniformMatrix4fv(uniform("scale_matrix"), 1, false, &scaleMatrix[0])
+	gl.UniformMatrix4fv(uniform("orthographic_matrix"), 1, false, &f.OrthographicMatrix[0])
+	gl.Uniform2fv(uniform("final_position"), 1, &t.finalPosition[0])
+	origin := t.shaderOrigin()
+	gl.Uniform2fv(uniform("glyph_origin"), 1, &origin[0])
+	gl.Uniform1i(uniform("fragment_texture"), 0)
+	gl.Uniform1i(uniform("pick_id"), int32(id))
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindVertexArray(t.vao)
+	for _, r := range t.drawRanges(drawCount) {
+		gl.BindTexture(gl.TEXTURE_2D, r.font.pageTextureIDs[r.page])
+		gl.DrawElements(gl.TRIANGLES, int32(r.count*6), gl.UNSIGNED_INT, gl.PtrOffset(r.first*4))
+	}
+	gl.BindVertexArray(0)
+	return nil
+}
+
+// DecodePick reads a pixel of a picking framebuffer written by DrawPick.  ok is false
+// when no glyph was drawn there.  The index of the rune of the glyph is returned by the
+// PickedRune method of the text drawn with id.
+func DecodePick(pixel [4]uint8) (id uint16, glyph int, ok bool) {
+	quad := int(pixel[2])<<8 | int(pixel[3])
+	if quad == 0 {
+		return 0, 0, false
+	}
+	return uint16(pixel[0])<<8 | uint16(pixel[1]), quad - 1, true
+}
+
+// PickedRune returns the index of the rune drawn by the glyph returned by DecodePick.
+func (t *Text) PickedRune(glyph int) int {
+	if glyph < 0 || glyph >= len(t.glyphs) {
+		return -1
+	}
+	return t.glyphs[glyph].Index
+}
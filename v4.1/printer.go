@@ -0,0 +1,104 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Printer draws text without managing Text objects.  Strings printed during a frame
+// are drawn by Draw.  Texts are cached between frames so that a string printed again
+// is not laid out or uploaded again, and texts that go unused are recycled.
+//
+//	p.Printf(-390, 290, "fps %.1f", fps)
+//	p.Draw()
+type Printer struct {
+	Font *Font
+
+	// Color of the strings printed from now on
+	Color mgl32.Vec3
+
+	// Anchor is the point of the strings printed from now on placed at (x, y).
+	// Defaults to AnchorTopLeft.
+	Anchor Anchor
+
+	// Spare is the number of unused texts kept for reuse.  Defaults to 16.
+	Spare int
+
+	cache  map[string][]*Text // texts drawn last frame by string
+	frame  map[string][]*Text // texts printed this frame by string
+	queue  []*Text
+	unused []*Text
+}
+
+// NewPrinter creates a printer drawing white text with the given font
+func NewPrinter(f *Font) *Printer {
+	return &Printer{
+		Font:   f,
+		Color:  mgl32.Vec3{1, 1, 1},
+		Anchor: AnchorTopLeft,
+		Spare:  16,
+		cache:  make(map[string][]*Text),
+		frame:  make(map[string][]*Text),
+	}
+}
+
+// Printf queues a string to be drawn at the next call to Draw.  x and y are the
+// position of the text's anchor point, as given to Text.SetPosition.
+func (p *Printer) Printf(x, y float32, format string, args ...interface{}) {
+	s := fmt.Sprintf(format, args...)
+
+	var t *Text
+	if cached := p.cache[s]; len(cached) > 0 {
+		t, p.cache[s] = cached[len(cached)-1], cached[:len(cached)-1]
+	} else if len(p.unused) > 0 {
+		t, p.unused = p.unused[len(p.unused)-1], p.unused[:len(p.unused)-1]
+		t.SetString("%s", s)
+	} else {
+		t = NewText(p.Font, 1, 1)
+		t.SetString("%s", s)
+	}
+	t.anchor = p.Anchor
+	t.SetColor(p.Color)
+	t.SetPosition(mgl32.Vec2{x, y})
+	p.frame[s] = append(p.frame[s], t)
+	p.queue = append(p.queue, t)
+}
+
+// Draw draws the strings printed since the previous call
+func (p *Printer) Draw() {
+	for _, t := range p.queue {
+		t.Draw()
+	}
+	p.queue = p.queue[:0]
+
+	// texts that were not printed this frame become available for other strings
+	for s, texts := range p.cache {
+		p.unused = append(p.unused, texts...)
+		delete(p.cache, s)
+	}
+	for len(p.unused) > p.Spare {
+		p.unused[len(p.unused)-1].Release()
+		p.unused = p.unused[:len(p.unused)-1]
+	}
+	p.cache, p.frame = p.frame, p.cache
+}
+
+// Release releases every text held by the printer
+func (p *Printer) Release() {
+	for _, texts := range []map[string][]*Text{p.cache, p.frame} {
+		for s, cached := range texts {
+			for _, t := range cached {
+				t.Release()
+			}
+			delete(texts, s)
+		}
+	}
+	for _, t := range p.unused {
+		t.Release()
+	}
+	p.queue, p.unused = nil, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderState sets up the program, projection and blending shared by the texts of a
+// font once per frame.  Texts drawn between Begin and End only send the uniforms that
+// changed before drawing their vertex array, which matters when drawing hundreds of
+// texts.  Texts with another blend mode are set up completely, after which the blending
+// of the state is enabled again.  Other gl state should not be changed between Begin
+// and End.
+//
+//	state := v41.NewRenderState(font)
+//	state.Begin()
+//	for _, text := range texts {
+//		text.Draw()
+//	}
+//	state.End()
+type RenderState struct {
+	Font *Font
+
+	// Blend is the blend mode of the texts drawn
+	Blend BlendMode
+
+	projection mgl32.Mat4
+
+	// the program and blending were replaced since Begin, EG by Rects.Draw
+	interrupted bool
+
+	// the values last sent, valid once their flag is set
+	texture, color, fadeout bool
+	lastTexture             uint32
+	lastColor               mgl32.Vec4
+	lastFadeout             float32
+}
+
+// NewRenderState creates the render state of the texts of f
+func NewRenderState(f *Font) *RenderState {
+	return &RenderState{Font: f}
+}
+
+// Begin binds the program of the font, its projection and enables blending with Blend.
+func (s *RenderState) Begin() {
+	f := s.Font
+	s.projection = f.OrthographicMatrix
+	s.texture, s.color, s.fadeout, s.interrupted = false, false, false, false
+	f.state = s
+
+	gl.UseProgram(f.program)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.Uniform1i(f.fragmentTextureUniform, 0)
+	gl.UniformMatrix4fv(f.orthographicMatrixUniform, 1, false, &s.projection[0])
+	s.Blend.begin()
+}
+
+// End restores the gl state changed by Begin and the texts drawn since.
+func (s *RenderState) End() {
+	s.Font.state = nil
+	gl.BindVertexArray(0)
+	s.Blend.end()
+}
+
+// covers reports whether a text drawn with projection and blend can rely on the program,
+// projection and blending set up by the state
+func (s *RenderState) covers(projection mgl32.Mat4, blend BlendMode) bool {
+	return s != nil && !s.interrupted && s.projection == projection && s.Blend == blend
+}
+
+// interrupt records that the program or blending of the state were replaced
+func (s *RenderState) interrupt() {
+	if s != nil {
+		s.interrupted = true
+	}
+}
+
+// restore sets the projection and blending of the state again after a text was drawn
+// with others, forgetting the values sent since
+func (s *RenderState) restore() {
+	s.texture, s.color, s.fadeout, s.interrupted = false, false, false, false
+	gl.UniformMatrix4fv(s.Font.orthographicMatrixUniform, 1, false, &s.projection[0])
+	s.Blend.begin()
+}
+
+// textureChanged records the texture bound and reports whether it differs from the
+// previous one.  Without a state every value is sent.
+func (s *RenderState) textureChanged(texture uint32) bool {
+	if s == nil {
+		return true
+	}
+	changed := !s.texture || s.lastTexture != texture
+	s.texture, s.lastTexture = true, texture
+	return changed
+}
+
+func (s *RenderState) colorChanged(color mgl32.Vec4) bool {
+	if s == nil {
+		return true
+	}
+	changed := !s.color || s.lastColor != color
+	s.color, s.lastColor = true, color
+	return changed
+}
+
+func (s *RenderState) fadeoutChanged(fadeout float32) bool {
+	if s == nil {
+		return true
+	}
+	changed := !s.fadeout || s.lastFadeout != fadeout
+	s.fadeout, s.lastFadeout = true, fadeout
+	return changed
+}
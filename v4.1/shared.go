@@ -0,0 +1,79 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"errors"
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"sync"
+)
+
+// sharedUpload hands a font uploaded on a loader context over to the render context
+type sharedUpload struct {
+	mu    sync.Mutex
+	done  bool
+	err   error
+	fence uintptr // signaled once the uploads of the loader context are complete
+}
+
+// NewFontShared creates a font like NewFontLazy whose atlas and program are uploaded by
+// Upload on a loader context sharing its objects with the render context, so that atlases
+// are generated and uploaded without stalling the frames being drawn.  Until the upload
+// completes the texts of the font are not drawn.  The render context waits on a fence the
+// first time it uses the font so that the atlas is complete before it is sampled.
+//
+// Vertex arrays are not shared between contexts, so texts of the font are created on the
+// render context once Initialized reports true.  Until then the font should only be
+// touched by the loader.
+//
+//	font := v41.NewFontShared(config)
+//	go func() {
+//		runtime.LockOSThread()
+//		loader.MakeContextCurrent()
+//		font.Upload()
+//	}()
+func NewFontShared(config *gltext.FontConfig) *Font {
+	f := NewFontLazy(config)
+	f.shared = &sharedUpload{}
+	return f
+}
+
+// Upload creates the gl objects of a font created by NewFontShared on the context current
+// to the calling goroutine and fences them for the render context.
+func (f *Font) Upload() error {
+	s := f.shared
+	if s == nil {
+		return errors.New("Font was not created by NewFontShared.")
+	}
+	err := f.init()
+	var fence uintptr
+	if err == nil {
+		fence = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+	}
+	// the fence has to reach the gpu before another context can wait on it
+	gl.Flush()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done, s.err, s.fence = true, err, fence
+	return err
+}
+
+// wait reports whether the upload completed, making the current context wait on its fence
+// the first time
+func (s *sharedUpload) wait() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.done {
+		return errors.New("Font is still being uploaded.")
+	}
+	if s.fence != 0 {
+		gl.WaitSync(s.fence, 0, gl.TIMEOUT_IGNORED)
+		gl.DeleteSync(s.fence)
+		s.fence = 0
+	}
+	return s.err
+}
@@ -0,0 +1,67 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v41
+
+import (
+	"github.com/4ydx/gltext"
+)
+
+// LayoutSnapshot is the laid out geometry of a text, independent of its position,
+// scale and rotation.  Snapshots hold plain values so that they can be compared with
+// reflect.DeepEqual or stored as golden files, making regression tests of text code
+// possible without drawing anything.  Coordinates are those of the vbo: pixels with
+// the text centered around (0,0).
+type LayoutSnapshot struct {
+	String string
+
+	// lower left and upper right corners of the bounding box
+	X1, X2 gltext.Point
+
+	Glyphs []GlyphSnapshot
+	Lines  []LineInfo
+}
+
+// GlyphSnapshot is the quad of a glyph.
+type GlyphSnapshot struct {
+	Index int  // index of the rune within the string
+	Rune  rune // the rune drawn
+	Line  int  // line of the glyph, starting at zero
+	Page  int  // atlas page of the glyph
+
+	// X1, X2: the lower left and upper right corners of the quad
+	// UV1, UV2: the texture coordinates of those corners
+	X1, X2   gltext.Point
+	UV1, UV2 gltext.Point
+}
+
+// LayoutSnapshot returns the geometry the text was last laid out with
+func (t *Text) LayoutSnapshot() LayoutSnapshot {
+	s := LayoutSnapshot{String: t.String, X1: t.X1, X2: t.X2, Glyphs: make([]GlyphSnapshot, len(t.glyphs))}
+	vbo, _ := t.vertices()
+	for q, glyph := range t.glyphs {
+		v := vbo[q*16:]
+		s.Glyphs[q] = GlyphSnapshot{
+			Index: glyph.Index,
+			Rune:  glyph.Rune,
+			Line:  glyph.Line,
+			Page:  t.face(glyph.Index).Config.Glyphs[glyph.Glyph].Page,
+			X1:    gltext.Point{X: v[0] + t.origin.X, Y: v[1] + t.origin.Y},
+			X2:    gltext.Point{X: v[8] + t.origin.X, Y: v[9] + t.origin.Y},
+			UV1:   gltext.Point{X: v[2], Y: v[3]},
+			UV2:   gltext.Point{X: v[10], Y: v[11]},
+		}
+	}
+
+	// lines are placed at the position of the text, which is taken out again
+	c := t.center()
+	s.Lines = t.Lines()
+	for i := range s.Lines {
+		line := &s.Lines[i]
+		line.Baseline -= c.Y()
+		line.X1.X, line.X1.Y = line.X1.X-c.X(), line.X1.Y-c.Y()
+		line.X2.X, line.X2.Y = line.X2.X-c.X(), line.X2.Y-c.Y()
+	}
+	return s
+}
@@ -0,0 +1,223 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"errors"
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CommandList records the draws of texts that do not change, such as menus and credits,
+// so that Replay draws them all with one vertex array and no layout.  The glyphs are
+// stored where they were drawn when recorded, after scaling, rotation and positioning,
+// and the uniforms of each draw are set only when they differ from the previous one.
+// Texts that change need to be recorded again.  Per-glyph effects, custom uniforms and
+// decorations are not recorded.
+//
+//	menu, _ := v41.NewCommandList(font)
+//	menu.Record(title, start, quit)
+//	for !window.ShouldClose() {
+//		menu.Replay()
+//	}
+type CommandList struct {
+	Font *Font
+
+	// Blend is the blend mode of the draws
+	Blend BlendMode
+
+	vao, vbo, ebo uint32
+	vboData       []float32 // x, y, u, v per vertex, followed by r, g, b, a when the font reads colors from the vbo
+	eboData       []int32
+	commands      []command
+}
+
+// command is a part of the recorded ebo drawn with a single call
+type command struct {
+	texture             uint32
+	color               mgl32.Vec4
+	maskOuter           mgl32.Vec4
+	maskInner           mgl32.Vec4
+	threshold, softness float32
+	first, count        int // ebo values
+}
+
+// NewCommandList creates an empty list drawing texts of f
+func NewCommandList(f *Font) (*CommandList, error) {
+	if err := f.Init(); err != nil {
+		return nil, err
+	}
+	l := &CommandList{Font: f}
+	gl.GenVertexArrays(1, &l.vao)
+	gl.GenBuffers(1, &l.vbo)
+	gl.GenBuffers(1, &l.ebo)
+
+	stride := int32(4 * l.vertexValues())
+	gl.BindVertexArray(l.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, l.vbo)
+	gl.EnableVertexAttribArray(f.centeredPositionAttribute)
+	gl.VertexAttribPointer(f.centeredPositionAttribute, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(f.uvAttribute)
+	gl.VertexAttribPointer(f.uvAttribute, 2, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	if f.vertexColors {
+		gl.EnableVertexAttribArray(f.colorAttribute)
+		gl.VertexAttribPointer(f.colorAttribute, 4, gl.FLOAT, false, stride, gl.PtrOffset(4*4))
+	}
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, l.ebo)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	return l, nil
+}
+
+// Record replaces the recorded draws with those of texts as they are currently laid
+// out and placed.  Every text, and every face drawing it, has to be the font of the list.
+func (l *CommandList) Record(texts ...*Text) error {
+	if err := l.record(texts); err != nil {
+		return err
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, l.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(l.vboData), gl.Ptr(l.vboData), gl.STATIC_DRAW)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, l.ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(l.eboData), gl.Ptr(l.eboData), gl.STATIC_DRAW)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	return nil
+}
+
+// vertexValues returns the number of values of a vertex of the vbo
+func (l *CommandList) vertexValues() int {
+	if l.Font.vertexColors {
+		return 8
+	}
+	return 4
+}
+
+// record fills the vbo, ebo and commands from the draw ranges of texts, merging the
+// consecutive ranges sharing their uniforms.  When the font reads colors from the vbo
+// they are stored with each vertex, so that texts of different colors merge.
+func (l *CommandList) record(texts []*Text) error {
+	l.vboData, l.eboData, l.commands = l.vboData[:0], l.eboData[:0], l.commands[:0]
+	projection := l.Font.OrthographicMatrix
+	for _, t := range texts {
+		if t.Font != l.Font {
+			return errors.New("Text drawn with another font.")
+		}
+		drawCount := t.drawCount()
+		if drawCount <= 0 {
+			continue
+		}
+		ranges := t.drawRanges(drawCount)
+		for _, r := range ranges {
+			if r.font != l.Font {
+				return errors.New("Text drawn with another face.")
+			}
+		}
+
+		vbo, ebo := t.vertices()
+		base := int32(len(l.vboData) / l.vertexValues())
+		model := t.ModelMatrix()
+		for i := 0; i+3 < len(vbo); i += 4 {
+			v := model.Mul4x1(mgl32.Vec4{vbo[i] + t.origin.X, vbo[i+1] + t.origin.Y, 0, 1})
+			l.vboData = append(l.vboData, v.X(), v.Y(), vbo[i+2], vbo[i+3])
+			if l.Font.vertexColors {
+				color := t.quadColor(i / 16)
+				l.vboData = append(l.vboData, color[0], color[1], color[2], 1-t.transparency)
+			}
+		}
+
+		c := command{color: t.Font.outputColor(t.color, 1-t.transparency)}
+		if l.Font.vertexColors {
+			c.color = mgl32.Vec4{1, 1, 1, 1}
+		}
+		c.maskOuter, c.maskInner = t.maskEdges(projection)
+		if t.Font.Config.SDF {
+			c.threshold, c.softness = t.sdfUniforms()
+		}
+		for _, r := range ranges {
+			c.texture = r.font.pageTextureIDs[r.page]
+			if r.color != nil {
+				c.color = t.Font.outputColor(*r.color, 1-t.transparency)
+			}
+			c.first, c.count = len(l.eboData), r.count*6
+			for _, index := range ebo[r.first : r.first+r.count*6] {
+				l.eboData = append(l.eboData, base+index)
+			}
+			if n := len(l.commands); n > 0 && l.commands[n-1].merges(c) {
+				l.commands[n-1].count += c.count
+			} else {
+				l.commands = append(l.commands, c)
+			}
+		}
+	}
+	return nil
+}
+
+// merges reports whether c continues the ebo values of p with the same uniforms
+func (p command) merges(c command) bool {
+	return p.first+p.count == c.first && p.texture == c.texture && p.color == c.color &&
+		p.maskOuter == c.maskOuter && p.maskInner == c.maskInner &&
+		p.threshold == c.threshold && p.softness == c.softness
+}
+
+// Len returns the number of draw calls issued by Replay
+func (l *CommandList) Len() int {
+	return len(l.commands)
+}
+
+// Replay draws the recorded texts
+func (l *CommandList) Replay() {
+	if len(l.commands) == 0 {
+		return
+	}
+	f := l.Font
+	identity := mgl32.Ident4()
+	zero := mgl32.Vec2{}
+	gl.UseProgram(f.program)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.Uniform1i(f.fragmentTextureUniform, 0)
+	gl.UniformMatrix4fv(f.orthographicMatrixUniform, 1, false, &f.OrthographicMatrix[0])
+	gl.UniformMatrix4fv(f.scaleMatrixUniform, 1, false, &identity[0])
+	gl.Uniform2fv(f.finalPositionUniform, 1, &zero[0])
+	if f.originUniform >= 0 {
+		gl.Uniform2fv(f.originUniform, 1, &zero[0])
+	}
+	gl.Uniform1f(f.fadeoutUniform, 0)
+	l.Blend.begin()
+	f.timer.begin()
+	gl.BindVertexArray(l.vao)
+	for i, c := range l.commands {
+		p := command{}
+		if i > 0 {
+			p = l.commands[i-1]
+		}
+		if i == 0 || p.texture != c.texture {
+			gl.BindTexture(gl.TEXTURE_2D, c.texture)
+		}
+		if i == 0 || p.color != c.color {
+			gl.Uniform4fv(f.colorUniform, 1, &c.color[0])
+		}
+		if i == 0 || p.maskOuter != c.maskOuter || p.maskInner != c.maskInner {
+			gl.Uniform4fv(f.maskOuterUniform, 1, &c.maskOuter[0])
+			gl.Uniform4fv(f.maskInnerUniform, 1, &c.maskInner[0])
+		}
+		if f.Config.SDF && (i == 0 || p.threshold != c.threshold || p.softness != c.softness) {
+			gl.Uniform1f(f.sdfThresholdUniform, c.threshold)
+			gl.Uniform1f(f.sdfSoftnessUniform, c.softness)
+		}
+		gl.DrawElements(gl.TRIANGLES, int32(c.count), gl.UNSIGNED_INT, gl.PtrOffset(c.first*4))
+	}
+	f.stats.record(len(l.commands), len(l.eboData)/6)
+	f.timer.end()
+	gl.BindVertexArray(0)
+	l.Blend.end()
+}
+
+// Release releases the buffers of the list
+func (l *CommandList) Release() {
+	gl.DeleteBuffers(1, &l.vbo)
+	gl.DeleteBuffers(1, &l.ebo)
+	gl.DeleteVertexArrays(1, &l.vao)
+}
@@ -0,0 +1,28 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/4ydx/gltext"
+	"reflect"
+	"testing"
+)
+
+func TestComputeSegments(t *testing.T) {
+	square := []gltext.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	bulge := gltext.Curve{Start: gltext.Point{X: 10, Y: 0}, Control: gltext.Point{X: 20, Y: 5}, End: gltext.Point{X: 10, Y: 10}}
+	g := gltext.GlyphCurves{Polygons: [][]gltext.Point{square}, Curves: []gltext.Curve{bulge}}
+
+	segments := computeSegments(g)
+	if len(segments) != 4*6 {
+		t.Fatal("Expecting three lines and a curve", len(segments)/6)
+	}
+	if !reflect.DeepEqual(segments[:6], []float32{0, 0, 5, 0, 10, 0}) {
+		t.Error("Bad line", segments[:6])
+	}
+	if !reflect.DeepEqual(segments[18:], []float32{10, 0, 20, 5, 10, 10}) {
+		t.Error("Bad curve", segments[18:])
+	}
+}
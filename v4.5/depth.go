@@ -0,0 +1,78 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// DepthMode determines whether a text is tested against and written to the depth buffer.
+type DepthMode int
+
+const (
+	DMUnchanged DepthMode = iota // the depth state set by the caller is used
+	DMOnTop                      // the text is drawn over everything
+	DMTest                       // the text is hidden behind nearer geometry
+	DMTestWrite                  // the text is hidden behind nearer geometry and hides farther geometry
+)
+
+// test and write return the depth test and depth writes used by the mode
+func (m DepthMode) test() bool {
+	return m == DMTest || m == DMTestWrite
+}
+
+func (m DepthMode) write() bool {
+	return m == DMTestWrite
+}
+
+// depthState is the gl depth state replaced while drawing a text
+type depthState struct {
+	changed bool
+	test    bool
+	write   bool
+	offset  bool
+}
+
+// beginDepth applies the depth mode and polygon offset of the text, returning the state
+// to restore once it is drawn
+func (t *Text) beginDepth() (previous depthState) {
+	if t.Depth == DMUnchanged {
+		return
+	}
+	var write bool
+	gl.GetBooleanv(gl.DEPTH_WRITEMASK, &write)
+	previous = depthState{changed: true, test: gl.IsEnabled(gl.DEPTH_TEST), write: write, offset: gl.IsEnabled(gl.POLYGON_OFFSET_FILL)}
+
+	if t.Depth.test() {
+		gl.Enable(gl.DEPTH_TEST)
+		gl.DepthFunc(gl.LEQUAL)
+	} else {
+		gl.Disable(gl.DEPTH_TEST)
+	}
+	gl.DepthMask(t.Depth.write())
+	if t.Depth.test() && t.PolygonOffset != [2]float32{} {
+		gl.Enable(gl.POLYGON_OFFSET_FILL)
+		gl.PolygonOffset(t.PolygonOffset[0], t.PolygonOffset[1])
+	}
+	return
+}
+
+// end restores the depth state
+func (s depthState) end() {
+	if !s.changed {
+		return
+	}
+	setCapability(gl.DEPTH_TEST, s.test)
+	setCapability(gl.POLYGON_OFFSET_FILL, s.offset)
+	gl.DepthMask(s.write)
+}
+
+func setCapability(capability uint32, enabled bool) {
+	if enabled {
+		gl.Enable(capability)
+	} else {
+		gl.Disable(capability)
+	}
+}
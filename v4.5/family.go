@@ -0,0 +1,116 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"errors"
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// FontFamily holds the faces of a font.  Texts using a family draw their style
+// runs with the matching face.
+type FontFamily struct {
+	Regular    *Font
+	Bold       *Font
+	Italic     *Font
+	BoldItalic *Font
+}
+
+// NewFontFamily groups the faces of a font.  Only the regular face is required,
+// missing faces fall back on the closest face available.  Every face is drawn using
+// the shader of the regular face, so all of them must either be SDF fonts or not.
+func NewFontFamily(regular, bold, italic, boldItalic *Font) (*FontFamily, error) {
+	if regular == nil {
+		return nil, errors.New("A regular face is required.")
+	}
+	for _, f := range []*Font{bold, italic, boldItalic} {
+		if f != nil && f.Config.SDF != regular.Config.SDF {
+			return nil, errors.New("Faces should all be SDF fonts or not.")
+		}
+	}
+	return &FontFamily{Regular: regular, Bold: bold, Italic: italic, BoldItalic: boldItalic}, nil
+}
+
+// Face returns the face used to draw the given style
+func (ff *FontFamily) Face(style gltext.FontStyle) *Font {
+	candidates := []*Font{ff.Regular}
+	switch style {
+	case gltext.StyleBold:
+		candidates = []*Font{ff.Bold, ff.Regular}
+	case gltext.StyleItalic:
+		candidates = []*Font{ff.Italic, ff.Regular}
+	case gltext.StyleBoldItalic:
+		candidates = []*Font{ff.BoldItalic, ff.Bold, ff.Italic, ff.Regular}
+	}
+	for _, f := range candidates {
+		if f != nil {
+			return f
+		}
+	}
+	return ff.Regular
+}
+
+// SetFamily draws the text using the faces of ff, starting with Regular
+func (t *Text) SetFamily(ff *FontFamily) {
+	t.Family = ff
+	t.setFont(ff.Regular)
+	t.SetString("%s", t.String)
+}
+
+// setFont changes the font the text is drawn with.  The program of f may read the vertices
+// from other locations and with or without a color, so the vertex array is described again
+// and the vbo allocated again by the next upload, the string having to be set again.
+func (t *Text) setFont(f *Font) {
+	if f == t.Font {
+		return
+	}
+	gl.BindVertexArray(t.vao)
+	gl.DisableVertexAttribArray(t.Font.centeredPositionAttribute)
+	gl.DisableVertexAttribArray(t.Font.uvAttribute)
+	if t.vertexColored {
+		gl.DisableVertexAttribArray(t.Font.colorAttribute)
+		t.vertexColored = false
+	}
+	attributes := t.attributes
+	for _, a := range attributes {
+		gl.DisableVertexAttribArray(a.location)
+	}
+	t.Font = f
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	t.setVertexPointers()
+	if t.sharedEBO {
+		// the quad indices of the previous font may be released with it, and ebo holds
+		// older indices than the previous ones
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.ebo)
+		t.sharedEBO, t.eboCapacity = false, 0
+	}
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	t.vboCapacity = 0
+	t.fontGeneration = f.generation
+
+	// attributes the program of f does not read are dropped
+	t.releaseAttributes()
+	for _, a := range attributes {
+		t.AddAttribute(a.name, a.size, a.fill)
+	}
+}
+
+// SetStyleRuns applies styles to ranges of runes of the string.  The runs are kept
+// when the string changes, until they are replaced.
+func (t *Text) SetStyleRuns(runs ...gltext.StyleRun) {
+	t.styleRuns = runs
+	t.SetString("%s", t.String)
+}
+
+// face returns the font holding the glyph of the rune at index i
+func (t *Text) face(i int) *Font {
+	if t.Family == nil || len(t.styleRuns) == 0 {
+		return t.Font
+	}
+	return t.Family.Face(t.styleRuns.StyleAt(i))
+}
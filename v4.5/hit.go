@@ -0,0 +1,143 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/layout"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CursorPosition converts a cursor position, in pixels from the top left corner of the
+// window as reported by windowing libraries, into the coordinates used by Text.SetPosition.
+func (f *Font) CursorPosition(x, y float64) mgl32.Vec2 {
+	return mgl32.Vec2{float32(x) - f.WindowWidth/2, f.WindowHeight/2 - float32(y)}
+}
+
+// GetScaledBoundingBox returns the bounding box of the text as drawn, after scaling.
+// Rotation is not taken into account.
+func (t *Text) GetScaledBoundingBox() (X1, X2 gltext.Point) {
+	c := t.center()
+	px := t.X1.X + t.pivot.X*(t.X2.X-t.X1.X)
+	py := t.X1.Y + t.pivot.Y*(t.X2.Y-t.X1.Y)
+	X1.X = px + (t.X1.X-px)*t.Scale + c.X()
+	X1.Y = py + (t.X1.Y-py)*t.Scale + c.Y()
+	X2.X = px + (t.X2.X-px)*t.Scale + c.X()
+	X2.Y = py + (t.X2.Y-py)*t.Scale + c.Y()
+	return
+}
+
+// InkBounds returns the tightest box around the ink of the glyphs, in the coordinates of
+// GetBoundingBox.  Unlike the bounding box it leaves out the gaps between characters,
+// spaces and the room above and below the ink of the lines.
+func (t *Text) InkBounds() (X1, X2 gltext.Point) {
+	corners := []gltext.Point{}
+	for q := range t.glyphBounds {
+		// spaces have no ink
+		if b1, b2 := t.inkBounds(q); b2.X > b1.X {
+			corners = append(corners, b1, b2)
+		}
+	}
+	if len(corners) == 0 {
+		return
+	}
+	X1, X2 = bounds(corners...)
+	c := t.center()
+	return gltext.Point{X: X1.X + c.X(), Y: X1.Y + c.Y()}, gltext.Point{X: X2.X + c.X(), Y: X2.Y + c.Y()}
+}
+
+// LogicalBounds returns the box covering the advances of the lines, in the coordinates of
+// GetBoundingBox, for placing other elements next to the text.  It extends the bounding
+// box over the tabs ending lines, which have no glyph.
+func (t *Text) LogicalBounds() (X1, X2 gltext.Point) {
+	X1, X2 = t.GetBoundingBox()
+	c := t.center()
+	if end := t.lineEnd() + c.X(); end > X2.X {
+		X2.X = end
+	}
+	return
+}
+
+// AdvanceWidth returns the width of LogicalBounds
+func (t *Text) AdvanceWidth() float32 {
+	X1, X2 := t.LogicalBounds()
+	return X2.X - X1.X
+}
+
+// lineEnd returns the furthest end of the advances of the lines, trailing tabs included,
+// in the coordinates of the centered vbo data
+func (t *Text) lineEnd() float32 {
+	runes := []rune(t.String)
+	opts := t.layoutOptions()
+	end := t.X1.X
+	for q, glyph := range t.glyphs {
+		if q+1 < len(t.glyphs) && t.glyphs[q+1].Line == glyph.Line {
+			continue
+		}
+		x := glyph.X + t.CharSpacing[q]
+		if glyph.Rune == '\t' {
+			// the symbol of a tab shown by ShowWhitespace
+			x = layout.TabStop(t.Font.Config, glyph.X, opts)
+		}
+		for i := glyph.Index + 1; i < len(runes) && runes[i] != '\n'; i++ {
+			if runes[i] == '\t' {
+				x = layout.TabStop(t.Font.Config, x, opts)
+			}
+		}
+		if x+t.origin.X > end {
+			end = x + t.origin.X
+		}
+	}
+	return end
+}
+
+// Contains reports whether p, in the coordinates of SetPosition, lies within the
+// bounding box of the text as drawn, scaled and rotated.
+func (t *Text) Contains(p mgl32.Vec2) bool {
+	return inside(t.localPoint(p), t.X1, t.X2)
+}
+
+// GlyphBounds returns the lower left and upper right corners of the ink of the glyph of
+// rune i as drawn, in the coordinates of SetPosition.  Unlike the advance of the glyph,
+// the ink does not cover the gap between characters.  ok is false for runes without a
+// glyph, such as newlines.  Under rotation the corners are rotated as well.
+func (t *Text) GlyphBounds(i int) (X1, X2 gltext.Point, ok bool) {
+	for q, glyph := range t.glyphs {
+		if glyph.Index == i {
+			X1, X2 = t.inkBounds(q)
+			return t.drawnPoint(X1.X, X1.Y), t.drawnPoint(X2.X, X2.Y), true
+		}
+	}
+	return
+}
+
+// GlyphAt returns the index of the rune whose ink is drawn under p, given in the
+// coordinates of SetPosition.
+func (t *Text) GlyphAt(p mgl32.Vec2) (index int, ok bool) {
+	local := t.localPoint(p)
+	for q, glyph := range t.glyphs {
+		if X1, X2 := t.inkBounds(q); inside(local, X1, X2) {
+			return glyph.Index, true
+		}
+	}
+	return 0, false
+}
+
+// inkBounds returns the bounds of the ink of glyph q in the coordinates of the centered text
+func (t *Text) inkBounds(q int) (X1, X2 gltext.Point) {
+	b := t.glyphBounds[q]
+	X1 = gltext.Point{X: b[0].X + t.origin.X, Y: b[0].Y + t.origin.Y}
+	X2 = gltext.Point{X: b[1].X + t.origin.X, Y: b[1].Y + t.origin.Y}
+	return
+}
+
+// quadBounds returns the bounds of the quad of glyph q in vbo, the vbo data of the text,
+// in the coordinates of the centered text
+func (t *Text) quadBounds(vbo []float32, q int) (X1, X2 gltext.Point) {
+	at := q * 16
+	X1 = gltext.Point{X: vbo[at] + t.origin.X, Y: vbo[at+1] + t.origin.Y}
+	X2 = gltext.Point{X: vbo[at+8] + t.origin.X, Y: vbo[at+9] + t.origin.Y}
+	return
+}
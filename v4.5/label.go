@@ -0,0 +1,109 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Label is a text drawn over a background with an optional border.  The anchor and
+// position of a label apply to its box, padding and border included.
+type Label struct {
+	*Text
+
+	// Padding is the space between the text and the border
+	Padding float32
+
+	// Background is drawn behind the text when its alpha is above zero
+	Background mgl32.Vec4
+
+	// Border is drawn around the background when BorderWidth is above zero
+	Border      mgl32.Vec4
+	BorderWidth float32
+
+	position mgl32.Vec2
+	rects    *Rects
+}
+
+// NewLabel creates an empty label
+func NewLabel(f *Font) (*Label, error) {
+	rects, err := NewRects(f)
+	if err != nil {
+		return nil, err
+	}
+	return &Label{Text: NewText(f, 1, 1), rects: rects}, nil
+}
+
+// SetString sets the text of the label
+func (l *Label) SetString(fs string, argv ...interface{}) {
+	l.Text.SetString(fs, argv...)
+	l.SetPosition(l.position)
+}
+
+// SetAnchor determines which point of the label's box is placed at its position
+func (l *Label) SetAnchor(a Anchor) {
+	l.Text.SetAnchor(a)
+	l.SetPosition(l.position)
+}
+
+// SetPosition places the anchor point of the label's box at v
+func (l *Label) SetPosition(v mgl32.Vec2) {
+	l.position = v
+	l.Text.SetPosition(v.Add(l.inset()))
+}
+
+// GetPosition returns the position given to SetPosition
+func (l *Label) GetPosition() mgl32.Vec2 {
+	return l.position
+}
+
+// inset is the offset from the label's anchor point to the text's, which
+// moves the text inside of the padding and border
+func (l *Label) inset() (offset mgl32.Vec2) {
+	edge := l.Padding + l.BorderWidth
+	switch l.anchor {
+	case AnchorTopLeft, AnchorLeft, AnchorBottomLeft:
+		offset[0] = edge
+	case AnchorTopRight, AnchorRight, AnchorBottomRight:
+		offset[0] = -edge
+	}
+	switch l.anchor {
+	case AnchorTopLeft, AnchorTop, AnchorTopRight:
+		offset[1] = -edge
+	case AnchorBottomLeft, AnchorBottom, AnchorBottomRight:
+		offset[1] = edge
+	}
+	return
+}
+
+// GetBoundingBox returns the lower left and upper right corners of the label's box
+func (l *Label) GetBoundingBox() (X1, X2 gltext.Point) {
+	X1, X2 = l.Text.GetBoundingBox()
+	edge := l.Padding + l.BorderWidth
+	X1.X, X1.Y = X1.X-edge, X1.Y-edge
+	X2.X, X2.Y = X2.X+edge, X2.Y+edge
+	return
+}
+
+// Draw draws the background, border and text of the label
+func (l *Label) Draw() {
+	X1, X2 := l.GetBoundingBox()
+	l.rects.Clear()
+	if l.Background[3] > 0 {
+		l.rects.Add(X1.X, X1.Y, X2.X, X2.Y, l.Background)
+	}
+	if l.BorderWidth > 0 {
+		l.rects.AddOutline(X1.X, X1.Y, X2.X, X2.Y, l.BorderWidth, l.Border)
+	}
+	l.rects.Draw()
+	l.Text.Draw()
+}
+
+// Release releases the resources of the label
+func (l *Label) Release() {
+	l.rects.Release()
+	l.Text.Release()
+}
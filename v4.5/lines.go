@@ -0,0 +1,54 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/4ydx/gltext"
+)
+
+// LineInfo describes a line of laid out text.  Coordinates are those of GetBoundingBox.
+type LineInfo struct {
+	Line       int // number of the line, starting at zero
+	Start, End int // the runes of the line, from Start up to End
+
+	// Width is the advance of the line and Baseline the height glyphs are placed upon
+	Width    float32
+	Baseline float32
+
+	// lower left and upper right corners of the line
+	X1, X2 gltext.Point
+}
+
+// Lines returns the metrics of each line of the text, in order.  Lines without any
+// glyph, such as blank lines, are omitted.
+func (t *Text) Lines() []LineInfo {
+	c := t.center()
+	lineHeight := t.Font.lineHeight()
+	lines := []LineInfo{}
+	for q, glyph := range t.glyphs {
+		left := glyph.X + t.origin.X + c.X()
+		right := left + t.CharSpacing[q]
+		if len(lines) == 0 || lines[len(lines)-1].Line != glyph.Line {
+			bottom := glyph.Y + t.origin.Y + c.Y()
+			lines = append(lines, LineInfo{
+				Line:     glyph.Line,
+				Start:    glyph.Index,
+				Baseline: bottom,
+				X1:       gltext.Point{X: left, Y: bottom},
+				X2:       gltext.Point{X: right, Y: bottom + lineHeight},
+			})
+		}
+		line := &lines[len(lines)-1]
+		line.End = glyph.Index + 1
+		if left < line.X1.X {
+			line.X1.X = left
+		}
+		if right > line.X2.X {
+			line.X2.X = right
+		}
+		line.Width = line.X2.X - line.X1.X
+	}
+	return lines
+}
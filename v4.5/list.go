@@ -0,0 +1,98 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"fmt"
+	"github.com/4ydx/gltext/layout"
+	"strings"
+)
+
+// ListStyle determines the marker drawn in front of each list item.
+type ListStyle int
+
+const (
+	LSBullet ListStyle = iota
+	LSNumbered
+)
+
+// ListText renders a bulleted or numbered list.  Markers are drawn in a gutter on the
+// left-hand side and lines that wrap are indented so that they line up with the item text.
+// Wrapping only happens when MaxWidth is set on the underlying Text.
+type ListText struct {
+	*Text
+
+	Style ListStyle
+
+	// Bullet is the marker used by LSBullet.  '•' is used when it is zero and the
+	// font supports it, otherwise '-'.
+	Bullet rune
+
+	// Gutter is the width of the marker column.  It is sized to fit the widest marker
+	// followed by a space when zero, and widened to the widest marker when narrower.
+	Gutter float32
+
+	Items []string
+}
+
+// NewListText creates a list using the same scaling boundaries as NewText.
+func NewListText(f *Font, style ListStyle, scaleMin, scaleMax float32) *ListText {
+	return &ListText{Text: NewText(f, scaleMin, scaleMax), Style: style}
+}
+
+// SetItems replaces the items of the list and lays them out.  Newlines within an
+// item are replaced by spaces.
+func (l *ListText) SetItems(items ...string) {
+	l.SetString("%s", l.itemsString(items))
+}
+
+// itemsString sets the items and the indentation of the list and returns the string
+// laying them out
+func (l *ListText) itemsString(items []string) string {
+	l.Items = items
+
+	// a gutter narrower than a marker is widened to it, otherwise the tab would move the
+	// first line of the item past the hanging indent of the following ones
+	markers := make([]string, len(items))
+	gutter := l.Gutter
+	for i := range items {
+		markers[i] = l.marker(i)
+		w := l.measure(markers[i])
+		if l.Gutter == 0 {
+			w = l.measure(markers[i] + " ")
+		}
+		if w > gutter {
+			gutter = w
+		}
+	}
+
+	// the tab moves each item's text to the gutter and wrapped lines hang from the same point
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = markers[i] + "\t" + strings.Replace(item, "\n", " ", -1)
+	}
+	l.FirstLineIndent = 0
+	l.HangingIndent = gutter
+	l.TabWidth = gutter
+	return strings.Join(lines, "\n")
+}
+
+func (l *ListText) marker(i int) string {
+	if l.Style == LSNumbered {
+		return fmt.Sprintf("%d.", i+1)
+	}
+	if l.Bullet != 0 {
+		return string(l.Bullet)
+	}
+	if l.HasRune('•') {
+		return "•"
+	}
+	return "-"
+}
+
+// measure returns the advance of s in the list's font.
+func (l *ListText) measure(s string) float32 {
+	return layout.Advance(l.Font.Config, []rune(s))
+}
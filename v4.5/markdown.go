@@ -0,0 +1,40 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/4ydx/gltext"
+)
+
+// MarkdownText displays a subset of markdown, see gltext.ParseMarkdown.  Headings and
+// emphasis are drawn with the faces of a font family and long lines wrap when MaxWidth
+// is set on the underlying Text.
+type MarkdownText struct {
+	*Text
+
+	// Style is DefaultMarkdownStyle unless changed before SetMarkdown.  List items use
+	// '•' when Bullet is zero and the font supports it.
+	Style gltext.MarkdownStyle
+
+	// Source is the markdown being displayed
+	Source string
+}
+
+// NewMarkdownText creates an empty markdown text drawn with the faces of ff.
+func NewMarkdownText(ff *FontFamily, scaleMin, scaleMax float32) *MarkdownText {
+	t := NewText(ff.Regular, scaleMin, scaleMax)
+	t.Family = ff
+	return &MarkdownText{Text: t, Style: gltext.DefaultMarkdownStyle}
+}
+
+// SetMarkdown parses and displays s.
+func (m *MarkdownText) SetMarkdown(s string) {
+	m.Source = s
+	style := m.Style
+	if style.Bullet == 0 && m.HasRune('•') {
+		style.Bullet = '•'
+	}
+	m.SetMarkup(gltext.ParseMarkdown(s, style))
+}
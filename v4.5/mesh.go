@@ -0,0 +1,74 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Mesh is the laid out geometry of a text, for renderers that draw it themselves.
+type Mesh struct {
+	// Positions holds an x, y pair per vertex, in pixels from the center of the window
+	// with y pointing up, after the text's scale, rotation and position have been applied.
+	Positions []float32
+
+	// UVs holds a u, v pair per vertex.
+	UVs []float32
+
+	// Indices holds 6 vertex indices, two counter-clockwise triangles, per glyph quad.
+	Indices []uint32
+
+	// Batches split Indices by atlas texture.  Only the quads of the first RuneCount
+	// glyphs are counted.
+	Batches []MeshBatch
+
+	// TextureID is the texture of the first batch, the only one for single page fonts.
+	TextureID uint32
+}
+
+// MeshBatch is a part of Mesh.Indices drawn with a single texture.
+type MeshBatch struct {
+	TextureID uint32
+	First     int // index of the first value in Mesh.Indices
+	Count     int // number of values
+}
+
+// Mesh returns the geometry produced by SetString.  Per-glyph effects are not included.
+func (t *Text) Mesh() Mesh {
+	vbo, ebo := t.vertices()
+	m := Mesh{
+		Positions: make([]float32, 0, len(vbo)/2),
+		UVs:       make([]float32, 0, len(vbo)/2),
+		Indices:   make([]uint32, len(ebo)),
+		TextureID: t.Font.textureID,
+	}
+
+	// scale and rotate around the pivot then move to the screen position
+	model := t.ModelMatrix()
+	for i := 0; i+3 < len(vbo); i += 4 {
+		v := model.Mul4x1(mgl32.Vec4{vbo[i] + t.origin.X, vbo[i+1] + t.origin.Y, 0, 1})
+		m.Positions = append(m.Positions, v.X(), v.Y())
+		m.UVs = append(m.UVs, vbo[i+2], vbo[i+3])
+	}
+	for i, index := range ebo {
+		m.Indices[i] = uint32(index)
+	}
+
+	runeCount := t.RuneCount
+	if runeCount*6 > len(ebo) {
+		runeCount = len(ebo) / 6
+	}
+	for _, r := range t.pageRanges {
+		count := r.drawCount(runeCount)
+		if count == 0 {
+			continue
+		}
+		m.Batches = append(m.Batches, MeshBatch{TextureID: r.font.pageTextureIDs[r.page], First: r.first, Count: count * 6})
+	}
+	if len(m.Batches) > 0 {
+		m.TextureID = m.Batches[0].TextureID
+	}
+	return m
+}
@@ -0,0 +1,127 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"fmt"
+)
+
+// DebugOverlay draws the frame rate, frame time and watched values in a corner of the window.
+//
+//	overlay := NewDebugOverlay(font)
+//	overlay.Watch("entities", func() interface{} { return len(entities) })
+//	...
+//	overlay.Update(dt)
+//	overlay.Draw()
+type DebugOverlay struct {
+	Printer *Printer
+
+	// Corner of the window the overlay is drawn in.  Defaults to AnchorTopLeft.
+	// Only the corner anchors are meaningful.
+	Corner Anchor
+
+	// Margin is the distance in pixels from the edges of the window.  Defaults to 8.
+	Margin float32
+
+	// Frames is the number of frames the frame rate is averaged over.  Defaults to 60.
+	Frames int
+
+	watches   []watch
+	frameTime []float32 // seconds, most recent last
+}
+
+type watch struct {
+	name  string
+	value func() interface{}
+}
+
+// NewDebugOverlay creates an overlay drawn with the given font
+func NewDebugOverlay(f *Font) *DebugOverlay {
+	return &DebugOverlay{Printer: NewPrinter(f), Corner: AnchorTopLeft, Margin: 8, Frames: 60}
+}
+
+// Watch shows the value returned by value on a line of its own, below the frame rate.
+// Watching a name again replaces its value.
+func (o *DebugOverlay) Watch(name string, value func() interface{}) {
+	for i := range o.watches {
+		if o.watches[i].name == name {
+			o.watches[i].value = value
+			return
+		}
+	}
+	o.watches = append(o.watches, watch{name, value})
+}
+
+// Unwatch removes a watched value
+func (o *DebugOverlay) Unwatch(name string) {
+	for i := range o.watches {
+		if o.watches[i].name == name {
+			o.watches = append(o.watches[:i], o.watches[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update records the duration of the last frame in seconds
+func (o *DebugOverlay) Update(dt float32) {
+	o.frameTime = append(o.frameTime, dt)
+	if len(o.frameTime) > o.Frames {
+		o.frameTime = o.frameTime[len(o.frameTime)-o.Frames:]
+	}
+}
+
+// FPS returns the frame rate and the frame time in milliseconds averaged over the recent frames
+func (o *DebugOverlay) FPS() (fps, ms float32) {
+	total := float32(0)
+	for _, dt := range o.frameTime {
+		total += dt
+	}
+	if total <= 0 {
+		return 0, 0
+	}
+	n := float32(len(o.frameTime))
+	return n / total, 1000 * total / n
+}
+
+// Lines returns the lines the overlay draws
+func (o *DebugOverlay) Lines() []string {
+	fps, ms := o.FPS()
+	lines := []string{fmt.Sprintf("fps %.1f  %.2f ms", fps, ms)}
+	for _, w := range o.watches {
+		lines = append(lines, fmt.Sprintf("%s: %v", w.name, w.value()))
+	}
+	return lines
+}
+
+// Draw draws the overlay, one line below the other
+func (o *DebugOverlay) Draw() {
+	f := o.Printer.Font
+	x, y := -f.WindowWidth/2+o.Margin, f.WindowHeight/2-o.Margin
+	step := -f.lineHeight()
+	switch o.Corner {
+	case AnchorTopRight, AnchorBottomRight:
+		x = -x
+	}
+	lines := o.Lines()
+	switch o.Corner {
+	case AnchorBottomLeft, AnchorBottomRight:
+		// the last line sits on the bottom margin
+		y = -y + float32(len(lines)-1)*f.lineHeight()
+	}
+
+	o.Printer.Anchor = AnchorTopLeft
+	if o.Corner == AnchorTopRight || o.Corner == AnchorBottomRight {
+		o.Printer.Anchor = AnchorTopRight
+	}
+	for i, line := range lines {
+		o.Printer.Printf(x, y+float32(i)*step, "%s", line)
+	}
+	o.Printer.Draw()
+}
+
+// Release releases the texts of the overlay
+func (o *DebugOverlay) Release() {
+	o.Printer.Release()
+}
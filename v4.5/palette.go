@@ -0,0 +1,62 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"fmt"
+	"github.com/4ydx/gltext"
+)
+
+// the palette texts refer to by name and the texts doing so.  Like gl, it should only be
+// used from the thread drawing the texts.
+var (
+	palette       = gltext.Palette{}
+	palettedTexts = map[*Text]struct{}{}
+)
+
+// SetPalette replaces the palette and re-tints every text colored by SetPaletteColor,
+// switching between themes such as dark and light modes.  Texts whose color name is
+// missing from p keep their current color.
+func SetPalette(p gltext.Palette) {
+	palette = p
+	for t := range palettedTexts {
+		t.applyPaletteColor()
+	}
+}
+
+// CurrentPalette returns the palette set by SetPalette
+func CurrentPalette() gltext.Palette {
+	return palette
+}
+
+// SetPaletteColor colors the text with the color of the palette named name, and again
+// whenever SetPalette changes the palette.  Setting another color with SetColor does not
+// stop the text from following the palette; ClearPaletteColor does.
+func (t *Text) SetPaletteColor(name string) error {
+	if _, ok := palette[name]; !ok {
+		return fmt.Errorf("no palette color %q", name)
+	}
+	t.paletteColor = name
+	palettedTexts[t] = struct{}{}
+	t.applyPaletteColor()
+	return nil
+}
+
+// PaletteColor returns the name of the palette color of the text, empty when it has none
+func (t *Text) PaletteColor() string {
+	return t.paletteColor
+}
+
+// ClearPaletteColor stops the text from following the palette, keeping its current color
+func (t *Text) ClearPaletteColor() {
+	t.paletteColor = ""
+	delete(palettedTexts, t)
+}
+
+func (t *Text) applyPaletteColor() {
+	if c, ok := palette[t.paletteColor]; ok {
+		t.SetColorRGBA8(c.R, c.G, c.B, c.A)
+	}
+}
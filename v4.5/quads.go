@@ -0,0 +1,72 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// the fewest quads the shared element buffer of a font is allocated for
+const minQuadCapacity = 64
+
+// quadIndices returns the element buffer shared by the texts of the font whose quads are
+// drawn in order, holding the indices of at least quads quads.  The buffer grows by
+// doubling, keeping its name so that the vertex arrays bound to it remain valid.
+func (f *Font) quadIndices(quads int) uint32 {
+	if quads <= f.quadCapacity {
+		return f.quadEBO
+	}
+	capacity := 2 * f.quadCapacity
+	if capacity < minQuadCapacity {
+		capacity = minQuadCapacity
+	}
+	if capacity < quads {
+		capacity = quads
+	}
+	if f.quadEBO == 0 {
+		gl.GenBuffers(1, &f.quadEBO)
+	}
+	// the element array binding belongs to the bound vertex array, the copy target does not
+	indices := quadPattern(capacity)
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, f.quadEBO)
+	gl.BufferData(gl.COPY_WRITE_BUFFER, 4*len(indices), gl.Ptr(indices), gl.STATIC_DRAW)
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, 0)
+	f.quadCapacity = capacity
+	return f.quadEBO
+}
+
+// quadPattern returns the indices of the two counter-clockwise triangles of each of quads quads
+func quadPattern(quads int) []int32 {
+	indices := make([]int32, 0, quads*6)
+	for i := int32(0); i < int32(quads); i++ {
+		indices = append(indices, 4*i, 4*i+1, 4*i+2, 4*i, 4*i+2, 4*i+3)
+	}
+	return indices
+}
+
+// isQuadPattern reports whether the first quads quads of ebo are drawn in order, as the
+// shared element buffer does.  The values following them pad the ebo for runes that are
+// not drawn, such as newlines, and only draw empty quads of either buffer.
+func isQuadPattern(ebo []int32, quads int) bool {
+	if len(ebo)%6 != 0 || quads*6 > len(ebo) {
+		return false
+	}
+	for at := 0; at < quads*6; at += 6 {
+		i := int32(at / 6 * 4)
+		if ebo[at] != i || ebo[at+1] != i+1 || ebo[at+2] != i+2 || ebo[at+3] != i || ebo[at+4] != i+2 || ebo[at+5] != i+3 {
+			return false
+		}
+	}
+	return true
+}
+
+// quadCount returns the number of quads of the text holding a glyph
+func (t *Text) quadCount() int {
+	if t.glyphs == nil {
+		// counters lay out their quads without glyphs
+		return t.eboIndexCount / 6
+	}
+	return len(t.glyphs)
+}
@@ -0,0 +1,108 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"sort"
+)
+
+// DrawQueue collects the texts drawn during a frame and draws them on Flush sorted by
+// program, blend mode and texture, so that each program and texture is bound once per
+// frame however many fonts are in play.  Texts sharing all three are drawn in the order
+// they were queued, others in sorted order, so overlapping texts should share a font.
+// The decorations of the queued texts, such as highlights, are drawn first.
+//
+//	queue := v41.NewDrawQueue()
+//	for _, text := range texts {
+//		queue.Queue(text)
+//	}
+//	queue.Flush()
+type DrawQueue struct {
+	texts  []*Text
+	states map[*Font]*RenderState
+}
+
+// NewDrawQueue creates an empty queue
+func NewDrawQueue() *DrawQueue {
+	return &DrawQueue{states: map[*Font]*RenderState{}}
+}
+
+// Queue adds t to the texts drawn by the next Flush
+func (q *DrawQueue) Queue(t *Text) {
+	q.texts = append(q.texts, t)
+}
+
+// Len returns the number of texts queued
+func (q *DrawQueue) Len() int {
+	return len(q.texts)
+}
+
+// Flush draws the queued texts and empties the queue.  The texts of a font sharing a blend
+// mode are drawn between the Begin and End of a RenderState.
+func (q *DrawQueue) Flush() {
+	for _, t := range q.texts {
+		// lazy fonts have no program to sort by before they are initialized
+		t.Font.Init()
+		t.drawDecorations()
+	}
+	texts := q.sorted()
+	for first := 0; first < len(texts); {
+		f, blend := texts[first].Font, texts[first].Blend
+		last := first + 1
+		for last < len(texts) && texts[last].Font == f && texts[last].Blend == blend {
+			last++
+		}
+		if f.Init() == nil {
+			state := q.state(f)
+			state.Blend = blend
+			state.Begin()
+			for _, t := range texts[first:last] {
+				t.draw(f.OrthographicMatrix, t.finalPosition)
+			}
+			state.End()
+		}
+		first = last
+	}
+	for i := range q.texts {
+		q.texts[i] = nil
+	}
+	q.texts = q.texts[:0]
+}
+
+// state returns the render state of the texts of f
+func (q *DrawQueue) state(f *Font) *RenderState {
+	s, ok := q.states[f]
+	if !ok {
+		s = NewRenderState(f)
+		q.states[f] = s
+	}
+	return s
+}
+
+// sorted returns the queued texts ordered by program, blend mode and texture, and by
+// font so that the texts of a font follow each other
+func (q *DrawQueue) sorted() []*Text {
+	texts := append([]*Text(nil), q.texts...)
+	fonts := map[*Font]int{}
+	for _, t := range texts {
+		if _, ok := fonts[t.Font]; !ok {
+			fonts[t.Font] = len(fonts)
+		}
+	}
+	sort.SliceStable(texts, func(i, j int) bool {
+		a, b := texts[i], texts[j]
+		if a.Font.program != b.Font.program {
+			return a.Font.program < b.Font.program
+		}
+		if a.Blend != b.Blend {
+			return a.Blend < b.Blend
+		}
+		if a.Font.textureID != b.Font.textureID {
+			return a.Font.textureID < b.Font.textureID
+		}
+		return fonts[a.Font] < fonts[b.Font]
+	})
+	return texts
+}
@@ -0,0 +1,144 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var rectVertexShaderSource string = `
+#version 330
+
+uniform mat4 orthographic_matrix;
+
+in vec2 position;
+in vec4 color;
+
+out vec4 fragment_rect_color;
+
+void main() {
+  fragment_rect_color = color;
+  gl_Position = orthographic_matrix * vec4(position, 0, 1);
+}
+` + "\x00"
+
+var rectFragmentShaderSource string = `
+#version 330
+
+in vec4 fragment_rect_color;
+out vec4 fragment_color;
+
+void main() {
+  fragment_color = fragment_rect_color;
+}
+` + "\x00"
+
+// Rects draws solid colored rectangles, such as backgrounds and underlines, in the
+// same coordinates as Text.SetPosition: pixels from the center of the window.
+type Rects struct {
+	font *Font
+
+	vao, vbo, ebo uint32
+	vboData       []float32 // x, y, r, g, b, a per vertex
+	eboData       []int32
+	dirty         bool
+}
+
+// NewRects creates an empty set of rectangles projected like texts of the font
+func NewRects(f *Font) (*Rects, error) {
+	if f.rectProgram == 0 {
+		program, err := NewProgram(rectVertexShaderSource, rectFragmentShaderSource)
+		if err != nil {
+			return nil, err
+		}
+		f.rectProgram = program
+	}
+	r := &Rects{font: f}
+	gl.GenVertexArrays(1, &r.vao)
+	gl.GenBuffers(1, &r.vbo)
+	gl.GenBuffers(1, &r.ebo)
+
+	gl.BindVertexArray(r.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.vbo)
+	position := uint32(gl.GetAttribLocation(f.rectProgram, gl.Str("position\x00")))
+	color := uint32(gl.GetAttribLocation(f.rectProgram, gl.Str("color\x00")))
+	gl.EnableVertexAttribArray(position)
+	gl.VertexAttribPointer(position, 2, gl.FLOAT, false, 6*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(color)
+	gl.VertexAttribPointer(color, 4, gl.FLOAT, false, 6*4, gl.PtrOffset(2*4))
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, r.ebo)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	return r, nil
+}
+
+// Clear removes every rectangle
+func (r *Rects) Clear() {
+	if len(r.vboData) > 0 {
+		r.vboData, r.eboData = r.vboData[:0], r.eboData[:0]
+		r.dirty = true
+	}
+}
+
+// Len returns the number of rectangles
+func (r *Rects) Len() int {
+	return len(r.eboData) / 6
+}
+
+// Add adds the rectangle with lower left corner (x1, y1) and upper right corner (x2, y2)
+func (r *Rects) Add(x1, y1, x2, y2 float32, color mgl32.Vec4) {
+	first := int32(len(r.vboData) / 6)
+	for _, corner := range [4][2]float32{{x1, y1}, {x2, y1}, {x2, y2}, {x1, y2}} {
+		r.vboData = append(r.vboData, corner[0], corner[1], color[0], color[1], color[2], color[3])
+	}
+	r.eboData = append(r.eboData, first, first+1, first+2, first, first+2, first+3)
+	r.dirty = true
+}
+
+// AddOutline adds a frame of the given width drawn inside of the rectangle
+func (r *Rects) AddOutline(x1, y1, x2, y2, width float32, color mgl32.Vec4) {
+	r.Add(x1, y1, x2, y1+width, color)
+	r.Add(x1, y2-width, x2, y2, color)
+	r.Add(x1, y1+width, x1+width, y2-width, color)
+	r.Add(x2-width, y1+width, x2, y2-width, color)
+}
+
+// Draw draws the rectangles, uploading them first when they changed.  Between
+// RenderState.Begin and End the next text drawn sets up the program and blending again.
+func (r *Rects) Draw() {
+	if len(r.eboData) == 0 {
+		return
+	}
+	// the element buffer is bound to the vertex array of the rectangles, leaving that of
+	// the text drawn last alone
+	gl.BindVertexArray(r.vao)
+	if r.dirty {
+		gl.BindBuffer(gl.ARRAY_BUFFER, r.vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, 4*len(r.vboData), gl.Ptr(r.vboData), gl.DYNAMIC_DRAW)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, r.ebo)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(r.eboData), gl.Ptr(r.eboData), gl.DYNAMIC_DRAW)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		r.dirty = false
+	}
+	gl.UseProgram(r.font.rectProgram)
+	location := gl.GetUniformLocation(r.font.rectProgram, gl.Str("orthographic_matrix\x00"))
+	gl.UniformMatrix4fv(location, 1, false, &r.font.OrthographicMatrix[0])
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.DrawElements(gl.TRIANGLES, int32(len(r.eboData)), gl.UNSIGNED_INT, nil)
+	gl.BindVertexArray(0)
+	gl.Disable(gl.BLEND)
+	r.font.state.interrupt()
+}
+
+// Release releases the buffers of the rectangles
+func (r *Rects) Release() {
+	gl.DeleteBuffers(1, &r.vbo)
+	gl.DeleteBuffers(1, &r.ebo)
+	gl.DeleteVertexArrays(1, &r.vao)
+}
@@ -0,0 +1,111 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"time"
+)
+
+// Stats counts the work done drawing the texts of a font since ResetStats, which is
+// usually called once per frame.
+type Stats struct {
+	Texts     int // texts drawn
+	DrawCalls int // glDrawElements calls
+	Glyphs    int // quads drawn
+
+	// GPUTime is the time the gpu spent drawing the texts, measured when enabled by
+	// SetGPUTiming.  Timings arrive a few frames late as the gpu finishes drawing.
+	GPUTime time.Duration
+}
+
+// Stats returns the work done since ResetStats, collecting the gpu timings available
+func (f *Font) Stats() Stats {
+	f.timer.collect(&f.stats)
+	return f.stats
+}
+
+// ResetStats starts counting again.  Timings of draws still in flight are counted
+// towards the next stats.
+func (f *Font) ResetStats() {
+	f.timer.collect(&f.stats)
+	f.stats = Stats{}
+}
+
+// SetGPUTiming measures the gpu time of every text drawn with timer queries.  Queries
+// cost a little time themselves and no other GL_TIME_ELAPSED query may be active while
+// texts are drawn.
+func (f *Font) SetGPUTiming(enabled bool) {
+	if !enabled && f.timer != nil {
+		f.timer.release()
+		f.timer = nil
+	}
+	if enabled && f.timer == nil {
+		f.timer = &gpuTimer{}
+	}
+}
+
+// record counts a text drawn with the given draw calls and quads
+func (s *Stats) record(drawCalls, glyphs int) {
+	s.Texts++
+	s.DrawCalls += drawCalls
+	s.Glyphs += glyphs
+}
+
+// gpuTimer measures draws with timer queries, reusing the queries whose results were read
+type gpuTimer struct {
+	free    []uint32
+	pending []uint32
+}
+
+// begin starts measuring a draw, it is nil safe so that draws are only timed when enabled
+func (g *gpuTimer) begin() {
+	if g == nil {
+		return
+	}
+	var query uint32
+	if n := len(g.free); n > 0 {
+		query, g.free = g.free[n-1], g.free[:n-1]
+	} else {
+		gl.GenQueries(1, &query)
+	}
+	gl.BeginQuery(gl.TIME_ELAPSED, query)
+	g.pending = append(g.pending, query)
+}
+
+func (g *gpuTimer) end() {
+	if g != nil {
+		gl.EndQuery(gl.TIME_ELAPSED)
+	}
+}
+
+// collect adds the results available to s without waiting for the others
+func (g *gpuTimer) collect(s *Stats) {
+	if g == nil {
+		return
+	}
+	pending := g.pending[:0]
+	for _, query := range g.pending {
+		var available int32
+		gl.GetQueryObjectiv(query, gl.QUERY_RESULT_AVAILABLE, &available)
+		if available == gl.FALSE {
+			pending = append(pending, query)
+			continue
+		}
+		var elapsed uint64
+		gl.GetQueryObjectui64v(query, gl.QUERY_RESULT, &elapsed)
+		s.GPUTime += time.Duration(elapsed)
+		g.free = append(g.free, query)
+	}
+	g.pending = pending
+}
+
+func (g *gpuTimer) release() {
+	queries := append(g.free, g.pending...)
+	if len(queries) > 0 {
+		gl.DeleteQueries(int32(len(queries)), &queries[0])
+	}
+	g.free, g.pending = nil, nil
+}
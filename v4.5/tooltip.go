@@ -0,0 +1,117 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v45
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Tooltip is a label shown once the cursor has rested over a target text for Delay
+// seconds.  It is placed below and to the right of the cursor, or below the target
+// when AttachToTarget is set, and flips to the other side of the cursor or target when
+// it would leave the window.
+type Tooltip struct {
+	*Label
+
+	Target *Text
+
+	// Delay is the time in seconds before the tooltip appears.  Defaults to 0.5.
+	Delay float32
+
+	// Offset is the distance between the cursor and the tooltip.  Defaults to 12 pixels.
+	Offset float32
+
+	// AttachToTarget places the tooltip next to the target's bounding box instead of the cursor
+	AttachToTarget bool
+
+	hovered float32 // seconds the cursor has spent over the target
+	visible bool
+}
+
+// NewTooltip creates a tooltip showing s over a dark background when target is hovered
+func NewTooltip(f *Font, s string, target *Text) (*Tooltip, error) {
+	l, err := NewLabel(f)
+	if err != nil {
+		return nil, err
+	}
+	l.Padding = 4
+	l.Background = mgl32.Vec4{0.1, 0.1, 0.1, 0.9}
+	l.Border = mgl32.Vec4{0.6, 0.6, 0.6, 1}
+	l.BorderWidth = 1
+	l.SetColor(mgl32.Vec3{1, 1, 1})
+	l.SetString("%s", s)
+	return &Tooltip{Label: l, Target: target, Delay: 0.5, Offset: 12}, nil
+}
+
+// Visible reports whether the tooltip is shown
+func (tt *Tooltip) Visible() bool {
+	return tt.visible
+}
+
+// Update shows or hides the tooltip given the cursor position, in the coordinates of
+// SetPosition, and the time elapsed in seconds.
+func (tt *Tooltip) Update(cursor mgl32.Vec2, dt float32) {
+	if tt.Target == nil || !tt.Target.Contains(cursor) {
+		tt.hovered, tt.visible = 0, false
+		return
+	}
+	tt.hovered += dt
+	if tt.hovered < tt.Delay {
+		return
+	}
+	tt.visible = true
+
+	X1, X2 := tt.GetBoundingBox()
+	size := mgl32.Vec2{X2.X - X1.X, X2.Y - X1.Y}
+	f := tt.Font
+	window := mgl32.Vec2{f.WindowWidth, f.WindowHeight}
+	var anchor Anchor
+	var position mgl32.Vec2
+	if tt.AttachToTarget {
+		T1, T2 := tt.Target.GetScaledBoundingBox()
+		anchor, position = placeTooltip(size, window,
+			mgl32.Vec2{T1.X, T1.Y - tt.Offset},
+			mgl32.Vec2{T1.X, T2.Y + tt.Offset},
+			T2.X)
+	} else {
+		anchor, position = placeTooltip(size, window,
+			mgl32.Vec2{cursor.X() + tt.Offset, cursor.Y() - tt.Offset},
+			mgl32.Vec2{cursor.X() + tt.Offset, cursor.Y() + tt.Offset},
+			cursor.X()-tt.Offset)
+	}
+	tt.Text.anchor = anchor
+	tt.SetPosition(position)
+}
+
+// placeTooltip returns the anchor and position of a box of the given size whose top left
+// corner is preferably placed at below.  The box moves up to the point above when it would
+// leave the bottom of the window, and ends at x = right when it would leave the right-hand side.
+func placeTooltip(size, window, below, above mgl32.Vec2, right float32) (Anchor, mgl32.Vec2) {
+	flipY := below.Y()-size.Y() < -window.Y()/2
+	flipX := below.X()+size.X() > window.X()/2
+	position := below
+	if flipY {
+		position = above
+	}
+	if flipX {
+		position[0] = right
+	}
+	switch {
+	case flipX && flipY:
+		return AnchorBottomRight, position
+	case flipX:
+		return AnchorTopRight, position
+	case flipY:
+		return AnchorBottomLeft, position
+	}
+	return AnchorTopLeft, position
+}
+
+// Draw draws the tooltip when it is visible
+func (tt *Tooltip) Draw() {
+	if tt.visible {
+		tt.Label.Draw()
+	}
+}
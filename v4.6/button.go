@@ -0,0 +1,108 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// ButtonState is the interaction state of a TextButton
+type ButtonState int
+
+const (
+	ButtonNormal ButtonState = iota
+	ButtonHover
+	ButtonPressed
+	ButtonDisabled
+)
+
+// TextButton is a text that grows to ScaleMax while hovered and calls OnClick when
+// it is pressed and released under the cursor.  Its color follows its state.
+type TextButton struct {
+	*Text
+
+	// OnClick is called when the button is clicked
+	OnClick func()
+
+	// Colors of each state, indexed by ButtonState
+	Colors [4]mgl32.Vec3
+
+	// HoverDuration is the time in seconds taken to scale between ScaleMin and ScaleMax.
+	HoverDuration float32
+
+	state      ButtonState
+	wasPressed bool // the mouse button was down during the previous update
+}
+
+// NewTextButton creates a button labelled s that grows by a tenth when hovered
+func NewTextButton(f *Font, s string, onClick func()) *TextButton {
+	b := &TextButton{Text: NewText(f, 1, 1.1), OnClick: onClick, HoverDuration: 0.1}
+	b.Colors = [4]mgl32.Vec3{
+		ButtonNormal:   {0.9, 0.9, 0.9},
+		ButtonHover:    {1, 1, 1},
+		ButtonPressed:  {0.7, 0.7, 0.7},
+		ButtonDisabled: {0.5, 0.5, 0.5},
+	}
+	b.SetString("%s", s)
+	b.SetColor(b.Colors[ButtonNormal])
+	return b
+}
+
+// State returns the current state of the button
+func (b *TextButton) State() ButtonState {
+	return b.state
+}
+
+// SetDisabled stops the button from reacting to the cursor
+func (b *TextButton) SetDisabled(disabled bool) {
+	if disabled {
+		b.setState(ButtonDisabled)
+	} else if b.state == ButtonDisabled {
+		b.setState(ButtonNormal)
+	}
+}
+
+// Update advances the button given the cursor position, in the coordinates of
+// SetPosition (see Font.CursorPosition), whether the mouse button is down and the
+// time elapsed in seconds.
+func (b *TextButton) Update(cursor mgl32.Vec2, pressed bool, dt float32) {
+	if b.state != ButtonDisabled {
+		hover := b.Contains(cursor)
+		switch {
+		case hover && pressed && (b.state == ButtonPressed || !b.wasPressed):
+			// presses that began elsewhere are ignored
+			b.setState(ButtonPressed)
+		case hover && !pressed && b.state == ButtonPressed:
+			b.setState(ButtonHover)
+			if b.OnClick != nil {
+				b.OnClick()
+			}
+		case hover && !pressed:
+			b.setState(ButtonHover)
+		case !hover:
+			b.setState(ButtonNormal)
+		}
+	}
+	b.wasPressed = pressed
+	b.Text.Update(dt)
+}
+
+func (b *TextButton) setState(state ButtonState) {
+	if state == b.state {
+		return
+	}
+	grow := state == ButtonHover || state == ButtonPressed
+	wasGrown := b.state == ButtonHover || b.state == ButtonPressed
+	b.state = state
+	b.SetColor(b.Colors[state])
+	if grow != wasGrown {
+		target := b.ScaleMin
+		if grow {
+			target = b.ScaleMax
+		}
+		b.AnimateScale(target, b.HoverDuration, gltext.EaseOutQuad)
+	}
+}
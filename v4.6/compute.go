@@ -0,0 +1,192 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/mikzorz/gltext"
+)
+
+// every invocation covers one pixel of the glyph rectangle, sampling it four times and
+// counting the winding of the outline around each sample along a ray towards +x.  lines are
+// stored as curves whose control point is their midpoint.
+var rasterComputeShaderSource string = `
+#version 430
+
+layout(local_size_x = 8, local_size_y = 8) in;
+layout(rgba8, binding = 0) uniform writeonly image2D atlas;
+layout(std430, binding = 1) readonly buffer segments {
+  vec2 points[];
+};
+
+uniform ivec2 offset;
+uniform ivec2 size;
+uniform vec2 origin;
+uniform int segment_count;
+
+int winding(vec2 p) {
+  int w = 0;
+  for (int i = 0; i < segment_count; i++) {
+    vec2 p0 = points[3 * i] - p;
+    vec2 p1 = points[3 * i + 1] - p;
+    vec2 p2 = points[3 * i + 2] - p;
+    float a = p0.y - 2.0 * p1.y + p2.y;
+    float b = 2.0 * (p1.y - p0.y);
+    float c = p0.y;
+    float t[2] = float[2](-1.0, -1.0);
+    if (abs(a) < 1e-6) {
+      if (abs(b) > 1e-6) {
+        t[0] = -c / b;
+      }
+    } else {
+      float d = b * b - 4.0 * a * c;
+      if (d >= 0.0) {
+        float r = sqrt(d);
+        t[0] = (-b - r) / (2.0 * a);
+        t[1] = (-b + r) / (2.0 * a);
+      }
+    }
+    for (int j = 0; j < 2; j++) {
+      float s = t[j];
+      if (s < 0.0 || s >= 1.0) {
+        continue;
+      }
+      float u = 1.0 - s;
+      float x = u * u * p0.x + 2.0 * u * s * p1.x + s * s * p2.x;
+      float dy = 2.0 * a * s + b;
+      if (x > 0.0 && dy != 0.0) {
+        w += dy > 0.0 ? 1 : -1;
+      }
+    }
+  }
+  return w;
+}
+
+void main() {
+  ivec2 pixel = ivec2(gl_GlobalInvocationID.xy);
+  if (pixel.x >= size.x || pixel.y >= size.y) {
+    return;
+  }
+  // image rows grow downwards while glyphs are laid out with y up
+  vec2 center = origin + vec2(float(pixel.x) + 0.5, -float(pixel.y) - 0.5);
+  vec2 samples[4] = vec2[4](vec2(-0.375, -0.125), vec2(0.125, -0.375), vec2(0.375, 0.125), vec2(-0.125, 0.375));
+  float coverage = 0.0;
+  for (int i = 0; i < 4; i++) {
+    if (winding(center + samples[i]) != 0) {
+      coverage += 0.25;
+    }
+  }
+  imageStore(atlas, offset + pixel, vec4(1.0, 1.0, 1.0, coverage));
+}
+` + "\x00"
+
+// ComputeRasterizer draws glyph outlines straight into atlas textures with a compute
+// shader, instead of rasterizing them on the cpu and uploading the pixels.  It needs
+// OpenGL 4.3 and textures with an RGBA8 format, such as the pages of a Font.
+type ComputeRasterizer struct {
+	program uint32
+	ssbo    uint32
+
+	offsetUniform  int32
+	sizeUniform    int32
+	originUniform  int32
+	segmentUniform int32
+}
+
+// NewComputeRasterizer compiles the compute shader
+func NewComputeRasterizer() (*ComputeRasterizer, error) {
+	shader, err := compileShader(rasterComputeShaderSource, gl.COMPUTE_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	program := gl.CreateProgram()
+	gl.AttachShader(program, shader)
+	gl.LinkProgram(program)
+	gl.DetachShader(program, shader)
+	gl.DeleteShader(shader)
+
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program)
+		return nil, errors.New(fmt.Sprintf("failed to link program: %v", log))
+	}
+
+	r := &ComputeRasterizer{program: program}
+	r.offsetUniform = gl.GetUniformLocation(program, gl.Str("offset\x00"))
+	r.sizeUniform = gl.GetUniformLocation(program, gl.Str("size\x00"))
+	r.originUniform = gl.GetUniformLocation(program, gl.Str("origin\x00"))
+	r.segmentUniform = gl.GetUniformLocation(program, gl.Str("segment_count\x00"))
+	gl.GenBuffers(1, &r.ssbo)
+	return r, nil
+}
+
+// Rasterize fills the width by height pixels of texture whose upper left corner is (x, y)
+// with the coverage of the glyph.  origin is the point of the outline, in pixels with y up,
+// placed at the upper left corner of the rectangle.  Texts sample the updated pixels once
+// Rasterize returns.
+func (r *ComputeRasterizer) Rasterize(texture uint32, x, y, width, height int32, g gltext.GlyphCurves, origin gltext.Point) {
+	segments := computeSegments(g)
+	gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, r.ssbo)
+	if len(segments) > 0 {
+		gl.BufferData(gl.SHADER_STORAGE_BUFFER, 4*len(segments), gl.Ptr(segments), gl.STREAM_DRAW)
+	} else {
+		gl.BufferData(gl.SHADER_STORAGE_BUFFER, 4*6, nil, gl.STREAM_DRAW)
+	}
+	gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, 0)
+
+	gl.UseProgram(r.program)
+	gl.Uniform2i(r.offsetUniform, x, y)
+	gl.Uniform2i(r.sizeUniform, width, height)
+	gl.Uniform2f(r.originUniform, origin.X, origin.Y)
+	gl.Uniform1i(r.segmentUniform, int32(len(segments)/6))
+	gl.BindImageTexture(0, texture, 0, false, 0, gl.WRITE_ONLY, gl.RGBA8)
+	gl.BindBufferBase(gl.SHADER_STORAGE_BUFFER, 1, r.ssbo)
+	gl.DispatchCompute(uint32(width+7)/8, uint32(height+7)/8, 1)
+
+	// samplers must see the stored pixels
+	gl.MemoryBarrier(gl.TEXTURE_FETCH_BARRIER_BIT | gl.SHADER_IMAGE_ACCESS_BARRIER_BIT)
+	gl.BindBufferBase(gl.SHADER_STORAGE_BUFFER, 1, 0)
+	gl.UseProgram(0)
+}
+
+// computeSegments returns the start, control and end points of every segment of the glyph.
+// Polygon edges spanned by a curve are replaced by that curve, the others are lines.
+func computeSegments(g gltext.GlyphCurves) []float32 {
+	type edge struct{ from, to gltext.Point }
+	curved := map[edge]int{}
+	for _, c := range g.Curves {
+		curved[edge{c.Start, c.End}]++
+	}
+	segments := []float32{}
+	for _, polygon := range g.Polygons {
+		for i, from := range polygon {
+			to := polygon[(i+1)%len(polygon)]
+			if curved[edge{from, to}] > 0 {
+				curved[edge{from, to}]--
+				continue
+			}
+			segments = append(segments, from.X, from.Y, (from.X+to.X)/2, (from.Y+to.Y)/2, to.X, to.Y)
+		}
+	}
+	for _, c := range g.Curves {
+		segments = append(segments, c.Start.X, c.Start.Y, c.Control.X, c.Control.Y, c.End.X, c.End.Y)
+	}
+	return segments
+}
+
+// Release releases the program and buffer of the rasterizer
+func (r *ComputeRasterizer) Release() {
+	gl.DeleteBuffers(1, &r.ssbo)
+	gl.DeleteProgram(r.program)
+}
@@ -0,0 +1,193 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/mikzorz/gltext"
+)
+
+// counterRunes are the runes drawn by a counter, the digits followed by the minus sign
+const counterRunes = "0123456789-"
+
+// the number of floats of a quad of a text
+const counterQuadSize = 4 * 4
+
+// Counter draws an integer that changes often, such as a score or frames per second.  The
+// quads of the digits are generated once and setting a value only rewrites the cells that
+// changed, without laying out a string or reallocating the buffers as SetString does.
+// Values are right aligned in cells as wide as the widest digit so that they do not jitter.
+// The digits and minus sign must be on the first page of the atlas.
+//
+// SetString and ReleaseCPUBuffers must not be called on the text of a counter.
+type Counter struct {
+	*Text
+
+	// Digits is the number of cells, the minus sign of negative values taking one of them
+	Digits int
+
+	value int
+	cell  float32                                     // width of a cell
+	quads [len(counterRunes)][counterQuadSize]float32 // quads of the runes in a cell whose lower left corner is at (0,0)
+	shown []int                                       // index within counterRunes of the rune of each cell, -1 when blank
+}
+
+// NewCounter creates a counter of digits cells showing 0
+func NewCounter(f *Font, digits int) (*Counter, error) {
+	if digits <= 0 {
+		return nil, fmt.Errorf("invalid number of digits %d", digits)
+	}
+	c := &Counter{Text: NewText(f, 1, 1), Digits: digits}
+	if err := c.bake(); err != nil {
+		c.Text.Release()
+		return nil, err
+	}
+	c.upload(nil, nil)
+	return c, nil
+}
+
+// Value returns the value shown, which SetValue may have clamped
+func (c *Counter) Value() int {
+	return c.value
+}
+
+// SetValue shows v, clamped to the values that fit in the cells of the counter, sending
+// only the cells that changed to the gpu.
+func (c *Counter) SetValue(v int) {
+	c.Font.checkThread("SetValue")
+	first, last := c.setValue(v)
+	if first > last {
+		return
+	}
+	data := c.vboData[:(last+1)*counterQuadSize]
+	if c.colorsChanged() {
+		// every cell is sent again with the new color
+		data = c.vboData
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+	c.vertexBufferSubData(data, first*counterQuadSize)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// Draw draws the counter, generating its quads again when the font was reloaded
+func (c *Counter) Draw() {
+	if c.fontGeneration != c.Font.generation {
+		if err := c.bake(); err != nil {
+			gltext.TextDebug(err.Error())
+			return
+		}
+		c.upload(nil, nil)
+	}
+	c.Text.Draw()
+}
+
+// bake generates the quads of the runes and lays out the blank cells of the counter
+func (c *Counter) bake() error {
+	f := c.Font
+	glyphs := [len(counterRunes)]gltext.Glyph{}
+	c.cell = 0
+	for i, r := range counterRunes {
+		index := int(f.Config.RuneRanges.GetGlyphIndex(r))
+		if index < 0 || index >= len(f.Config.Glyphs) || f.Config.Glyphs[index].Page != 0 {
+			return fmt.Errorf("no glyph for %q on the first page of the atlas", r)
+		}
+		glyphs[i] = f.Config.Glyphs[index]
+		if advance := float32(glyphs[i].Advance); advance > c.cell {
+			c.cell = advance
+		}
+	}
+	for i := range glyphs {
+		tP1, tP2 := glyphs[i].GetTexturePositions(f)
+		vw, vh := float32(glyphs[i].Advance), float32(glyphs[i].Height)
+		x, y := (c.cell-vw)/2, float32(glyphs[i].OffsetY)
+		c.quads[i] = [counterQuadSize]float32{
+			x, y, tP1.X, tP2.Y,
+			x + vw, y, tP2.X, tP2.Y,
+			x + vw, y + vh, tP2.X, tP1.Y,
+			x, y + vh, tP1.X, tP1.Y,
+		}
+	}
+
+	// cells are stored from the right so that drawing the first RuneCount quads draws
+	// the right aligned value
+	t := c.Text
+	t.vboIndexCount, t.eboIndexCount = c.Digits*counterQuadSize, c.Digits*6
+	t.vboData = make([]float32, t.vboIndexCount)
+	t.eboData = quadPattern(c.Digits)
+	t.pageRanges, t.glyphs, t.glyphBounds, t.CharSpacing = nil, nil, nil, nil
+	t.X1 = gltext.Point{}
+	t.X2 = gltext.Point{X: c.cell * float32(c.Digits), Y: f.lineHeight()}
+	t.centerTheData(t.getLowerLeft())
+	t.fontGeneration = f.generation
+
+	c.shown = make([]int, c.Digits)
+	for i := range c.shown {
+		c.shown[i] = -1
+	}
+	c.setValue(c.value)
+	t.SetPosition(t.Position)
+	return nil
+}
+
+// setValue writes the quads of the cells showing v that changed and returns their range
+func (c *Counter) setValue(v int) (first, last int) {
+	high, low := 1, 0
+	for i := 0; i < c.Digits; i++ {
+		low = -(high - 1)
+		high *= 10
+	}
+	high--
+	if v > high {
+		v = high
+	}
+	if v < low {
+		v = low
+	}
+	c.value = v
+	c.String = strconv.Itoa(v)
+
+	negative := v < 0
+	if negative {
+		v = -v
+	}
+	first, last = len(c.shown), -1
+	c.RuneCount = 0
+	for i := range c.shown {
+		r := -1
+		switch {
+		case i == 0 || v > 0:
+			r, v = v%10, v/10
+		case negative:
+			r, negative = len(counterRunes)-1, false
+		}
+		if r >= 0 {
+			c.RuneCount = i + 1
+		}
+		if r == c.shown[i] {
+			continue
+		}
+		c.shown[i] = r
+		quad := c.vboData[i*counterQuadSize : (i+1)*counterQuadSize]
+		if r < 0 {
+			for j := range quad {
+				quad[j] = 0
+			}
+		} else {
+			copy(quad, c.quads[r][:])
+			x := c.cell * float32(c.Digits-1-i)
+			for j := 0; j < counterQuadSize; j += 4 {
+				quad[j] += x
+			}
+		}
+		if i < first {
+			first = i
+		}
+		last = i
+	}
+	return first, last
+}
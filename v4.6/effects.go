@@ -0,0 +1,99 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/mikzorz/gltext"
+)
+
+// SetEffects replaces the effects moving the individual glyphs of the text.  The offsets
+// of all effects are added together.  Effects are advanced by Update, calling SetEffects
+// without arguments puts every glyph back in its place.
+func (t *Text) SetEffects(effects ...gltext.GlyphEffect) {
+	t.effects = effects
+	t.effectTime = 0
+	if len(effects) == 0 {
+		t.SetGlyphOffsets(nil)
+	}
+}
+
+// updateEffects is called by Update
+func (t *Text) updateEffects(dt float32) {
+	if len(t.effects) == 0 {
+		return
+	}
+	t.effectTime += dt
+
+	count := len(t.CharSpacing)
+	if cap(t.glyphOffsets) < count {
+		t.glyphOffsets = make([]gltext.Point, count)
+	}
+	offsets := t.glyphOffsets[:count]
+	for i := range offsets {
+		offsets[i] = gltext.Point{}
+		for _, e := range t.effects {
+			dx, dy := e.Offset(i, t.effectTime)
+			offsets[i].X += dx
+			offsets[i].Y += dy
+		}
+	}
+	t.SetGlyphOffsets(offsets)
+}
+
+// SetGlyphOffsets moves each glyph away from the place it was laid out at.  Offset i
+// applies to the i-th drawn glyph.  Passing nil restores the original layout.
+// The bounding box is not affected.
+func (t *Text) SetGlyphOffsets(offsets []gltext.Point) {
+	if t.vboData == nil && !t.static {
+		// the data was dropped by ReleaseCPUBuffers
+		t.vboData, t.eboData = t.vertices()
+	}
+	if len(t.vboData) == 0 {
+		t.transformedData = nil
+		return
+	}
+	data := t.offsetVertices(offsets)
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	t.vertexBufferSubData(data, 0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// offsetVertices returns the vertices of the text moved by offsets, kept until the glyphs
+// are put back in their place or the string is laid out again, or the vertices of the
+// layout when offsets is nil
+func (t *Text) offsetVertices(offsets []gltext.Point) []float32 {
+	if offsets == nil {
+		t.transformedData = nil
+		return t.vboData
+	}
+	if len(t.transformedData) != len(t.vboData) {
+		t.transformedData = make([]float32, len(t.vboData))
+	}
+	copy(t.transformedData, t.vboData)
+	data := t.transformedData
+
+	// each quad holds 4 vertices of (x, y, u, v)
+	for i, offset := range offsets {
+		at := i * 16
+		if at+16 > len(data) {
+			break
+		}
+		for v := 0; v < 4; v++ {
+			data[at+v*4] += offset.X
+			data[at+v*4+1] += offset.Y
+		}
+	}
+	return data
+}
+
+// drawnVertices returns the vertices of the text as uploaded, moved by its glyph effects
+func (t *Text) drawnVertices() []float32 {
+	if t.transformedData != nil {
+		return t.transformedData
+	}
+	vbo, _ := t.vertices()
+	return vbo
+}
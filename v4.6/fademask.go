@@ -0,0 +1,45 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// FadeMask clips a text to a rectangle given in the coordinates of SetPosition.  Glyphs
+// dissolve over Fade.X pixels along the left and right edges and Fade.Y pixels along the
+// bottom and top edges instead of being cut off, which suits scrolling and marquee text.
+type FadeMask struct {
+	X1, X2 gltext.Point // lower left and upper right corners
+	Fade   gltext.Point
+}
+
+// SetFadeMask clips the text to the mask, nil drawing the whole text again.  The mask
+// applies to the built-in shaders and custom shaders using edge_mask.
+func (t *Text) SetFadeMask(m *FadeMask) {
+	t.fadeMask = m
+}
+
+// FadeMask returns the mask set by SetFadeMask
+func (t *Text) FadeMask() *FadeMask {
+	return t.fadeMask
+}
+
+// maskEdges returns the outer and inner rectangles of the fade mask in clip space.
+// Without a mask both cover everything drawn.
+func (t *Text) maskEdges(projection mgl32.Mat4) (outer, inner mgl32.Vec4) {
+	m := t.fadeMask
+	if m == nil {
+		return mgl32.Vec4{-2, -2, 2, 2}, mgl32.Vec4{-2, -2, 2, 2}
+	}
+	clip := func(x, y float32) mgl32.Vec2 {
+		p := projection.Mul4x1(mgl32.Vec4{x, y, 0, 1})
+		return mgl32.Vec2{p.X() / p.W(), p.Y() / p.W()}
+	}
+	low, high := clip(m.X1.X, m.X1.Y), clip(m.X2.X, m.X2.Y)
+	innerLow, innerHigh := clip(m.X1.X+m.Fade.X, m.X1.Y+m.Fade.Y), clip(m.X2.X-m.Fade.X, m.X2.Y-m.Fade.Y)
+	return mgl32.Vec4{low.X(), low.Y(), high.X(), high.Y()}, mgl32.Vec4{innerLow.X(), innerLow.Y(), innerHigh.X(), innerHigh.Y()}
+}
@@ -0,0 +1,127 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// FloatingText spawns short lived texts that rise and fade out, such as damage numbers
+// and pick up notices.  Texts whose lifetime is over are kept and reused by later spawns,
+// so once the pool has grown to the number of texts shown at once spawning creates no gl
+// objects.  Reserve grows the pool up front, EG while a level loads.
+type FloatingText struct {
+	Font *Font
+
+	// Lifetime in seconds of a spawned text.  Defaults to 1.
+	Lifetime float32
+
+	// Rise is the distance in pixels a text moves up over its lifetime.  Defaults to 40.
+	Rise float32
+
+	// FadeAfter is the part of the lifetime after which a text fades out.  Defaults to 0.5.
+	FadeAfter float32
+
+	// Easing of the rise, defaults to gltext.EaseOutQuad
+	Easing gltext.Easing
+
+	active []*floating
+	free   []*floating
+}
+
+// floating is a spawned text and where it started
+type floating struct {
+	text   *Text
+	origin mgl32.Vec2
+	age    float32
+}
+
+// NewFloatingText creates an empty pool of floating texts drawn with f
+func NewFloatingText(f *Font) *FloatingText {
+	return &FloatingText{Font: f, Lifetime: 1, Rise: 40, FadeAfter: 0.5, Easing: gltext.EaseOutQuad}
+}
+
+// Reserve creates texts until n can be shown at once without creating more
+func (ft *FloatingText) Reserve(n int) {
+	for len(ft.active)+len(ft.free) < n {
+		ft.free = append(ft.free, &floating{text: NewText(ft.Font, 1, 1)})
+	}
+}
+
+// Spawn shows the formatted string centered on position in color, reusing a text whose
+// lifetime is over when there is one.  The text is returned so that its scale or effects
+// can be changed, it must not be kept once its lifetime is over.
+func (ft *FloatingText) Spawn(position mgl32.Vec2, color mgl32.Vec3, fs string, argv ...interface{}) *Text {
+	var fl *floating
+	if n := len(ft.free); n > 0 {
+		fl, ft.free = ft.free[n-1], ft.free[:n-1]
+	} else {
+		fl = &floating{text: NewText(ft.Font, 1, 1)}
+	}
+	fl.origin, fl.age = position, 0
+	t := fl.text
+	t.SetString(fs, argv...)
+	t.SetColor(color)
+	t.transparency = 0
+	t.SetPosition(position)
+	ft.active = append(ft.active, fl)
+	return t
+}
+
+// Len returns the number of texts being shown
+func (ft *FloatingText) Len() int {
+	return len(ft.active)
+}
+
+// Update moves and fades the texts by dt seconds, recycling those whose lifetime is over
+func (ft *FloatingText) Update(dt float32) {
+	active := ft.active[:0]
+	for _, fl := range ft.active {
+		fl.age += dt
+		if ft.Lifetime <= 0 || fl.age >= ft.Lifetime {
+			ft.free = append(ft.free, fl)
+			continue
+		}
+		progress := fl.age / ft.Lifetime
+		rise := progress
+		if ft.Easing != nil {
+			rise = ft.Easing(progress)
+		}
+		fl.text.Update(dt)
+		fl.text.SetPosition(mgl32.Vec2{fl.origin.X(), fl.origin.Y() + ft.Rise*rise})
+		fl.text.transparency = 0
+		if progress > ft.FadeAfter && ft.FadeAfter < 1 {
+			fl.text.transparency = (progress - ft.FadeAfter) / (1 - ft.FadeAfter)
+		}
+		active = append(active, fl)
+	}
+	for i := len(active); i < len(ft.active); i++ {
+		ft.active[i] = nil
+	}
+	ft.active = active
+}
+
+// Draw draws the texts being shown
+func (ft *FloatingText) Draw() {
+	for _, fl := range ft.active {
+		fl.text.Draw()
+	}
+}
+
+// Clear recycles every text being shown
+func (ft *FloatingText) Clear() {
+	ft.free = append(ft.free, ft.active...)
+	ft.active = ft.active[:0]
+}
+
+// Release releases every text of the pool
+func (ft *FloatingText) Release() {
+	ft.Clear()
+	for _, fl := range ft.free {
+		fl.text.Release()
+	}
+	ft.free = nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"math"
+)
+
+// gl constants used by glTF
+const (
+	gltfFloat        = 5126
+	gltfUnsignedInt  = 5125
+	gltfArrayBuffer  = 34962
+	gltfElementArray = 34963
+	gltfLinear       = 9729
+)
+
+// WriteGLTF writes the quads of the text, with their texture coordinates and the atlas
+// pages they sample, as a self-contained glTF 2.0 file so that other engines and content
+// creation tools can use text laid out by gltext.  The text lies in the xy plane, centered
+// around the origin, unitsPerPixel converting pixels to scene units.  Each atlas page used
+// becomes a material colored like the text.
+func (t *Text) WriteGLTF(w io.Writer, unitsPerPixel float32) error {
+	s := t.LayoutSnapshot()
+	doc := gltfDocument{
+		Asset:    map[string]string{"version": "2.0", "generator": "gltext"},
+		Scenes:   []map[string][]int{{"nodes": {0}}},
+		Nodes:    []map[string]int{{"mesh": 0}},
+		Samplers: []map[string]int{{"magFilter": gltfLinear, "minFilter": gltfLinear}},
+	}
+	var data bytes.Buffer
+	var primitives []gltfPrimitive
+	pages := t.Font.Config.PageImages()
+	color, alpha := t.Color()
+
+	// a primitive per page, in the order pages are first used
+	var order []int
+	byPage := map[int][]GlyphSnapshot{}
+	for _, g := range s.Glyphs {
+		if _, ok := byPage[g.Page]; !ok {
+			order = append(order, g.Page)
+		}
+		byPage[g.Page] = append(byPage[g.Page], g)
+	}
+	for _, page := range order {
+		glyphs := byPage[page]
+		positions := make([]float32, 0, len(glyphs)*12)
+		uvs := make([]float32, 0, len(glyphs)*8)
+		indices := make([]uint32, 0, len(glyphs)*6)
+		min := []float32{math.MaxFloat32, math.MaxFloat32, 0}
+		max := []float32{-math.MaxFloat32, -math.MaxFloat32, 0}
+		for i, g := range glyphs {
+			corners := [4][4]float32{
+				{g.X1.X, g.X1.Y, g.UV1.X, g.UV1.Y},
+				{g.X2.X, g.X1.Y, g.UV2.X, g.UV1.Y},
+				{g.X2.X, g.X2.Y, g.UV2.X, g.UV2.Y},
+				{g.X1.X, g.X2.Y, g.UV1.X, g.UV2.Y},
+			}
+			for _, c := range corners {
+				x, y := c[0]*unitsPerPixel, c[1]*unitsPerPixel
+				positions = append(positions, x, y, 0)
+				uvs = append(uvs, c[2], c[3])
+				min[0], min[1] = float32(math.Min(float64(min[0]), float64(x))), float32(math.Min(float64(min[1]), float64(y)))
+				max[0], max[1] = float32(math.Max(float64(max[0]), float64(x))), float32(math.Max(float64(max[1]), float64(y)))
+			}
+			first := uint32(i * 4)
+			indices = append(indices, first, first+1, first+2, first, first+2, first+3)
+		}
+
+		position := doc.addAccessor(&data, positions, gltfArrayBuffer, "VEC3", len(glyphs)*4)
+		doc.Accessors[position].Min, doc.Accessors[position].Max = min, max
+		uv := doc.addAccessor(&data, uvs, gltfArrayBuffer, "VEC2", len(glyphs)*4)
+		index := doc.addAccessor(&data, indices, gltfElementArray, "SCALAR", len(indices))
+
+		uri, err := pngDataURI(pages, page)
+		if err != nil {
+			return err
+		}
+		doc.Images = append(doc.Images, map[string]string{"uri": uri})
+		doc.Textures = append(doc.Textures, map[string]int{"source": len(doc.Images) - 1, "sampler": 0})
+		doc.Materials = append(doc.Materials, gltfMaterial{
+			PBR: gltfPBR{
+				BaseColorFactor:  [4]float32{color[0], color[1], color[2], alpha},
+				BaseColorTexture: map[string]int{"index": len(doc.Textures) - 1},
+				RoughnessFactor:  1,
+			},
+			AlphaMode:   "BLEND",
+			DoubleSided: true,
+		})
+		primitives = append(primitives, gltfPrimitive{
+			Attributes: map[string]int{"POSITION": position, "TEXCOORD_0": uv},
+			Indices:    index,
+			Material:   len(doc.Materials) - 1,
+		})
+	}
+	doc.Meshes = []map[string][]gltfPrimitive{{"primitives": primitives}}
+	doc.Buffers = []gltfBuffer{{
+		ByteLength: data.Len(),
+		URI:        "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(data.Bytes()),
+	}}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+type gltfDocument struct {
+	Asset       map[string]string            `json:"asset"`
+	Scene       int                          `json:"scene"`
+	Scenes      []map[string][]int           `json:"scenes"`
+	Nodes       []map[string]int             `json:"nodes"`
+	Meshes      []map[string][]gltfPrimitive `json:"meshes"`
+	Materials   []gltfMaterial               `json:"materials,omitempty"`
+	Textures    []map[string]int             `json:"textures,omitempty"`
+	Samplers    []map[string]int             `json:"samplers"`
+	Images      []map[string]string          `json:"images,omitempty"`
+	Buffers     []gltfBuffer                 `json:"buffers"`
+	BufferViews []gltfBufferView             `json:"bufferViews,omitempty"`
+	Accessors   []gltfAccessor               `json:"accessors,omitempty"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   int            `json:"material"`
+}
+
+type gltfMaterial struct {
+	PBR         gltfPBR `json:"pbrMetallicRoughness"`
+	AlphaMode   string  `json:"alphaMode"`
+	DoubleSided bool    `json:"doubleSided"`
+}
+
+type gltfPBR struct {
+	BaseColorFactor  [4]float32     `json:"baseColorFactor"`
+	BaseColorTexture map[string]int `json:"baseColorTexture"`
+	MetallicFactor   float32        `json:"metallicFactor"`
+	RoughnessFactor  float32        `json:"roughnessFactor"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+// addAccessor appends values, a slice of float32 or uint32, to data and returns the
+// index of the accessor reading them
+func (doc *gltfDocument) addAccessor(data *bytes.Buffer, values interface{}, target int, kind string, count int) int {
+	offset := data.Len()
+	binary.Write(data, binary.LittleEndian, values)
+	componentType := gltfFloat
+	if _, ok := values.([]uint32); ok {
+		componentType = gltfUnsignedInt
+	}
+	doc.BufferViews = append(doc.BufferViews, gltfBufferView{ByteOffset: offset, ByteLength: data.Len() - offset, Target: target})
+	doc.Accessors = append(doc.Accessors, gltfAccessor{BufferView: len(doc.BufferViews) - 1, ComponentType: componentType, Count: count, Type: kind})
+	return len(doc.Accessors) - 1
+}
+
+func pngDataURI(pages []*image.NRGBA, page int) (string, error) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, pages[page]); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(encoded.Bytes()), nil
+}
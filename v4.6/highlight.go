@@ -0,0 +1,86 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// highlight is a range of runes drawn over a background
+type highlight struct {
+	start, end int
+	color      mgl32.Vec4
+}
+
+// Highlight draws a background of the given color behind the runes from start up to end,
+// one rectangle for every line the range covers, such as search results.  Highlights
+// refer to rune indices and are kept when the string changes.  They are drawn by Draw.
+func (t *Text) Highlight(start, end int, color mgl32.Vec4) {
+	t.highlights = append(t.highlights, highlight{start: start, end: end, color: color})
+}
+
+// ClearHighlights removes every highlight
+func (t *Text) ClearHighlights() {
+	t.highlights = t.highlights[:0]
+}
+
+// RangeRects returns the lower left and upper right corners of the rectangles covering the
+// advances of the runes from start up to end, one per line, in the coordinates of
+// SetPosition after scaling.  Rotation is not taken into account.
+func (t *Text) RangeRects(start, end int) [][2]gltext.Point {
+	rects := [][2]gltext.Point{}
+	lineHeight := t.Font.lineHeight()
+	line := -1
+	for q, glyph := range t.glyphs {
+		if glyph.Index < start || glyph.Index >= end {
+			continue
+		}
+		left := glyph.X + t.origin.X
+		bottom := glyph.Y + t.origin.Y
+		right := left + t.CharSpacing[q]
+		if glyph.Line != line || len(rects) == 0 {
+			line = glyph.Line
+			rects = append(rects, [2]gltext.Point{{X: left, Y: bottom}, {X: right, Y: bottom + lineHeight}})
+			continue
+		}
+		r := &rects[len(rects)-1]
+		if left < r[0].X {
+			r[0].X = left
+		}
+		if right > r[1].X {
+			r[1].X = right
+		}
+	}
+	for i, r := range rects {
+		rects[i] = [2]gltext.Point{t.drawnPoint(r[0].X, r[0].Y), t.drawnPoint(r[1].X, r[1].Y)}
+	}
+	return rects
+}
+
+// drawDecorations draws the backgrounds of the highlights, the underlines of the links and
+// the squiggles, creating their rectangles when first needed
+func (t *Text) drawDecorations() {
+	if len(t.highlights) == 0 && len(t.links) == 0 && len(t.squiggles) == 0 {
+		return
+	}
+	if t.decorations == nil {
+		rects, err := NewRects(t.Font)
+		if err != nil {
+			gltext.TextDebug(err.Error())
+			return
+		}
+		t.decorations = rects
+	}
+	t.decorations.Clear()
+	for _, h := range t.highlights {
+		for _, r := range t.RangeRects(h.start, h.end) {
+			t.decorations.Add(r[0].X, r[0].Y, r[1].X, r[1].Y, h.color)
+		}
+	}
+	t.addUnderlines(t.decorations)
+	t.addSquiggles(t.decorations)
+	t.decorations.Draw()
+}
@@ -0,0 +1,43 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/mikzorz/gltext"
+)
+
+// watchFontLeak warns when f is garbage collected before Release when gltext.LeakWarnings
+// is set.  The gl objects cannot be deleted by the finalizer as it does not run on the
+// thread owning the context.
+func watchFontLeak(f *Font) {
+	if gltext.LeakWarnings {
+		runtime.SetFinalizer(f, func(f *Font) {
+			fmt.Printf("[warning] font %q was garbage collected without Release, leaking %d textures\n", fontName(f), len(f.pageTextureIDs))
+		})
+	}
+}
+
+func watchTextLeak(t *Text) {
+	if gltext.LeakWarnings {
+		runtime.SetFinalizer(t, func(t *Text) {
+			fmt.Printf("[warning] text %q was garbage collected without Release, leaking its buffers\n", t.String)
+		})
+	}
+}
+
+// unwatchLeak removes the finalizer of a released font or text
+func unwatchLeak(obj interface{}) {
+	runtime.SetFinalizer(obj, nil)
+}
+
+func fontName(f *Font) string {
+	if f.Config == nil {
+		return ""
+	}
+	return f.Config.Name
+}
@@ -0,0 +1,77 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// AddLink makes the runes from start up to end a link calling onClick when it is clicked
+// (see UpdateLinks).  The runes take the link color of gltext.DefaultMarkdownStyle and,
+// like the links of markup, are underlined by Draw.  Links are removed by SetMarkup.
+func (t *Text) AddLink(start, end int, onClick func()) {
+	t.links = append(t.links, gltext.Link{Start: start, End: end})
+	for len(t.linkClicks) < len(t.links)-1 {
+		t.linkClicks = append(t.linkClicks, nil)
+	}
+	t.linkClicks = append(t.linkClicks, onClick)
+	if c := gltext.DefaultMarkdownStyle.LinkColor; c != nil {
+		t.styleRuns = append(t.styleRuns, gltext.StyleRun{Start: start, End: end, Color: c})
+	}
+	t.SetString("%s", t.String)
+}
+
+// UpdateLinks calls the onClick of the link added by AddLink that is pressed and released
+// under the cursor, given in the coordinates of SetPosition (see Font.CursorPosition),
+// along with whether the mouse button is down.  Presses that began elsewhere are ignored.
+func (t *Text) UpdateLinks(cursor mgl32.Vec2, pressed bool) {
+	at := t.linkIndexAt(cursor)
+	switch {
+	case pressed && !t.wasPressed:
+		t.pressedLink = at
+	case !pressed && t.wasPressed:
+		if at >= 0 && at == t.pressedLink && at < len(t.linkClicks) && t.linkClicks[at] != nil {
+			t.linkClicks[at]()
+		}
+		t.pressedLink = -1
+	}
+	t.wasPressed = pressed
+}
+
+// linkIndexAt returns the index of the link drawn under p or -1
+func (t *Text) linkIndexAt(p mgl32.Vec2) int {
+	local := t.localPoint(p)
+	vbo, _ := t.vertices()
+	for q, glyph := range t.glyphs {
+		for i, link := range t.links {
+			if glyph.Index < link.Start || glyph.Index >= link.End {
+				continue
+			}
+			if X1, X2 := t.quadBounds(vbo, q); inside(local, X1, X2) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// addUnderlines adds a line along the bottom of every line of every link, in the color
+// of its first rune
+func (t *Text) addUnderlines(rects *Rects) {
+	for _, link := range t.links {
+		color := t.color.Vec4(1 - t.transparency)
+		if c := t.styleRuns.ColorAt(link.Start); c != nil {
+			color = colorVec3(c).Vec4(color[3])
+		}
+		for _, r := range t.RangeRects(link.Start, link.End) {
+			thickness := (r[1].Y - r[0].Y) / 16
+			if thickness < 1 {
+				thickness = 1
+			}
+			rects.Add(r[0].X, r[0].Y, r[1].X, r[0].Y+thickness, color)
+		}
+	}
+}
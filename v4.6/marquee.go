@@ -0,0 +1,139 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// Marquee scrolls a single line of text that is wider than Width back and forth or from
+// start to end, such as music titles and news tickers.  The text is clipped to the box
+// with a fade mask and scrolls as Update is called.  Text that fits is not scrolled.
+type Marquee struct {
+	*Text
+
+	// Width of the box the text scrolls in
+	Width float32
+
+	// Speed in pixels per second of scrolling
+	Speed float32
+
+	// Pause in seconds at either end of the text
+	Pause float32
+
+	// Fade is the width of the edges the text dissolves along
+	Fade float32
+
+	// Bounce scrolls back to the start instead of jumping to it.  Without Loop the text
+	// stops once it has scrolled to its end, and back to its start when bouncing.
+	Bounce bool
+	Loop   bool
+
+	// Position of the center of the box away from the center of the window
+	Position mgl32.Vec2
+
+	offset    float32 // distance scrolled
+	direction float32 // 1 towards the end of the text, -1 back to the start
+	wait      float32 // remaining pause
+	restart   bool    // jump back to the start once the pause is over
+	done      bool
+}
+
+// NewMarquee creates an empty looping marquee of the given width using the same scaling
+// boundaries as NewText.
+func NewMarquee(f *Font, width, scaleMin, scaleMax float32) *Marquee {
+	m := &Marquee{Text: NewText(f, scaleMin, scaleMax), Width: width, Speed: 40, Pause: 1, Loop: true}
+	m.Text.SetPivot(0, 0.5)
+	m.Reset()
+	return m
+}
+
+// SetString replaces the text and scrolls back to its start
+func (m *Marquee) SetString(fs string, argv ...interface{}) {
+	m.Text.SetString(fs, argv...)
+	m.Reset()
+}
+
+// SetPosition moves the center of the box
+func (m *Marquee) SetPosition(v mgl32.Vec2) {
+	m.Position = v
+	m.place()
+}
+
+// Reset scrolls back to the start of the text, pausing there before scrolling again
+func (m *Marquee) Reset() {
+	m.offset, m.direction, m.wait = 0, 1, m.Pause
+	m.restart, m.done = false, false
+	m.place()
+}
+
+// Scrolling reports whether the text is wider than the box and has not stopped
+func (m *Marquee) Scrolling() bool {
+	return m.overflow() > 0 && !m.done
+}
+
+// Update advances the animations of the text and scrolls it by dt seconds
+func (m *Marquee) Update(dt float32) {
+	m.Text.Update(dt)
+	m.advance(dt)
+	m.place()
+}
+
+// overflow is how far the text has to scroll for its end to reach the end of the box
+func (m *Marquee) overflow() float32 {
+	if overflow := m.Text.Width() - m.Width; overflow > 0 {
+		return overflow
+	}
+	return 0
+}
+
+func (m *Marquee) advance(dt float32) {
+	overflow := m.overflow()
+	if overflow == 0 || m.done {
+		m.offset = 0
+		return
+	}
+	if m.wait > 0 {
+		m.wait -= dt
+		if m.wait > 0 {
+			return
+		}
+		dt, m.wait = -m.wait, 0
+		if m.restart {
+			m.offset, m.wait, m.restart = 0, m.Pause, false
+			return
+		}
+	}
+	m.offset += m.direction * m.Speed * dt
+	switch {
+	case m.direction > 0 && m.offset >= overflow:
+		m.offset, m.wait = overflow, m.Pause
+		switch {
+		case m.Bounce:
+			m.direction = -1
+		case m.Loop:
+			m.restart = true
+		default:
+			m.done = true
+		}
+	case m.direction < 0 && m.offset <= 0:
+		m.offset, m.wait, m.direction = 0, m.Pause, 1
+		m.done = !m.Loop
+	}
+}
+
+// place moves the text by the distance scrolled and clips it to the box
+func (m *Marquee) place() {
+	left := m.Position.X() - m.Width/2
+	m.Text.SetPosition(mgl32.Vec2{left - m.offset, m.Position.Y()})
+
+	height := m.Text.Height()
+	m.SetFadeMask(&FadeMask{
+		X1:   gltext.Point{X: left, Y: m.Position.Y() - height},
+		X2:   gltext.Point{X: left + m.Width, Y: m.Position.Y() + height},
+		Fade: gltext.Point{X: m.Fade},
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext/layout"
+)
+
+// TextOption configures a text created by NewText before any string is laid out, so
+// that the settings depending on each other are applied together.  The exported fields
+// they set are deprecated as a way of configuring texts.  They remain exported and
+// writable until the next major version: programs read them, and since a getter cannot
+// share the name of a field, unexporting them would break the reads along with the writes.
+//
+//	text := v41.NewText(font, 1, 1, v41.WithMaxWidth(300), v41.WithColor(mgl32.Vec3{1, 1, 1}))
+type TextOption func(t *Text)
+
+// WithScaleRange sets the scaling boundaries of the text, replacing those given to
+// NewText.  The text rests at the same scale as one created with them.
+func WithScaleRange(scaleMin, scaleMax float32) TextOption {
+	return func(t *Text) {
+		t.setScaleRange(scaleMin, scaleMax)
+	}
+}
+
+// WithAlign aligns every line of the text
+func WithAlign(a layout.Align) TextOption {
+	return func(t *Text) {
+		t.Align = a
+	}
+}
+
+// WithMaxWidth wraps lines wider than width
+func WithMaxWidth(width float32) TextOption {
+	return func(t *Text) {
+		t.MaxWidth = width
+	}
+}
+
+// WithMaxRuneCount truncates strings longer than n runes
+func WithMaxRuneCount(n int) TextOption {
+	return func(t *Text) {
+		t.MaxRuneCount = n
+	}
+}
+
+// WithTruncate sets how strings longer than MaxRuneCount are shortened
+func WithTruncate(mode TruncateMode) TextOption {
+	return func(t *Text) {
+		t.Truncate = mode
+	}
+}
+
+// WithNormalize composes strings into their NFC form before laying them out
+func WithNormalize() TextOption {
+	return func(t *Text) {
+		t.Normalize = true
+	}
+}
+
+// WithColor sets the color of the text
+func WithColor(color mgl32.Vec3) TextOption {
+	return func(t *Text) {
+		t.SetColor(color)
+	}
+}
+
+// WithVertexFormat stores the vertices of the text in format
+func WithVertexFormat(format VertexFormat) TextOption {
+	return func(t *Text) {
+		t.SetVertexFormat(format)
+	}
+}
+
+// WithAnchor places the given point of the bounding box at the position of the text
+func WithAnchor(a Anchor) TextOption {
+	return func(t *Text) {
+		t.SetAnchor(a)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/gl/v4.6-core/gl"
+)
+
+// TextPool recycles the vertex arrays and buffers of texts shown for a short time, such as
+// tooltips and pick up notices, rather than creating and deleting gl objects for each of
+// them.  Released texts keep their buffers, so acquiring a text and setting a string no
+// longer than those it held before does not allocate gpu memory either.
+type TextPool struct {
+	Font               *Font
+	ScaleMin, ScaleMax float32
+
+	free []*Text
+}
+
+// NewTextPool creates an empty pool of texts of f using the same scaling boundaries as NewText
+func NewTextPool(f *Font, scaleMin, scaleMax float32) *TextPool {
+	return &TextPool{Font: f, ScaleMin: scaleMin, ScaleMax: scaleMax}
+}
+
+// Reserve creates texts until n of them are pooled
+func (p *TextPool) Reserve(n int) {
+	for len(p.free) < n {
+		p.free = append(p.free, NewText(p.Font, p.ScaleMin, p.ScaleMax))
+	}
+}
+
+// Acquire returns a pooled text, in the state of a new one, or creates a text when the pool is empty
+func (p *TextPool) Acquire() *Text {
+	if n := len(p.free); n > 0 {
+		t := p.free[n-1]
+		p.free[n-1], p.free = nil, p.free[:n-1]
+		return t
+	}
+	return NewText(p.Font, p.ScaleMin, p.ScaleMax)
+}
+
+// Release returns t to the pool, clearing its string, style and registrations.  t must not
+// be used once released.  Texts of another font are released for good.
+func (p *TextPool) Release(t *Text) {
+	if t.Font != p.Font {
+		t.Release()
+		return
+	}
+	t.recycle(p.ScaleMin, p.ScaleMax)
+	p.free = append(p.free, t)
+}
+
+// Len returns the number of pooled texts
+func (p *TextPool) Len() int {
+	return len(p.free)
+}
+
+// Clear releases the gl objects of the pooled texts
+func (p *TextPool) Clear() {
+	for _, t := range p.free {
+		t.Release()
+	}
+	p.free = nil
+}
+
+// recycle resets t to the state of a new text while keeping its vertex array and buffers
+func (t *Text) recycle(scaleMin, scaleMax float32) {
+	t.ClearPaletteColor()
+	t.UnregisterAccessible()
+	if len(t.attributes) > 0 {
+		gl.BindVertexArray(t.vao)
+		for _, a := range t.attributes {
+			gl.DisableVertexAttribArray(a.location)
+		}
+		gl.BindVertexArray(0)
+		t.releaseAttributes()
+	}
+	if t.decorations != nil {
+		t.decorations.Release()
+	}
+
+	f, vao, vbo, ebo, sharedEBO := t.Font, t.vao, t.vbo, t.ebo, t.sharedEBO
+	vboCapacity, eboCapacity := t.vboCapacity, t.eboCapacity
+	if t.static {
+		// the buffers are allocated again for dynamic drawing
+		vboCapacity, eboCapacity = 0, 0
+	}
+	vertexFormat, vertexColored := t.vertexFormat, t.vertexColored
+	*t = Text{Font: f, vao: vao, vbo: vbo, ebo: ebo, vboCapacity: vboCapacity, eboCapacity: eboCapacity, sharedEBO: sharedEBO}
+	if vertexFormat != VFFloat32 || vertexColored {
+		// new texts store 32 bit floats without colors
+		gl.BindVertexArray(t.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+		t.setVertexPointers()
+		gl.BindVertexArray(0)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		t.vboCapacity = 0
+	}
+	t.setDefaults(scaleMin, scaleMax)
+}
@@ -0,0 +1,67 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// squiggle is a range of runes drawn over a wavy underline
+type squiggle struct {
+	start, end int
+	amplitude  float32
+	color      mgl32.Vec4
+}
+
+// Squiggle draws a wavy line of the given color under the runes from start up to end, such
+// as those of misspelled words.  The line rises and falls by amplitude pixels around a
+// line amplitude pixels above the bottom of each line.  Like highlights, squiggles refer
+// to rune indices and are kept when the string changes.  They are drawn by Draw.
+func (t *Text) Squiggle(start, end int, amplitude float32, color mgl32.Vec4) {
+	if amplitude < 1 {
+		amplitude = 1
+	}
+	t.squiggles = append(t.squiggles, squiggle{start: start, end: end, amplitude: amplitude, color: color})
+}
+
+// ClearSquiggles removes every squiggle
+func (t *Text) ClearSquiggles() {
+	t.squiggles = t.squiggles[:0]
+}
+
+// addSquiggles adds the wave of every line of every squiggle as a run of short rectangles
+// following a triangle wave with a period of four amplitudes
+func (t *Text) addSquiggles(rects *Rects) {
+	for _, s := range t.squiggles {
+		thickness := s.amplitude / 2
+		if thickness < 1 {
+			thickness = 1
+		}
+		step := s.amplitude / 2
+		for _, r := range t.RangeRects(s.start, s.end) {
+			middle := r[0].Y + s.amplitude
+			for x := r[0].X; x < r[1].X; x += step {
+				right := x + step
+				if right > r[1].X {
+					right = r[1].X
+				}
+				y := middle + triangleWave((x+step/2-r[0].X)/(4*s.amplitude))*s.amplitude
+				rects.Add(x, y-thickness/2, right, y+thickness/2, s.color)
+			}
+		}
+	}
+}
+
+// triangleWave rises from 0 to 1, falls to -1 and returns to 0 as phase goes from 0 to 1
+func triangleWave(phase float32) float32 {
+	phase -= float32(int(phase))
+	switch {
+	case phase < 0.25:
+		return phase * 4
+	case phase < 0.75:
+		return 2 - phase*4
+	}
+	return phase*4 - 4
+}
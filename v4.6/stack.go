@@ -0,0 +1,156 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// StackItem is anything a Stack can position: texts, labels, buttons and other stacks.
+type StackItem interface {
+	GetBoundingBox() (X1, X2 gltext.Point)
+	SetAnchor(a Anchor)
+	SetPosition(v mgl32.Vec2)
+	Draw()
+}
+
+// StackAlign places items across the direction of a stack
+type StackAlign int
+
+const (
+	StackAlignStart  StackAlign = iota // left of a vertical stack, top of a horizontal one
+	StackAlignCenter                   // centered
+	StackAlignEnd                      // right of a vertical stack, bottom of a horizontal one
+)
+
+// Stack places its items one below the other (a vertical stack) or side by side
+// (a horizontal stack).  Items are laid out again when they change size.
+type Stack struct {
+	Horizontal bool
+	Spacing    float32
+	Align      StackAlign
+	Items      []StackItem
+
+	position mgl32.Vec2
+	anchor   Anchor
+	sizes    []mgl32.Vec2 // sizes of the items when they were last laid out
+	size     mgl32.Vec2
+}
+
+// NewVStack creates a stack placing items one below the other
+func NewVStack(spacing float32, items ...StackItem) *Stack {
+	s := &Stack{Spacing: spacing, anchor: AnchorTopLeft}
+	s.Add(items...)
+	return s
+}
+
+// NewHStack creates a stack placing items side by side, from left to right
+func NewHStack(spacing float32, items ...StackItem) *Stack {
+	s := NewVStack(spacing, items...)
+	s.Horizontal = true
+	s.Layout()
+	return s
+}
+
+// Add appends items to the stack
+func (s *Stack) Add(items ...StackItem) {
+	s.Items = append(s.Items, items...)
+	s.Layout()
+}
+
+// SetAnchor determines which point of the stack's box is placed at its position.
+// The default is AnchorTopLeft.
+func (s *Stack) SetAnchor(a Anchor) {
+	s.anchor = a
+	s.Layout()
+}
+
+// SetPosition places the anchor point of the stack at v
+func (s *Stack) SetPosition(v mgl32.Vec2) {
+	s.position = v
+	s.Layout()
+}
+
+// Size returns the width and height of the stack
+func (s *Stack) Size() mgl32.Vec2 {
+	return s.size
+}
+
+// GetBoundingBox returns the lower left and upper right corners of the stack
+func (s *Stack) GetBoundingBox() (X1, X2 gltext.Point) {
+	topLeft := s.topLeft()
+	return gltext.Point{X: topLeft.X(), Y: topLeft.Y() - s.size.Y()}, gltext.Point{X: topLeft.X() + s.size.X(), Y: topLeft.Y()}
+}
+
+// Layout positions the items.  It is called by Draw when the size of an item changed.
+func (s *Stack) Layout() {
+	s.sizes = s.sizes[:0]
+	s.size = mgl32.Vec2{}
+	main, cross := 1, 0
+	if s.Horizontal {
+		main, cross = 0, 1
+	}
+	for i, item := range s.Items {
+		size := itemSize(item)
+		s.sizes = append(s.sizes, size)
+		if i > 0 {
+			s.size[main] += s.Spacing
+		}
+		s.size[main] += size[main]
+		if size[cross] > s.size[cross] {
+			s.size[cross] = size[cross]
+		}
+	}
+
+	// offsets from the top left corner, y pointing down
+	topLeft := s.topLeft()
+	at := float32(0)
+	for i, item := range s.Items {
+		var offset mgl32.Vec2
+		offset[main] = at
+		offset[cross] = (s.size[cross] - s.sizes[i][cross]) * float32(s.Align) / 2
+		at += s.sizes[i][main] + s.Spacing
+
+		item.SetAnchor(AnchorTopLeft)
+		item.SetPosition(mgl32.Vec2{topLeft.X() + offset.X(), topLeft.Y() - offset.Y()})
+	}
+}
+
+// topLeft returns the top left corner of the stack's box
+func (s *Stack) topLeft() mgl32.Vec2 {
+	x, y := s.position.X(), s.position.Y()
+	switch s.anchor {
+	case AnchorTop, AnchorCenter, AnchorBottom:
+		x -= s.size.X() / 2
+	case AnchorTopRight, AnchorRight, AnchorBottomRight:
+		x -= s.size.X()
+	}
+	switch s.anchor {
+	case AnchorLeft, AnchorCenter, AnchorRight:
+		y += s.size.Y() / 2
+	case AnchorBottomLeft, AnchorBottom, AnchorBottomRight:
+		y += s.size.Y()
+	}
+	return mgl32.Vec2{x, y}
+}
+
+// Draw lays the items out again if their size changed and draws them
+func (s *Stack) Draw() {
+	for i, item := range s.Items {
+		if i >= len(s.sizes) || itemSize(item) != s.sizes[i] {
+			s.Layout()
+			break
+		}
+	}
+	for _, item := range s.Items {
+		item.Draw()
+	}
+}
+
+func itemSize(item StackItem) mgl32.Vec2 {
+	X1, X2 := item.GetBoundingBox()
+	return mgl32.Vec2{X2.X - X1.X, X2.Y - X1.Y}
+}
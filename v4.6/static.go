@@ -0,0 +1,67 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/gl/v4.6-core/gl"
+)
+
+// SetStatic uploads the buffers of a text that will not change again with GL_STATIC_DRAW
+// and drops the vbo and ebo data kept on the cpu, halving the memory held by labels that
+// are set once.  SetString fails until SetStatic(false) is called, which keeps the string
+// and lays it out again for dynamic drawing.  Glyph effects are not applied to static
+// texts; Mesh, LayoutSnapshot and command lists lay the string out again when needed.
+func (t *Text) SetStatic(static bool) {
+	if static == t.static {
+		return
+	}
+	if !static {
+		t.static = false
+		// the next upload allocates the buffers again for dynamic drawing
+		t.vboCapacity, t.eboCapacity = 0, 0
+		t.SetString("%s", t.String)
+		return
+	}
+	vbo, ebo := t.vertices()
+	if t.eboIndexCount > 0 {
+		gl.BindVertexArray(t.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+		t.vertexBufferData(vbo, gl.STATIC_DRAW)
+		t.vboCapacity = len(vbo)
+		if !t.sharedEBO {
+			gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.ebo)
+			gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(ebo), gl.Ptr(ebo), gl.STATIC_DRAW)
+			t.eboCapacity = len(ebo)
+		}
+		gl.BindVertexArray(0)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	}
+	t.static = true
+	t.vboData, t.eboData, t.transformedData, t.packedData = nil, nil, nil, nil
+}
+
+// ReleaseCPUBuffers drops the vbo and ebo data kept on the cpu once they were uploaded, so
+// that applications holding thousands of texts do not keep two copies of every vertex.
+// Unlike SetStatic the text can still be set, the next SetString generating the data
+// again.  Glyph effects generate the data again and keep it while they run.
+func (t *Text) ReleaseCPUBuffers() {
+	t.vboData, t.eboData, t.transformedData, t.packedData = nil, nil, nil, nil
+}
+
+// Static reports whether SetStatic froze the text
+func (t *Text) Static() bool {
+	return t.static
+}
+
+// reload lays the string out again once the font was reloaded, keeping static texts static
+func (t *Text) reload() {
+	if !t.static {
+		t.SetString("%s", t.String)
+		return
+	}
+	t.SetStatic(false)
+	t.SetStatic(true)
+}
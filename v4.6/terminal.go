@@ -0,0 +1,280 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var gridVertexShaderSource string = `
+#version 330
+
+uniform mat4 orthographic_matrix;
+
+in vec2 position;
+in vec2 uv;
+in vec4 color;
+
+out vec2 fragment_uv;
+out vec4 fragment_cell_color;
+
+void main() {
+  fragment_uv = uv;
+  fragment_cell_color = color;
+  gl_Position = orthographic_matrix * vec4(position, 0, 1);
+}
+` + "\x00"
+
+// backgrounds are drawn with a negative uv, glyphs sample the atlas
+var gridFragmentShaderSource string = `
+#version 330
+
+uniform sampler2D fragment_texture;
+uniform int sdf;
+
+in vec2 fragment_uv;
+in vec4 fragment_cell_color;
+out vec4 fragment_color;
+
+void main() {
+  if (fragment_uv.x < 0.0) {
+    fragment_color = fragment_cell_color;
+    return;
+  }
+  float alpha = texture(fragment_texture, fragment_uv).w;
+  if (sdf == 1) {
+    float width = max(fwidth(alpha), 0.0001);
+    alpha = smoothstep(0.5 - width, 0.5 + width, alpha);
+  }
+  fragment_color = vec4(fragment_cell_color.xyz, fragment_cell_color.w * alpha);
+}
+` + "\x00"
+
+// Cell is a character of a terminal grid along with its colors
+type Cell struct {
+	Rune       rune
+	Foreground mgl32.Vec4
+	Background mgl32.Vec4
+}
+
+// TerminalGrid draws fixed size cells of a monospace font, as needed by terminal
+// emulators and roguelikes.  Only the cells changed since the last draw are sent to
+// the gpu and the whole grid, backgrounds included, is drawn at once.  Glyphs must be
+// on the first page of the atlas.
+type TerminalGrid struct {
+	Font       *Font
+	Rows, Cols int
+
+	// the size of a cell, which defaults to the widest advance and the line height of the font
+	CellWidth, CellHeight float32
+
+	// Position is the top left corner of the grid in pixels from the center of the window
+	Position mgl32.Vec2
+
+	cells []Cell
+
+	// the range of cells, in row major order, changed since the last draw
+	dirtyFirst, dirtyLast int
+
+	vao, vbo, ebo uint32
+	vboData       []float32 // x, y, u, v, r, g, b, a per vertex.  backgrounds come first.
+	eboData       []int32
+}
+
+// the number of floats of a quad
+const gridQuadSize = 4 * 8
+
+// NewTerminalGrid creates a grid of blank cells
+func NewTerminalGrid(f *Font, rows, cols int) (*TerminalGrid, error) {
+	if f.gridProgram == 0 {
+		program, err := NewProgram(gridVertexShaderSource, gridFragmentShaderSource)
+		if err != nil {
+			return nil, err
+		}
+		f.gridProgram = program
+	}
+	g := newTerminalGrid(f, rows, cols)
+	gl.GenVertexArrays(1, &g.vao)
+	gl.GenBuffers(1, &g.vbo)
+	gl.GenBuffers(1, &g.ebo)
+
+	gl.BindVertexArray(g.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, g.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(g.vboData), gl.Ptr(g.vboData), gl.DYNAMIC_DRAW)
+	offset := 0
+	for _, attribute := range []struct {
+		name string
+		size int32
+	}{{"position", 2}, {"uv", 2}, {"color", 4}} {
+		location := uint32(gl.GetAttribLocation(f.gridProgram, gl.Str(attribute.name+"\x00")))
+		gl.EnableVertexAttribArray(location)
+		gl.VertexAttribPointer(location, attribute.size, gl.FLOAT, false, 8*4, gl.PtrOffset(offset*4))
+		offset += int(attribute.size)
+	}
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, g.ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(g.eboData), gl.Ptr(g.eboData), gl.STATIC_DRAW)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	return g, nil
+}
+
+// newTerminalGrid sets up the cpu side of a grid
+func newTerminalGrid(f *Font, rows, cols int) *TerminalGrid {
+	g := &TerminalGrid{Font: f, Rows: rows, Cols: cols, CellWidth: f.cellAdvance(), CellHeight: f.lineHeight()}
+	count := rows * cols
+	g.cells = make([]Cell, count)
+	g.vboData = make([]float32, 2*count*gridQuadSize)
+	g.eboData = quadPattern(2 * count)
+	g.invalidate()
+	return g
+}
+
+// Get returns the cell at row and col
+func (g *TerminalGrid) Get(row, col int) Cell {
+	return g.cells[row*g.Cols+col]
+}
+
+// Set replaces the cell at row and col.  Cells outside of the grid are ignored.
+func (g *TerminalGrid) Set(row, col int, c Cell) {
+	if row < 0 || row >= g.Rows || col < 0 || col >= g.Cols {
+		return
+	}
+	i := row*g.Cols + col
+	if g.cells[i] == c {
+		return
+	}
+	g.cells[i] = c
+	g.markDirty(i)
+}
+
+// Print writes s from row and col onwards, stopping at the end of the row, and returns
+// the column following the last rune written.
+func (g *TerminalGrid) Print(row, col int, s string, fg, bg mgl32.Vec4) int {
+	for _, r := range s {
+		if col >= g.Cols {
+			break
+		}
+		g.Set(row, col, Cell{Rune: r, Foreground: fg, Background: bg})
+		col++
+	}
+	return col
+}
+
+// Fill replaces every cell by c
+func (g *TerminalGrid) Fill(c Cell) {
+	for row := 0; row < g.Rows; row++ {
+		for col := 0; col < g.Cols; col++ {
+			g.Set(row, col, c)
+		}
+	}
+}
+
+// SetPosition places the top left corner of the grid at v
+func (g *TerminalGrid) SetPosition(v mgl32.Vec2) {
+	g.Position = v
+	g.invalidate()
+}
+
+// invalidate marks every cell as changed
+func (g *TerminalGrid) invalidate() {
+	g.dirtyFirst, g.dirtyLast = 0, len(g.cells)-1
+}
+
+func (g *TerminalGrid) markDirty(i int) {
+	if g.dirtyFirst > g.dirtyLast {
+		g.dirtyFirst, g.dirtyLast = i, i
+		return
+	}
+	if i < g.dirtyFirst {
+		g.dirtyFirst = i
+	}
+	if i > g.dirtyLast {
+		g.dirtyLast = i
+	}
+}
+
+// updateVertices rewrites the vertex data of the changed cells and returns their range
+func (g *TerminalGrid) updateVertices() (first, last int, changed bool) {
+	if g.dirtyFirst > g.dirtyLast {
+		return 0, 0, false
+	}
+	first, last = g.dirtyFirst, g.dirtyLast
+	for i := first; i <= last; i++ {
+		g.writeCell(i)
+	}
+	g.dirtyFirst, g.dirtyLast = 1, 0
+	return first, last, true
+}
+
+// writeCell stores the background quad and glyph quad of cell i
+func (g *TerminalGrid) writeCell(i int) {
+	c := g.cells[i]
+	x := g.Position.X() + float32(i%g.Cols)*g.CellWidth
+	y := g.Position.Y() - float32(i/g.Cols+1)*g.CellHeight
+	writeGridQuad(g.vboData[i*gridQuadSize:], x, y, x+g.CellWidth, y+g.CellHeight, -1, -1, -1, -1, c.Background)
+
+	glyph := g.vboData[(len(g.cells)+i)*gridQuadSize:]
+	index := g.Font.Config.RuneRanges.GetGlyphIndex(c.Rune)
+	if c.Rune == 0 || c.Rune == ' ' || index < 0 || g.Font.Config.Glyphs[index].Page != 0 {
+		// nothing is drawn
+		writeGridQuad(glyph, 0, 0, 0, 0, -1, -1, -1, -1, mgl32.Vec4{})
+		return
+	}
+	metrics := g.Font.Config.Glyphs[index]
+	tP1, tP2 := metrics.GetTexturePositions(g.Font)
+	y += float32(metrics.OffsetY)
+	writeGridQuad(glyph, x, y, x+float32(metrics.Advance), y+float32(metrics.Height), tP1.X, tP2.Y, tP2.X, tP1.Y, c.Foreground)
+}
+
+// writeGridQuad stores a counter-clockwise quad from (x1, y1) to (x2, y2)
+func writeGridQuad(data []float32, x1, y1, x2, y2, u1, v1, u2, v2 float32, color mgl32.Vec4) {
+	corners := [4][4]float32{{x1, y1, u1, v1}, {x2, y1, u2, v1}, {x2, y2, u2, v2}, {x1, y2, u1, v2}}
+	for i, corner := range corners {
+		copy(data[i*8:], corner[:])
+		copy(data[i*8+4:], color[:])
+	}
+}
+
+// Draw uploads the changed cells and draws the grid
+func (g *TerminalGrid) Draw() {
+	if len(g.cells) == 0 {
+		return
+	}
+	if first, last, changed := g.updateVertices(); changed {
+		size := (last - first + 1) * gridQuadSize
+		gl.BindBuffer(gl.ARRAY_BUFFER, g.vbo)
+		for _, at := range []int{first * gridQuadSize, (len(g.cells) + first) * gridQuadSize} {
+			gl.BufferSubData(gl.ARRAY_BUFFER, 4*at, 4*size, gl.Ptr(g.vboData[at:]))
+		}
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	}
+	gl.UseProgram(g.Font.gridProgram)
+	location := gl.GetUniformLocation(g.Font.gridProgram, gl.Str("orthographic_matrix\x00"))
+	gl.UniformMatrix4fv(location, 1, false, &g.Font.OrthographicMatrix[0])
+	sdf := int32(0)
+	if g.Font.Config.SDF {
+		sdf = 1
+	}
+	gl.Uniform1i(gl.GetUniformLocation(g.Font.gridProgram, gl.Str("sdf\x00")), sdf)
+	gl.Uniform1i(gl.GetUniformLocation(g.Font.gridProgram, gl.Str("fragment_texture\x00")), 0)
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, g.Font.textureID)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(g.vao)
+	gl.DrawElements(gl.TRIANGLES, int32(len(g.eboData)), gl.UNSIGNED_INT, nil)
+	gl.BindVertexArray(0)
+	gl.Disable(gl.BLEND)
+}
+
+// Release releases the buffers of the grid
+func (g *TerminalGrid) Release() {
+	gl.DeleteBuffers(1, &g.vbo)
+	gl.DeleteBuffers(1, &g.ebo)
+	gl.DeleteVertexArrays(1, &g.vao)
+}
@@ -5,10 +5,14 @@
 package v46
 
 import (
+	"errors"
 	"fmt"
 	"github.com/mikzorz/gltext"
+	"github.com/mikzorz/gltext/layout"
 	"github.com/go-gl/gl/v4.6-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"golang.org/x/text/unicode/norm"
+	"sort"
 )
 
 // CharacterSide shows which side of a character is
@@ -21,22 +25,91 @@ const (
 	CSUnknown
 )
 
+// Anchor is the point of the text's bounding box that is
+// placed at the text's position
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota
+	AnchorTopLeft
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
 // Text is not designed to be accessed concurrently
 type Text struct {
 	Font *Font
 
+	// Family provides the faces of style runs.  Font is its regular face.
+	Family    *FontFamily
+	styleRuns gltext.StyleRuns
+
+	// regions of the string pointing to addresses, see LinkAt, and the callbacks of those
+	// added by AddLink along with the link pressed during UpdateLinks
+	links       []gltext.Link
+	linkClicks  []func()
+	pressedLink int
+	wasPressed  bool
+
+	// Highlighter, when set, replaces the style runs whenever the string changes
+	Highlighter gltext.Highlighter
+
+	// backgrounds drawn behind ranges of runes and wavy lines under them, drawn along
+	// with the link underlines
+	highlights  []highlight
+	squiggles   []squiggle
+	decorations *Rects
+
 	// final position on screen
 	finalPosition mgl32.Vec2
 
-	// text color
-	color mgl32.Vec3
+	// text color and its 1 - alpha, so that texts are opaque by default
+	color        mgl32.Vec3
+	transparency float32
+
+	// the name of the palette color followed by the text, see SetPaletteColor
+	paletteColor string
+
+	// weight and softness of distance field fonts in pixels of the atlas
+	weight   float32
+	softness float32
 
 	// scaling the text
-	Scale       float32
+	Scale float32
+
+	// the scaling boundaries given to NewText
+	//
+	// Deprecated: set them with NewText or WithScaleRange, assigning them does not scale
+	// the text again.
 	ScaleMin    float32
 	ScaleMax    float32
 	scaleMatrix mgl32.Mat4
 
+	// the point scaling and rotation happen around as a fraction of the bounding box
+	pivot gltext.Point
+
+	// counter-clockwise rotation in radians
+	rotation float32
+
+	// running animations advanced by Update
+	scaleTween    *gltext.Tween
+	scaleFrom     float32
+	scaleTo       float32
+	positionTween *gltext.Tween
+	positionFrom  mgl32.Vec2
+	positionTo    mgl32.Vec2
+
+	// per-glyph effects advanced by Update
+	effects         []gltext.GlyphEffect
+	effectTime      float32
+	glyphOffsets    []gltext.Point
+	transformedData []float32
+
 	// Fadeout reduces alpha
 	FadeOutBegun      bool
 	FadeOutFrameCount float32 // number of frames since drawing began
@@ -45,6 +118,48 @@ type Text struct {
 	// bounding box of text
 	BoundingBox *BoundingBox
 
+	// Cull skips drawing the text when its bounding box, as drawn, lies outside of the view.
+	// CullX1 and CullX2 are the lower left and upper right corners of a camera rect, in the
+	// coordinates of SetPosition, replacing the view when they differ.
+	Cull           bool
+	CullX1, CullX2 gltext.Point
+
+	// Depth sets how the text interacts with the depth buffer and PolygonOffset, the factor
+	// and units of glPolygonOffset, pulls tested texts towards the viewer to avoid z-fighting
+	// with the surface they are placed on
+	Depth         DepthMode
+	PolygonOffset [2]float32
+
+	// Blend determines how the text is combined with what was drawn before it
+	Blend BlendMode
+
+	// the rectangle the text is clipped to, see SetFadeMask
+	fadeMask *FadeMask
+
+	// values for uniforms and attributes of custom shaders
+	uniforms   map[string]interface{}
+	attributes []*customAttribute
+
+	// the glyphs drawn, in the order of the quads in the vbo, and the lower left and upper
+	// right corners of their ink before the text is centered
+	glyphs      []layout.Glyph
+	glyphBounds [][2]gltext.Point
+
+	// what the glyphs were laid out with, and the number of leading runes the string being
+	// set shares with their string, so that only the lines following them are laid out again
+	glyphKey       glyphKey
+	unchangedRunes int
+
+	// the quads of each face and atlas page
+	pageRanges []pageRange
+
+	// the font generation the text was laid out with
+	fontGeneration int
+
+	// the error of initializing the font when the text was created, its vertex pointers
+	// being set once the font can be initialized
+	initErr error
+
 	// general opengl values
 	vao           uint32
 	vbo           uint32
@@ -54,11 +169,49 @@ type Text struct {
 	eboData       []int32
 	eboIndexCount int
 
+	// the number of values the gpu buffers were allocated with
+	vboCapacity int
+	eboCapacity int
+
+	// the vertex array draws with the element buffer of the font rather than ebo, see quadIndices
+	sharedEBO bool
+
+	// the type of the values of the vbo, see SetVertexFormat, and the values last converted to it
+	vertexFormat VertexFormat
+	packedData   []uint16
+
+	// whether the vbo holds the color of each vertex, the color of the text and framebuffer
+	// encoding it was interleaved with, and the values last interleaved, see interleavesColors
+	vertexColored   bool
+	vertexColor     mgl32.Vec3
+	vertexSRGB      bool
+	interleavedData []float32
+
+	// the origin the vertices were sent centered with, for programs without glyph_origin
+	vertexOrigin gltext.Point
+
+	// the buffers were uploaded with GL_STATIC_DRAW and the vbo and ebo data dropped, see SetStatic
+	static bool
+
 	// determines how many prefix characters are drawn on screen
 	RuneCount int
 
-	// no longer than this string
+	// no longer than this string, Truncate determining how longer strings are shortened.
+	//
+	// Deprecated: set them with WithMaxRuneCount and WithTruncate when creating the text.
 	MaxRuneCount int
+	Truncate     TruncateMode
+
+	// OnTruncate, when set, is called with the number of runes dropped from a string.  When
+	// Truncate is TruncateReject it is the number of runes over MaxRuneCount.
+	OnTruncate func(dropped int)
+
+	// Normalize composes strings into their NFC form in SetString, so that decomposed
+	// accents use the precomposed glyphs of the atlas.  Composing may shorten a string,
+	// moving the rune indices of style runs, links and highlights given for it.
+	//
+	// Deprecated: set it with WithNormalize when creating the text.
+	Normalize bool
 
 	// X1, X2: the lower left and upper right points of a box that bounds the text with a center point (0,0)
 
@@ -67,11 +220,47 @@ type Text struct {
 	// upper right
 	X2 gltext.Point
 
+	// the offset moving the laid out glyphs, and the vertices of the vbo data, around (0,0)
+	origin gltext.Point
+
 	// Screen position away from center
 	Position mgl32.Vec2
 
+	// the point of the bounding box placed at Position
+	anchor Anchor
+
 	String      string
 	CharSpacing []float32
+
+	// ParagraphSpacing is the extra space placed between blank-line-separated paragraphs
+	// FirstLineIndent shifts the first line of each paragraph to the right
+	ParagraphSpacing float32
+	FirstLineIndent  float32
+
+	// MaxWidth wraps lines that would become wider than this value, zero disables wrapping
+	//
+	// Deprecated: set it with WithMaxWidth when creating the text.
+	MaxWidth float32
+
+	// HangingIndent is where wrapped lines begin
+	// TabWidth is the distance between tab stops, zero uses four spaces
+	HangingIndent float32
+	TabWidth      float32
+
+	// Monospace places every glyph in a cell as wide as the widest advance of the font,
+	// centering narrower glyphs, so that columns line up
+	Monospace bool
+
+	// ShowWhitespace draws the layout whitespace symbols, such as a middle dot, over spaces,
+	// tabs and newlines for editors.  The font needs to hold the symbols (see
+	// layout.SpaceSymbol).  Like the fields above it applies from the next SetString.
+	ShowWhitespace bool
+
+	// Align places each line within the text, aligning the ends of its lines rather than
+	// moving the whole block
+	//
+	// Deprecated: set it with WithAlign when creating the text.
+	Align layout.Align
 }
 
 func (t *Text) GetLength() int {
@@ -80,21 +269,19 @@ func (t *Text) GetLength() int {
 
 // NewText creates a new text object with scaling boundaries
 // the rest state of the text when not being interacted with
-// is scaleMin.  most likely one wants to use 1.0.
-func NewText(f *Font, scaleMin, scaleMax float32) (t *Text) {
+// is a scale of 1, or scaleMin when the boundaries exclude 1.
+// opts configure the text once it is created, see TextOption.
+// SetStringChecked returns the error of initializing a lazy or shared font.
+func NewText(f *Font, scaleMin, scaleMax float32, opts ...TextOption) (t *Text) {
 	t = &Text{}
 	t.Font = f
-
-	// text hover values
-	// "resting state" of a text object is the min scale
-	t.ScaleMin, t.ScaleMax = scaleMin, scaleMax
-	t.SetScale(1)
-	glfloat_size := int32(4)
-
-	// stride of the buffered data
-	xy_count := int32(2)
-	stride := xy_count + int32(2)
-
+	// shared fonts fail until they are uploaded
+	if t.initErr = f.Init(); t.initErr != nil {
+		gltext.TextDebug(t.initErr.Error())
+	}
+	f.checkThread("NewText")
+	watchTextLeak(t)
+	t.setDefaults(scaleMin, scaleMax)
 	gl.GenVertexArrays(1, &t.vao)
 	gl.GenBuffers(1, &t.vbo)
 	gl.GenBuffers(1, &t.ebo)
@@ -110,26 +297,7 @@ func NewText(f *Font, scaleMin, scaleMax float32) (t *Text) {
 	// vbo
 	// specify the buffer for which the VertexAttribPointer calls apply
 	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
-
-	gl.EnableVertexAttribArray(t.Font.centeredPositionAttribute)
-	gl.VertexAttribPointer(
-		t.Font.centeredPositionAttribute,
-		2,
-		gl.FLOAT,
-		false,
-		glfloat_size*stride,
-		gl.PtrOffset(0),
-	)
-
-	gl.EnableVertexAttribArray(t.Font.uvAttribute)
-	gl.VertexAttribPointer(
-		t.Font.uvAttribute,
-		2,
-		gl.FLOAT,
-		false,
-		glfloat_size*stride,
-		gl.PtrOffset(int(glfloat_size*xy_count)),
-	)
+	t.setVertexPointers()
 
 	// ebo
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.ebo)
@@ -138,11 +306,59 @@ func NewText(f *Font, scaleMin, scaleMax float32) (t *Text) {
 	gl.BindVertexArray(0)
 	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+
+	for _, opt := range opts {
+		opt(t)
+	}
 	return t
 }
 
+// initFont initializes the font of the text, setting the vertex pointers that could not be
+// when the text was created before the font
+func (t *Text) initFont() error {
+	if err := t.Font.Init(); err != nil {
+		return err
+	}
+	if t.initErr != nil {
+		t.initErr = nil
+		gl.BindVertexArray(t.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+		t.setVertexPointers()
+		gl.BindVertexArray(0)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	}
+	return nil
+}
+
+// setScaleRange sets the scaling boundaries of the text along with its "resting state",
+// a scale of 1 when the boundaries allow it and scaleMin otherwise
+func (t *Text) setScaleRange(scaleMin, scaleMax float32) {
+	// text hover values
+	t.ScaleMin, t.ScaleMax = scaleMin, scaleMax
+	if !t.SetScale(1) {
+		t.SetScale(scaleMin)
+	}
+}
+
+// setDefaults sets the scaling boundaries and the initial state of a new text
+func (t *Text) setDefaults(scaleMin, scaleMax float32) {
+	t.setScaleRange(scaleMin, scaleMax)
+	t.SetPivot(0.5, 0.5)
+	if t.Font.premultiplied {
+		t.Blend = BMPremultiplied
+	}
+}
+
 // Release releases text resources.
 func (t *Text) Release() {
+	unwatchLeak(t)
+	t.ClearPaletteColor()
+	t.UnregisterAccessible()
+	t.releaseAttributes()
+	if t.decorations != nil {
+		t.decorations.Release()
+		t.decorations = nil
+	}
 	gl.DeleteBuffers(1, &t.vbo)
 	gl.DeleteBuffers(1, &t.ebo)
 	gl.DeleteVertexArrays(1, &t.vao)
@@ -171,60 +387,383 @@ func (t *Text) AddScale(s float32) bool {
 	return true
 }
 
+// SetPivot sets the point that scaling grows from.  The values are fractions of
+// the bounding box where (0,0) is the lower left and (1,1) the upper right corner.
+// The default (0.5,0.5) scales around the center of the text.
+func (t *Text) SetPivot(px, py float32) {
+	t.pivot = gltext.Point{X: px, Y: py}
+}
+
+// pivotedScale returns the scale matrix moved so that the pivot stays in place when
+// drawn with the given projection
+func (t *Text) pivotedScale(projection mgl32.Mat4) mgl32.Mat4 {
+	x := t.X1.X + t.pivot.X*(t.X2.X-t.X1.X)
+	y := t.X1.Y + t.pivot.Y*(t.X2.Y-t.X1.Y)
+	if t.rotation != 0 {
+		// rotating in clip space would distort the text unless the window is square, so
+		// the rotation happens in pixels: O * T(p) * R * S * T(-p) * O^-1
+		o := projection
+		model := mgl32.Translate3D(x, y, 0).
+			Mul4(mgl32.HomogRotate3DZ(t.rotation)).
+			Mul4(t.scaleMatrix).
+			Mul4(mgl32.Translate3D(-x, -y, 0))
+		return o.Mul4(model).Mul4(o.Inv())
+	}
+	if x == 0 && y == 0 {
+		return t.scaleMatrix
+	}
+	// the scale matrix is applied after the orthographic projection
+	p := projection.Mul4x1(mgl32.Vec4{x, y, 0, 1})
+	return mgl32.Translate3D(p.X(), p.Y(), 0).Mul4(t.scaleMatrix).Mul4(mgl32.Translate3D(-p.X(), -p.Y(), 0))
+}
+
+// AnimateScale scales the text to target over duration seconds as Update is called.
+// The target is kept within ScaleMin and ScaleMax.
+func (t *Text) AnimateScale(target, duration float32, easing gltext.Easing) {
+	if target > t.ScaleMax {
+		target = t.ScaleMax
+	}
+	if target < t.ScaleMin {
+		target = t.ScaleMin
+	}
+	t.scaleFrom, t.scaleTo = t.Scale, target
+	t.scaleTween = gltext.NewTween(duration, easing)
+}
+
+// AnimatePosition moves the text to target over duration seconds as Update is called.
+func (t *Text) AnimatePosition(target mgl32.Vec2, duration float32, easing gltext.Easing) {
+	t.positionFrom, t.positionTo = t.Position, target
+	t.positionTween = gltext.NewTween(duration, easing)
+}
+
+// IsAnimating returns true while an animation started by AnimateScale or AnimatePosition is running.
+func (t *Text) IsAnimating() bool {
+	return t.scaleTween != nil || t.positionTween != nil
+}
+
+// Update advances running animations and effects by dt seconds.  It is expected to be called once per frame.
+func (t *Text) Update(dt float32) {
+	t.updateEffects(dt)
+	if t.scaleTween != nil {
+		progress, done := t.scaleTween.Update(dt)
+		s := gltext.Lerp(t.scaleFrom, t.scaleTo, progress)
+
+		// easings that overshoot must not leave the scale boundaries
+		if s > t.ScaleMax {
+			s = t.ScaleMax
+		}
+		if s < t.ScaleMin {
+			s = t.ScaleMin
+		}
+		t.Scale = s
+		t.scaleMatrix = mgl32.Scale3D(s, s, s)
+		if done {
+			t.scaleTween = nil
+		}
+	}
+	if t.positionTween != nil {
+		progress, done := t.positionTween.Update(dt)
+		t.SetPosition(mgl32.Vec2{
+			gltext.Lerp(t.positionFrom.X(), t.positionTo.X(), progress),
+			gltext.Lerp(t.positionFrom.Y(), t.positionTo.Y(), progress),
+		})
+		if done {
+			t.positionTween = nil
+		}
+	}
+}
+
+// SetWeight thickens (positive values) or thins (negative values) the glyphs of a
+// signed distance field font by moving the outline delta pixels.  The change is limited
+// by the spread of the distance field.  Other fonts are not affected.
+func (t *Text) SetWeight(delta float32) {
+	t.weight = delta
+}
+
+// SetSoftness widens the antialiased edge of the glyphs of a signed distance field font
+// by px pixels, which is useful for blurred or glowing text or for smoothing
+// heavily scaled down text.  Other fonts are not affected.
+func (t *Text) SetSoftness(px float32) {
+	t.softness = px
+}
+
+// sdfUniforms converts weight and softness into distances stored by the field
+func (t *Text) sdfUniforms() (threshold, softness float32) {
+	spread := t.Font.Config.SDFSpread
+	if spread <= 0 {
+		return 0.5, 0
+	}
+	return 0.5 - t.weight/(2*spread), t.softness / (2 * spread)
+}
+
+// SetColor sets the color of the text in sRGB, see Font.SRGBFramebuffer
 func (t *Text) SetColor(color mgl32.Vec3) {
 	t.color = color
 }
 
+// SetColorRGBA8 sets the color and alpha of the text from 8 bit components
+func (t *Text) SetColorRGBA8(r, g, b, a uint8) {
+	t.color = mgl32.Vec3{float32(r) / 255, float32(g) / 255, float32(b) / 255}
+	t.transparency = 1 - float32(a)/255
+}
+
+// SetColorHex sets the color of the text from a color name or a hexadecimal color such
+// as "#RRGGBBAA", see gltext.ParseColor
+func (t *Text) SetColorHex(s string) error {
+	c, err := gltext.ParseColor(s)
+	if err != nil {
+		return err
+	}
+	t.SetColorRGBA8(c.R, c.G, c.B, c.A)
+	return nil
+}
+
+// Color returns the color and alpha of the text
+func (t *Text) Color() (color mgl32.Vec3, alpha float32) {
+	return t.color, 1 - t.transparency
+}
+
+// runes returns the runes of s to be laid out, normalized and truncated as configured
+func (t *Text) runes(s string) ([]rune, error) {
+	if t.Normalize {
+		s = norm.NFC.String(s)
+	}
+	return t.truncate([]rune(s))
+}
+
 // SetString performs creates new vbo and ebo objects as well as to perform all
 // binding required for displaying text to screen
 func (t *Text) SetString(fs string, argv ...interface{}) {
-	indices := []rune(fmt.Sprintf(fs, argv...))
-	if t.MaxRuneCount > 0 && len(indices) > t.MaxRuneCount+1 {
-		indices = indices[0:t.MaxRuneCount]
+	if err := t.SetStringChecked(fs, argv...); err != nil {
+		gltext.TextDebug(err.Error())
+	}
+}
+
+// SetStringChecked sets the string like SetString, returning an error rather than setting
+// it when it is longer than MaxRuneCount and Truncate is TruncateReject.
+func (t *Text) SetStringChecked(fs string, argv ...interface{}) error {
+	t.Font.checkThread("SetString")
+	if t.static {
+		return errors.New("Static text cannot be set.")
+	}
+	if err := t.initFont(); err != nil {
+		return err
+	}
+	indices, err := t.runes(fmt.Sprintf(fs, argv...))
+	if err != nil {
+		return err
+	}
+	t.unchangedRunes = 0
+	if t.glyphs != nil && t.fontGeneration == t.Font.generation {
+		t.unchangedRunes = commonRunePrefix([]rune(t.String), indices)
 	}
 	t.String = string(indices)
+	t.fontGeneration = t.Font.generation
+	if t.Highlighter != nil {
+		t.styleRuns = t.Highlighter.Highlight(t.String)
+	}
 
-	// ebo, vbo data
-	glfloat_size := int32(4)
+	t.RuneCount = len(indices)
+	previousVBO, previousEBO := t.vboData, t.eboData
+	t.layOut(indices)
+
+	if gltext.IsDebug {
+		prefix := gltext.DebugPrefix()
+		fmt.Printf("%s bounding box %v %v\n", prefix, t.X1, t.X2)
+		fmt.Printf("%s text vbo data\n%v\n", prefix, t.vboData)
+		fmt.Printf("%s text ebo data\n%v\n", prefix, t.eboData)
+	}
+	t.upload(previousVBO, previousEBO)
 
+	// SetString can be called at anytime.  we want to make sure that if the user is updating the text,
+	// the previous position will be maintained
+	t.SetPosition(t.Position)
+	return nil
+}
+
+// layOut generates the vbo and ebo data of indices along with the bounding box, centered
+// around the orthographic (0,0) point by the origin of the text
+func (t *Text) layOut(indices []rune) {
+	// ebo, vbo data
 	t.vboIndexCount = len(indices) * 4 * 2 * 2 // 4 indexes per rune (containing 2 position + 2 texture)
 	t.eboIndexCount = len(indices) * 6         // each rune requires 6 triangle indices for a quad
-	t.RuneCount = len(indices)
 	t.vboData = make([]float32, t.vboIndexCount, t.vboIndexCount)
 	t.eboData = make([]int32, t.eboIndexCount, t.eboIndexCount)
 
+	// the glyphs of the previous string are no longer moved, effects offset the new ones
+	// once updated
+	t.transformedData = nil
+
 	// generate the basic vbo data and bounding box
-	// center the vbo data around the orthographic (0,0) point
+	// center the text around the orthographic (0,0) point
 	t.X1 = gltext.Point{0, 0}
 	t.X2 = gltext.Point{0, 0}
 	t.makeBufferData(indices)
 	t.centerTheData(t.getLowerLeft())
+}
 
-	if gltext.IsDebug {
-		prefix := gltext.DebugPrefix()
-		fmt.Printf("%s bounding box %v %v\n", prefix, t.X1, t.X2)
-		fmt.Printf("%s text vbo data\n%v\n", prefix, t.vboData)
-		fmt.Printf("%s text ebo data\n%v\n", prefix, t.eboData)
+// vertices returns the vbo and ebo data of the text.  When SetStatic dropped them the
+// string is laid out again without keeping the data.
+func (t *Text) vertices() (vbo []float32, ebo []int32) {
+	if t.vboData != nil || t.eboIndexCount == 0 {
+		return t.vboData, t.eboData
+	}
+	t.layOut([]rune(t.String))
+	vbo, ebo = t.vboData, t.eboData
+	t.vboData, t.eboData = nil, nil
+	return vbo, ebo
+}
+
+// SetStringStrict sets the string like SetString and returns the runes of it that are not
+// drawn for lack of a glyph in the faces of the text, see SkippedRunes.
+func (t *Text) SetStringStrict(fs string, argv ...interface{}) []rune {
+	t.SetString(fs, argv...)
+	return t.SkippedRunes()
+}
+
+// SkippedRunes returns the runes of the string that were skipped for lack of a glyph, each
+// once in order of appearance, so that missing glyphs can be detected by tests rather than
+// on screenshots.  Newlines and tabs, which never have a glyph, are not reported.
+func (t *Text) SkippedRunes() []rune {
+	drawn := make(map[int]bool, len(t.glyphs))
+	for _, glyph := range t.glyphs {
+		drawn[glyph.Index] = true
+	}
+	skipped := []rune{}
+	reported := map[rune]bool{}
+	for i, r := range []rune(t.String) {
+		if drawn[i] || r == '\n' || r == '\t' || reported[r] {
+			continue
+		}
+		reported[r] = true
+		skipped = append(skipped, r)
 	}
-	if len(indices) > 0 {
+	return skipped
+}
+
+// Append adds s to the end of the string.  Only the lines from the last word of the
+// string are laid out again and, as with SetString, only the vertex data that changed is
+// sent to the gpu, which keeps growing logs cheap.
+func (t *Text) Append(s string) {
+	t.SetString("%s", t.String+s)
+}
+
+// ReplaceRange replaces the runes from start up to end with s.  Indices are clamped
+// to the length of the string.  As with Append, only the lines from the word holding
+// start are laid out again, unless the lines are aligned or styled.
+func (t *Text) ReplaceRange(start, end int, s string) {
+	t.SetString("%s", replaceRunes(t.String, start, end, s))
+}
+
+func replaceRunes(str string, start, end int, s string) string {
+	runes := []rune(str)
+	if end > len(runes) {
+		end = len(runes)
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > end {
+		start = end
+	}
+	return string(runes[:start]) + s + string(runes[end:])
+}
+
+// upload sends the vbo and ebo data as well as custom attributes to the gpu.  when the
+// buffers are large enough only the values following the common prefix with the previously
+// uploaded data are sent, so that changing the end of a string is cheap.
+func (t *Text) upload(previousVBO []float32, previousEBO []int32) {
+	glfloat_size := int32(4)
+	if t.eboIndexCount > 0 {
 		// in the event that we have no data to draw dont bother here
 		gl.BindVertexArray(t.vao)
 		gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
-		gl.BufferData(
-			gl.ARRAY_BUFFER, int(glfloat_size)*t.vboIndexCount, gl.Ptr(t.vboData), gl.DYNAMIC_DRAW)
-		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.ebo)
-		gl.BufferData(
-			gl.ELEMENT_ARRAY_BUFFER, int(glfloat_size)*t.eboIndexCount, gl.Ptr(t.eboData), gl.DYNAMIC_DRAW)
+		if colored := t.interleavesColors(); colored != t.vertexColored {
+			// the vertices gain or lose their color and the vbo its size
+			t.vertexColored = colored
+			t.setVertexPointers()
+			t.vboCapacity = 0
+		}
+		if t.vertexColored {
+			// the colors of the runs are not compared with those of the previous string
+			previousVBO = nil
+		}
+		if t.vboIndexCount <= t.vboCapacity {
+			if start := t.vboUploadStart(previousVBO); start < t.vboIndexCount {
+				t.vertexBufferSubData(t.vboData, start)
+			}
+		} else {
+			t.vertexBufferData(t.vboData, gl.DYNAMIC_DRAW)
+			t.vboCapacity = t.vboIndexCount
+		}
+		if isQuadPattern(t.eboData, t.quadCount()) {
+			// nothing to upload, the font holds these indices for all of its texts
+			gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.Font.quadIndices(t.eboIndexCount/6))
+			t.sharedEBO = true
+		} else {
+			if t.sharedEBO {
+				// ebo holds older indices than the previous ones
+				previousEBO, t.sharedEBO = nil, false
+			}
+			gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.ebo)
+			if t.eboIndexCount <= t.eboCapacity {
+				if start := commonEBOPrefix(previousEBO, t.eboData); start < t.eboIndexCount {
+					gl.BufferSubData(
+						gl.ELEMENT_ARRAY_BUFFER, int(glfloat_size)*start, int(glfloat_size)*(t.eboIndexCount-start), gl.Ptr(t.eboData[start:]))
+				}
+			} else {
+				gl.BufferData(
+					gl.ELEMENT_ARRAY_BUFFER, int(glfloat_size)*t.eboIndexCount, gl.Ptr(t.eboData), gl.DYNAMIC_DRAW)
+				t.eboCapacity = t.eboIndexCount
+			}
+		}
 		gl.BindVertexArray(0)
 
 		// possibly not necesssary?
 		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+
+		t.fillAttributes()
+	}
+}
+
+// vboUploadStart returns the first value of the vbo data that upload sends to the gpu when
+// previousVBO was uploaded before, or the length of the data when none changed.  As the
+// vertices are not centered on the cpu, a string whose extent changes starts after the
+// vertices of the runes it shares with the previous one.
+func (t *Text) vboUploadStart(previousVBO []float32) int {
+	start := commonVBOPrefix(previousVBO, t.vboData)
+	if start == len(t.vboData) {
+		return start
 	}
+	return t.subDataStart(start)
+}
 
-	// SetString can be called at anytime.  we want to make sure that if the user is updating the text,
-	// the previous position will be maintained
-	t.SetPosition(t.Position)
+// commonVBOPrefix returns the number of leading values shared by a and b
+func commonVBOPrefix(a, b []float32) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// commonRunePrefix returns the number of leading runes shared by a and b
+func commonRunePrefix(a, b []rune) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// commonEBOPrefix returns the number of leading indices shared by a and b
+func commonEBOPrefix(a, b []int32) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
 }
 
 // The block of text is positioned around the center of the screen, which in this case must
@@ -239,21 +778,50 @@ func (t *Text) getLowerLeft() (lowerLeft gltext.Point) {
 	return
 }
 
+// SetAnchor determines which point of the bounding box is placed at the
+// position given to SetPosition.  The default is AnchorCenter.
+func (t *Text) SetAnchor(a Anchor) {
+	t.anchor = a
+	t.SetPosition(t.Position)
+}
+
 // SetPosition prepares variables passed to the shader as well as values
 // used for bounding box calculations when clicking or hovering above text
 func (t *Text) SetPosition(v mgl32.Vec2) {
+	t.Position = v
+	center := t.center()
+
 	// transform to orthographic coordinates ranged -1 to 1 for the shader
-	t.finalPosition[0] = v.X() / (t.Font.WindowWidth / 2)
-	t.finalPosition[1] = v.Y() / (t.Font.WindowHeight / 2)
+	t.finalPosition[0] = center.X() / (t.Font.WindowWidth / 2)
+	t.finalPosition[1] = center.Y() / (t.Font.WindowHeight / 2)
 	if gltext.IsDebug {
-		t.BoundingBox.finalPosition[0] = v.X() / (t.Font.WindowWidth / 2)
-		t.BoundingBox.finalPosition[1] = v.Y() / (t.Font.WindowHeight / 2)
+		t.BoundingBox.finalPosition[0] = center.X() / (t.Font.WindowWidth / 2)
+		t.BoundingBox.finalPosition[1] = center.Y() / (t.Font.WindowHeight / 2)
 	}
-	t.Position = v
+}
+
+// center returns the screen position of the text's (0,0) point, which is
+// the Position shifted by the anchor
+func (t *Text) center() mgl32.Vec2 {
+	var offset gltext.Point
+	switch t.anchor {
+	case AnchorTopLeft, AnchorLeft, AnchorBottomLeft:
+		offset.X = t.X1.X
+	case AnchorTopRight, AnchorRight, AnchorBottomRight:
+		offset.X = t.X2.X
+	}
+	switch t.anchor {
+	case AnchorTopLeft, AnchorTop, AnchorTopRight:
+		offset.Y = t.X2.Y
+	case AnchorBottomLeft, AnchorBottom, AnchorBottomRight:
+		offset.Y = t.X1.Y
+	}
+	return mgl32.Vec2{t.Position.X() - offset.X, t.Position.Y() - offset.Y}
 }
 
 func (t *Text) GetBoundingBox() (X1, X2 gltext.Point) {
-	x, y := t.Position.X(), t.Position.Y()
+	c := t.center()
+	x, y := c.X(), c.Y()
 	X1.X = t.X1.X + x
 	X1.Y = t.X1.Y + y
 	X2.X = t.X2.X + x
@@ -262,6 +830,24 @@ func (t *Text) GetBoundingBox() (X1, X2 gltext.Point) {
 }
 
 func (t *Text) Draw() {
+	t.drawDecorations()
+	t.draw(t.Font.OrthographicMatrix, t.finalPosition)
+}
+
+// draw draws the text using projection, placing its center at finalPosition in clip space
+func (t *Text) draw(projection mgl32.Mat4, finalPosition mgl32.Vec2) {
+	if err := t.initFont(); err != nil {
+		gltext.TextDebug(err.Error())
+		return
+	}
+	t.Font.checkThread("Draw")
+	if t.fontGeneration != t.Font.generation {
+		// the font was reloaded
+		t.reload()
+	}
+	if t.Cull && t.culled(projection) {
+		return
+	}
 	if gltext.IsDebug {
 		t.BoundingBox.Draw()
 	}
@@ -273,33 +859,152 @@ func (t *Text) Draw() {
 		}
 	}
 
-	gl.UseProgram(t.Font.program)
+	drawCount := t.drawCount()
+	if drawCount <= 0 {
+		return
+	}
+	if t.colorsChanged() {
+		t.uploadColors()
+	}
 
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, t.Font.textureID)
+	// between RenderState.Begin and End the program, projection and blending are already set
+	active := t.Font.state
+	state := active
+	if !state.covers(projection, t.Blend) || gltext.IsDebug {
+		state = nil
+	}
+	if state == nil {
+		gl.UseProgram(t.Font.program)
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.Uniform1i(t.Font.fragmentTextureUniform, 0)
+		gl.UniformMatrix4fv(t.Font.orthographicMatrixUniform, 1, false, &projection[0])
+	}
+	if state.textureChanged(t.Font.textureID) {
+		gl.BindTexture(gl.TEXTURE_2D, t.Font.textureID)
+	}
 
 	// uniforms
-	gl.Uniform1i(t.Font.fragmentTextureUniform, 0)
-	gl.Uniform1f(t.Font.fadeoutUniform, t.FadeOutPerFrame*t.FadeOutFrameCount)
-	gl.Uniform4fv(t.Font.colorUniform, 1, &t.color[0])
-	gl.Uniform2fv(t.Font.finalPositionUniform, 1, &t.finalPosition[0])
-	gl.UniformMatrix4fv(t.Font.orthographicMatrixUniform, 1, false, &t.Font.OrthographicMatrix[0])
-	gl.UniformMatrix4fv(t.Font.scaleMatrixUniform, 1, false, &t.scaleMatrix[0])
+	if fadeout := t.FadeOutPerFrame * t.FadeOutFrameCount; state.fadeoutChanged(fadeout) {
+		gl.Uniform1f(t.Font.fadeoutUniform, fadeout)
+	}
+	textColor := t.Font.outputColor(t.color, 1-t.transparency)
+	if t.vertexColored {
+		// the colors are in the vbo, the uniform only fades the text
+		textColor = mgl32.Vec4{1, 1, 1, 1 - t.transparency}
+	} else if t.Font.vertexColors {
+		// the color array is disabled, the shader reading this value for every vertex
+		gl.VertexAttrib4f(t.Font.colorAttribute, 1, 1, 1, 1)
+	}
+	if state.colorChanged(textColor) {
+		gl.Uniform4fv(t.Font.colorUniform, 1, &textColor[0])
+	}
+	gl.Uniform2fv(t.Font.finalPositionUniform, 1, &finalPosition[0])
+	if t.Font.originUniform >= 0 {
+		origin := t.shaderOrigin()
+		gl.Uniform2fv(t.Font.originUniform, 1, &origin[0])
+	}
+	if t.Font.Config.SDF {
+		threshold, softness := t.sdfUniforms()
+		gl.Uniform1f(t.Font.sdfThresholdUniform, threshold)
+		gl.Uniform1f(t.Font.sdfSoftnessUniform, softness)
+	}
+	outer, inner := t.maskEdges(projection)
+	gl.Uniform4fv(t.Font.maskOuterUniform, 1, &outer[0])
+	gl.Uniform4fv(t.Font.maskInnerUniform, 1, &inner[0])
+	scaleMatrix := t.pivotedScale(projection)
+	gl.UniformMatrix4fv(t.Font.scaleMatrixUniform, 1, false, &scaleMatrix[0])
+	t.applyUniforms()
 
 	// draw
-	drawCount := int32(t.RuneCount * 6)
-	if drawCount > int32(t.eboIndexCount) {
-		drawCount = int32(t.eboIndexCount)
-	}
-	if drawCount <= 0 {
-		return
+	if state == nil {
+		t.Blend.begin()
 	}
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	depth := t.beginDepth()
+	t.Font.timer.begin()
 	gl.BindVertexArray(t.vao)
-	gl.DrawElements(gl.TRIANGLES, drawCount, gl.UNSIGNED_INT, nil)
-	gl.BindVertexArray(0)
-	gl.Disable(gl.BLEND)
+	ranges := t.drawRanges(drawCount)
+	if len(ranges) == 1 && ranges[0].font == t.Font && ranges[0].page == 0 && ranges[0].color == nil {
+		gl.DrawElements(gl.TRIANGLES, int32(drawCount), gl.UNSIGNED_INT, nil)
+		t.Font.stats.record(1, drawCount/6)
+	} else {
+		// multi-page fonts, font families and colored runs are drawn with one call per face,
+		// page and color
+		for _, r := range ranges {
+			color := textColor
+			if r.color != nil {
+				color = t.Font.outputColor(*r.color, 1-t.transparency)
+			}
+			if state.colorChanged(color) {
+				gl.Uniform4fv(t.Font.colorUniform, 1, &color[0])
+			}
+			if r.font.Init() != nil {
+				continue
+			}
+			if texture := r.font.pageTextureIDs[r.page]; state.textureChanged(texture) {
+				gl.BindTexture(gl.TEXTURE_2D, texture)
+			}
+			gl.DrawElements(gl.TRIANGLES, int32(r.count*6), gl.UNSIGNED_INT, gl.PtrOffset(r.first*4))
+		}
+		t.Font.stats.record(len(ranges), drawCount/6)
+	}
+	t.Font.timer.end()
+	depth.end()
+	t.resetUniforms()
+	if state == nil {
+		gl.BindVertexArray(0)
+		if active == nil {
+			t.Blend.end()
+		} else {
+			active.restore()
+		}
+	}
+}
+
+// drawCount returns the number of ebo values drawn, covering the first RuneCount glyphs
+func (t *Text) drawCount() int {
+	drawCount := t.RuneCount * 6
+	if drawCount > t.eboIndexCount {
+		drawCount = t.eboIndexCount
+	}
+	return drawCount
+}
+
+// drawRange is a part of the ebo drawn with a single call
+type drawRange struct {
+	font  *Font
+	page  int
+	color *mgl32.Vec3
+	first int // index of the first ebo value
+	count int // number of quads
+}
+
+// drawRanges splits the ebo values drawn into the parts sharing a face, page and color
+func (t *Text) drawRanges(drawCount int) []drawRange {
+	if len(t.pageRanges) == 0 {
+		return []drawRange{{font: t.Font, count: drawCount / 6}}
+	}
+	ranges := make([]drawRange, 0, len(t.pageRanges))
+	for _, r := range t.pageRanges {
+		if count := r.drawCount(t.RuneCount); count > 0 {
+			ranges = append(ranges, drawRange{font: r.font, page: r.page, color: r.color, first: r.first, count: count})
+		}
+	}
+	return ranges
+}
+
+// pageRange is the part of the ebo holding the quads of an atlas page of a face
+// drawn with a color
+type pageRange struct {
+	font  *Font
+	page  int
+	color *mgl32.Vec3 // nil for the color of the text
+	first int         // index of the first ebo value
+	quads []int       // indices of the glyphs drawn, in increasing order
+}
+
+// drawCount returns how many of the quads belong to the first runeCount glyphs
+func (r pageRange) drawCount(runeCount int) int {
+	return sort.SearchInts(r.quads, runeCount)
 }
 
 func (t *Text) BeginFadeOut() {
@@ -321,36 +1026,12 @@ func (t *Text) Hide() {
 
 // centerTheData prepares the value "centered_position" found in the font shader
 // as named, the function centers the text around the orthographic center of the screen
-// expected to only be called within SetString
+// expected to only be called within SetString.  The vbo data stays in the coordinates of
+// the layout, lowerLeft being added by the shader, so that a string whose extent changes
+// only sends the vertices that changed.
 func (t *Text) centerTheData(lowerLeft gltext.Point) (err error) {
-	length := len(t.vboData)
-	for index := 0; index < length; {
-		// index (0,0)
-		t.vboData[index] += lowerLeft.X
-		index++
-		t.vboData[index] += lowerLeft.Y
-		index += 3 // skip texture data
-
-		// index (1,0)
-		t.vboData[index] += lowerLeft.X
-		index++
-		t.vboData[index] += lowerLeft.Y
-		index += 3
-
-		// index (1,1)
-		t.vboData[index] += lowerLeft.X
-		index++
-		t.vboData[index] += lowerLeft.Y
-		index += 3
-
-		// index (0,1)
-		t.vboData[index] += lowerLeft.X
-		index++
-		t.vboData[index] += lowerLeft.Y
-		index += 3
-	}
-
 	// update bounding box so that it is centered around (0,0)
+	t.origin = lowerLeft
 	t.X1.X += lowerLeft.X
 	t.X2.X += lowerLeft.X
 	t.X1.Y += lowerLeft.Y
@@ -363,6 +1044,15 @@ func (t *Text) centerTheData(lowerLeft gltext.Point) (err error) {
 	return
 }
 
+// shaderOrigin returns the offset the shader adds to the vertices of the vbo, nothing
+// when they were sent centered for lack of a glyph_origin uniform
+func (t *Text) shaderOrigin() mgl32.Vec2 {
+	if t.Font.originUniform < 0 {
+		return mgl32.Vec2{}
+	}
+	return mgl32.Vec2{t.origin.X, t.origin.Y}
+}
+
 func (t *Text) Width() float32 {
 	return t.X2.X - t.X1.X
 }
@@ -428,107 +1118,258 @@ func (t *Text) HasRune(r rune) bool {
 	return false
 }
 
+// layoutOptions returns the options that the string of the text is laid out with
+func (t *Text) layoutOptions() layout.Options {
+	opts := layout.Options{
+		LineHeight:       t.Font.lineHeight(),
+		ParagraphSpacing: t.ParagraphSpacing,
+		FirstLineIndent:  t.FirstLineIndent,
+		MaxWidth:         t.MaxWidth,
+		HangingIndent:    t.HangingIndent,
+		TabWidth:         t.TabWidth,
+		Align:            t.Align,
+		ShowWhitespace:   t.ShowWhitespace,
+	}
+	if t.Monospace {
+		opts.CellAdvance = t.Font.cellAdvance()
+	}
+	if len(t.styleRuns) > 0 {
+		opts.Face = func(i int) layout.Face {
+			var face layout.Face = t.face(i).Config
+			if size := t.styleRuns.SizeAt(i); size != 1 {
+				face = scaledFace{face, size}
+			}
+			return face
+		}
+	}
+	return opts
+}
+
+// glyphKey holds what the glyphs of a text depend on besides its string
+type glyphKey struct {
+	font   *Font
+	styled bool // faces and sizes of style runs were used
+
+	lineHeight, paragraphSpacing, firstLineIndent  float32
+	maxWidth, hangingIndent, tabWidth, cellAdvance float32
+	align                                          layout.Align
+	showWhitespace                                 bool
+}
+
+func (t *Text) newGlyphKey(opts layout.Options) glyphKey {
+	return glyphKey{
+		font: t.Font, styled: opts.Face != nil,
+		lineHeight: opts.LineHeight, paragraphSpacing: opts.ParagraphSpacing, firstLineIndent: opts.FirstLineIndent,
+		maxWidth: opts.MaxWidth, hangingIndent: opts.HangingIndent, tabWidth: opts.TabWidth, cellAdvance: opts.CellAdvance,
+		align: opts.Align, showWhitespace: opts.ShowWhitespace,
+	}
+}
+
 // makeBufferData positions quads for drawing the text in the indices parameter using glyph dimensions
 // it also generates the bounding box (which needs to later be centered around (0,0))
 // expected to only be called by SetString
 func (t *Text) makeBufferData(indices []rune) {
-	glyphs := t.Font.Config.Glyphs
+	opts := t.layoutOptions()
+	key := t.newGlyphKey(opts)
+	var placed []layout.Glyph
+	if t.unchangedRunes > 0 && t.glyphs != nil && key == t.glyphKey && opts.Face == nil {
+		placed = layout.Relayout(t.Font.Config, indices, t.unchangedRunes, t.glyphs, opts)
+	} else {
+		placed = t.Font.layouts.Layout(t.Font.Config, indices, opts)
+	}
+	t.glyphs, t.glyphKey, t.unchangedRunes = placed, key, 0
+	t.glyphBounds = make([][2]gltext.Point, 0, len(placed))
+
+	// the indices of quads sharing a face, atlas page and color are kept together so that
+	// each of them is drawn at once.  faces and colors are ordered by first use, pages by number.
+	// colors do not split the quads of texts interleaving them in the vbo.
+	type facePage struct {
+		face, page, color int
+	}
+	faces := []*Font{}
+	faceIndex := map[*Font]int{}
+	colors := []*mgl32.Vec3{nil}
+	quadColors := make([]int, len(placed))
+	interleaved := t.interleavesColors()
+	quads := map[facePage]int{}
+	keys := []facePage{}
+	for i, p := range placed {
+		f := t.face(p.Index)
+		if _, ok := faceIndex[f]; !ok {
+			faceIndex[f] = len(faces)
+			faces = append(faces, f)
+		}
+		if c := t.styleRuns.ColorAt(p.Index); c != nil && !interleaved {
+			v := colorVec3(c)
+			quadColors[i] = len(colors)
+			for j, existing := range colors[1:] {
+				if *existing == v {
+					quadColors[i] = j + 1
+				}
+			}
+			if quadColors[i] == len(colors) {
+				colors = append(colors, &v)
+			}
+		}
+		key := facePage{faceIndex[f], f.Config.Glyphs[p.Glyph].Page, quadColors[i]}
+		if quads[key] == 0 {
+			keys = append(keys, key)
+		}
+		quads[key]++
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].face != keys[j].face {
+			return keys[i].face < keys[j].face
+		}
+		if keys[i].page != keys[j].page {
+			return keys[i].page < keys[j].page
+		}
+		return keys[i].color < keys[j].color
+	})
+	pageEboIndex := map[facePage]int{}
+	pageRangeIndex := map[facePage]int{}
+	t.pageRanges = t.pageRanges[:0]
+	at := 0
+	for _, key := range keys {
+		pageEboIndex[key], pageRangeIndex[key] = at, len(t.pageRanges)
+		t.pageRanges = append(t.pageRanges, pageRange{font: faces[key.face], page: key.page, color: colors[key.color], first: at})
+		at += quads[key] * 6
+	}
 
 	vboIndex := 0
-	eboIndex := 0
-	lineX := float32(0)
 	eboOffset := int32(0)
+	lineHeight := t.Font.lineHeight()
 
 	t.CharSpacing = make([]float32, 0)
-	for i, r := range indices {
-		glyphIndex := t.Font.Config.RuneRanges.GetGlyphIndex(r)
-		if glyphIndex >= 0 {
-			if gltext.IsDebug {
-				prefix := gltext.DebugPrefix()
-				fmt.Printf("%s png index %3d: %s rune %+v line at %f", prefix, glyphIndex, string(r), glyphs[glyphIndex], lineX)
+	for i, p := range placed {
+		f := t.face(p.Index)
+		glyphs := f.Config.Glyphs
+		glyphIndex := p.Glyph
+		lineX, lineY := p.X, p.Y
+		page := facePage{faceIndex[f], glyphs[glyphIndex].Page, quadColors[i]}
+		size := t.styleRuns.SizeAt(p.Index)
+		if gltext.IsDebug {
+			prefix := gltext.DebugPrefix()
+			fmt.Printf("%s png index %3d: %s rune %+v line at %f", prefix, glyphIndex, string(p.Rune), glyphs[glyphIndex], lineX)
+		}
+		advance := float32(glyphs[glyphIndex].Advance) * size
+
+		// Originally the glyph Width was used, but that results in quads that overlap one another.
+		vw := float32(glyphs[glyphIndex].Advance) * size
+		vh := float32(glyphs[glyphIndex].Height) * size
+		if opts.CellAdvance > 0 {
+			cell := opts.CellAdvance * size
+			lineX += (cell - vw) / 2
+			advance = cell
+		} else if opts.ShowWhitespace && p.Rune == ' ' {
+			// the symbol is centered over the advance of the space it replaces
+			if _, a, ok := f.Config.Glyph(' '); ok {
+				lineX += (a*size - vw) / 2
+				advance = a * size
 			}
-			advance := float32(glyphs[glyphIndex].Advance)
+		}
 
-			// Originally the glyph Width was used, but that results in quads that overlap one another.
-			vw := float32(glyphs[glyphIndex].Advance)
-			vh := float32(glyphs[glyphIndex].Height)
+		// used to determine which character inside of the text was clicked
+		t.CharSpacing = append(t.CharSpacing, advance)
 
-			// used to determine which character inside of the text was clicked
-			t.CharSpacing = append(t.CharSpacing, advance)
+		// variable width characters will produce a bounding box that is just
+		// a bit too long on the right-hand side unless we trim off the excess
+		// when processing the right-most character
+		trim := float32(0)
+		if i == len(placed)-1 {
+			// the box ends at the advance, or the cell, of the glyph
+			trim = lineX + vw - (p.X + advance)
+		}
+		tP1, tP2 := glyphs[glyphIndex].GetTexturePositions(f)
 
-			// variable width characters will produce a bounding box that is just
-			// a bit too long on the right-hand side unless we trim off the excess
-			// when processing the right-most character
-			trim := float32(0)
-			if i == len(indices)-1 {
-				trim = vw - advance
-			}
-			tP1, tP2 := glyphs[glyphIndex].GetTexturePositions(t.Font)
-
-			// counter-clockwise quad
-			// the bounding box value X2 is being expanded as characters are added
-
-			// index (0,0)
-			t.vboData[vboIndex] = lineX // position
-			vboIndex++
-			t.vboData[vboIndex] = 0
-			vboIndex++
-			t.vboData[vboIndex] = tP1.X // texture uv
-			vboIndex++
-			t.vboData[vboIndex] = tP2.Y
-			vboIndex++
-
-			// index (1,0) - expanding X2
-			t.vboData[vboIndex], t.X2.X = lineX+vw, lineX+vw-trim
-			vboIndex++
-			t.vboData[vboIndex] = 0
-			vboIndex++
-			t.vboData[vboIndex] = tP2.X
-			vboIndex++
-			t.vboData[vboIndex] = tP2.Y
-			vboIndex++
-
-			// index (1,1) - expanding X2
-			t.vboData[vboIndex] = lineX + vw
-			vboIndex++
-			t.vboData[vboIndex], t.X2.Y = vh, vh
-			vboIndex++
-			t.vboData[vboIndex] = tP2.X
-			vboIndex++
-			t.vboData[vboIndex] = tP1.Y
-			vboIndex++
-
-			// index (0,1)
-			t.vboData[vboIndex] = lineX
-			vboIndex++
-			t.vboData[vboIndex] = vh
-			vboIndex++
-			t.vboData[vboIndex] = tP1.X
-			vboIndex++
-			t.vboData[vboIndex] = tP1.Y
-			vboIndex++
-
-			// ebo data
-			t.eboData[eboIndex] = 0 + eboOffset
-			eboIndex++
-			t.eboData[eboIndex] = 1 + eboOffset
-			eboIndex++
-			t.eboData[eboIndex] = 2 + eboOffset
-			eboIndex++
-
-			t.eboData[eboIndex] = 0 + eboOffset
-			eboIndex++
-			t.eboData[eboIndex] = 2 + eboOffset
-			eboIndex++
-			t.eboData[eboIndex] = 3 + eboOffset
-			eboIndex++
-			eboOffset += 4
-
-			// shift to the right
-			lineX += advance
-			if gltext.IsDebug {
-				fmt.Printf("-> %f\n", lineX)
-			}
+		// counter-clockwise quad
+		// the bounding box values X1 and X2 are being expanded as characters and lines are added
+		if lineX+vw-trim > t.X2.X {
+			t.X2.X = lineX + vw - trim
+		}
+		top := lineY + lineHeight
+		if size > 1 {
+			// enlarged glyphs rise above the line
+			top = lineY + lineHeight*size
+		}
+		if top > t.X2.Y {
+			t.X2.Y = top
+		}
+		if lineY < t.X1.Y {
+			t.X1.Y = lineY
+		}
+
+		// glyphs trimmed to their ink are raised from the bottom of the line
+		lineY += float32(glyphs[glyphIndex].OffsetY) * size
+
+		// the ink is drawn from the left of the quad
+		ink := vw
+		if width := float32(glyphs[glyphIndex].Width) * size; width < ink {
+			ink = width
+		}
+		t.glyphBounds = append(t.glyphBounds, [2]gltext.Point{{X: lineX, Y: lineY}, {X: lineX + ink, Y: lineY + vh}})
+
+		// index (0,0)
+		t.vboData[vboIndex] = lineX // position
+		vboIndex++
+		t.vboData[vboIndex] = lineY
+		vboIndex++
+		t.vboData[vboIndex] = tP1.X // texture uv
+		vboIndex++
+		t.vboData[vboIndex] = tP2.Y
+		vboIndex++
+
+		// index (1,0)
+		t.vboData[vboIndex] = lineX + vw
+		vboIndex++
+		t.vboData[vboIndex] = lineY
+		vboIndex++
+		t.vboData[vboIndex] = tP2.X
+		vboIndex++
+		t.vboData[vboIndex] = tP2.Y
+		vboIndex++
+
+		// index (1,1)
+		t.vboData[vboIndex] = lineX + vw
+		vboIndex++
+		t.vboData[vboIndex] = lineY + vh
+		vboIndex++
+		t.vboData[vboIndex] = tP2.X
+		vboIndex++
+		t.vboData[vboIndex] = tP1.Y
+		vboIndex++
+
+		// index (0,1)
+		t.vboData[vboIndex] = lineX
+		vboIndex++
+		t.vboData[vboIndex] = lineY + vh
+		vboIndex++
+		t.vboData[vboIndex] = tP1.X
+		vboIndex++
+		t.vboData[vboIndex] = tP1.Y
+		vboIndex++
+
+		// ebo data
+		eboIndex := pageEboIndex[page]
+		pageEboIndex[page] += 6
+		t.pageRanges[pageRangeIndex[page]].quads = append(t.pageRanges[pageRangeIndex[page]].quads, i)
+
+		t.eboData[eboIndex] = 0 + eboOffset
+		eboIndex++
+		t.eboData[eboIndex] = 1 + eboOffset
+		eboIndex++
+		t.eboData[eboIndex] = 2 + eboOffset
+		eboIndex++
+
+		t.eboData[eboIndex] = 0 + eboOffset
+		eboIndex++
+		t.eboData[eboIndex] = 2 + eboOffset
+		eboIndex++
+		t.eboData[eboIndex] = 3 + eboOffset
+		eboOffset += 4
+
+		if gltext.IsDebug {
+			fmt.Printf("-> %f\n", lineX+advance)
 		}
 	}
 	if gltext.IsDebug {
@@ -5,8 +5,18 @@
 package v46
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"github.com/mikzorz/gltext"
+	"github.com/mikzorz/gltext/layout"
+	"github.com/go-gl/gl/v4.6-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"image"
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -77,3 +87,1924 @@ func TestBoundingBox(t *testing.T) {
 		t.Error(x2)
 	}
 }
+
+func TestAnchor(t *testing.T) {
+	text := &Text{}
+	text.X1 = gltext.Point{X: -10, Y: -5}
+	text.X2 = gltext.Point{X: +10, Y: +5}
+	text.Font = &Font{}
+
+	text.SetPosition(mgl32.Vec2{100, 50})
+	text.SetAnchor(AnchorTopLeft)
+	x1, x2 := text.GetBoundingBox()
+	if x1.X != 100 || x2.Y != 50 {
+		t.Error(x1, x2)
+	}
+
+	text.SetAnchor(AnchorBottomRight)
+	x1, x2 = text.GetBoundingBox()
+	if x2.X != 100 || x1.Y != 50 {
+		t.Error(x1, x2)
+	}
+}
+
+func TestPivot(t *testing.T) {
+	text := &Text{}
+	text.X1 = gltext.Point{X: -10, Y: -5}
+	text.X2 = gltext.Point{X: +10, Y: +5}
+	text.Font = &Font{}
+	text.Font.ResizeWindow(100, 100)
+	text.ScaleMin, text.ScaleMax = 1, 2
+	text.SetScale(2)
+
+	// the left edge stays in place while the text grows to the right
+	text.SetPivot(0, 0.5)
+	m := text.pivotedScale(text.Font.OrthographicMatrix)
+	left := m.Mul4x1(text.Font.OrthographicMatrix.Mul4x1(mgl32.Vec4{-10, 0, 0, 1}))
+	if !mgl32.FloatEqualThreshold(left.X(), -0.2, 1e-5) {
+		t.Error("Left edge moved", left)
+	}
+	right := m.Mul4x1(text.Font.OrthographicMatrix.Mul4x1(mgl32.Vec4{10, 0, 0, 1}))
+	if right.X() <= 0.2 {
+		t.Error("Right edge did not grow", right)
+	}
+}
+
+func TestAnimate(t *testing.T) {
+	text := &Text{}
+	text.Font = &Font{}
+	text.Font.ResizeWindow(100, 100)
+	text.ScaleMin, text.ScaleMax = 1, 2
+	text.SetScale(1)
+
+	text.AnimateScale(3, 1, gltext.EaseLinear)
+	text.AnimatePosition(mgl32.Vec2{10, 20}, 2, nil)
+	text.Update(0.5)
+	if text.Scale != 1.5 {
+		t.Error("Expecting half way", text.Scale)
+	}
+	text.Update(0.5)
+	if text.Scale != 2 || text.Position.X() != 5 {
+		t.Error("Expecting scale bound and half way position", text.Scale, text.Position)
+	}
+	text.Update(5)
+	if text.IsAnimating() || text.Position.Y() != 20 {
+		t.Error("Expecting completed animation", text.Position)
+	}
+}
+
+func TestSetUniform(t *testing.T) {
+	text := &Text{}
+	if err := text.SetUniform("time", float32(1)); err != nil {
+		t.Error(err)
+	}
+	if err := text.SetUniform("seed", 3); err != nil || text.uniforms["seed"] != int32(3) {
+		t.Error("Expecting int32 conversion", err)
+	}
+	if err := text.SetUniform("name", "value"); err == nil {
+		t.Error("Expecting unsupported type")
+	}
+	text.SetUniform("time", nil)
+	if _, ok := text.uniforms["time"]; ok {
+		t.Error("Expecting removal")
+	}
+}
+
+func TestSDFUniforms(t *testing.T) {
+	text := &Text{}
+	text.Font = &Font{}
+	text.Font.Config = &gltext.FontConfig{SDF: true, SDFSpread: 4}
+	text.SetWeight(1)
+	text.SetSoftness(2)
+	threshold, softness := text.sdfUniforms()
+	if threshold != 0.375 || softness != 0.25 {
+		t.Error(threshold, softness)
+	}
+}
+
+func TestPageRanges(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 1, Page: 1}, {Advance: 1}, {Advance: 1, Page: 1}}
+
+	text := &Text{}
+	text.Font = f
+	indices := []rune("abca")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+
+	if len(text.pageRanges) != 2 {
+		t.Fatal("Expecting 2 pages", text.pageRanges)
+	}
+	p0, p1 := text.pageRanges[0], text.pageRanges[1]
+	if p0.page != 0 || p0.first != 0 || len(p0.quads) != 1 || p0.quads[0] != 1 {
+		t.Error("Bad first page", p0)
+	}
+	if p1.page != 1 || p1.first != 6 || len(p1.quads) != 3 {
+		t.Error("Bad second page", p1)
+	}
+	// quad "b" is the second quad in the vbo
+	if text.eboData[0] != 4 {
+		t.Error("Bad indices", text.eboData)
+	}
+	if p1.drawCount(3) != 2 {
+		t.Error("Expecting 2 quads to be drawn", p1.drawCount(3))
+	}
+}
+
+func TestFontFamily(t *testing.T) {
+	face := func(advance int) *Font {
+		f := &Font{}
+		f.Config = &gltext.FontConfig{}
+		f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+		f.Config.Glyphs = gltext.Charset{{Advance: advance}, {Advance: advance}, {Advance: advance}}
+		return f
+	}
+	regular, bold := face(1), face(2)
+	if _, err := NewFontFamily(nil, bold, nil, nil); err == nil {
+		t.Error("Expecting a regular face to be required")
+	}
+	ff, err := NewFontFamily(regular, bold, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ff.Face(gltext.StyleItalic) != regular || ff.Face(gltext.StyleBoldItalic) != bold {
+		t.Error("Bad fallback faces")
+	}
+
+	text := &Text{}
+	text.Font = regular
+	text.Family = ff
+	text.styleRuns = gltext.StyleRuns{{Start: 1, End: 2, Style: gltext.StyleBold}}
+	indices := []rune("abc")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+
+	if len(text.pageRanges) != 2 || text.pageRanges[0].font != regular || text.pageRanges[1].font != bold {
+		t.Fatal("Expecting a range per face", text.pageRanges)
+	}
+	if len(text.pageRanges[0].quads) != 2 || text.pageRanges[1].quads[0] != 1 {
+		t.Error("Bad quads", text.pageRanges)
+	}
+	// the bold glyph is wider
+	if text.glyphs[2].X != 3 {
+		t.Error("Bad layout", text.glyphs)
+	}
+}
+
+func TestMesh(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2, Page: 1}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.pageTextureIDs = []uint32{7, 8}
+	f.textureID = 7
+	f.textureWidth, f.textureHeight = 8, 8
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 2, Position: mgl32.Vec2{10, 0}}
+	text.SetPivot(0.5, 0.5)
+	indices := []rune("abc")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+	text.RuneCount = 1
+
+	m := text.Mesh()
+	if len(m.Positions) != 24 || len(m.UVs) != 24 || len(m.Indices) != 18 {
+		t.Fatal("Bad mesh sizes", len(m.Positions), len(m.UVs), len(m.Indices))
+	}
+	// the text is 6 pixels wide, doubled in size and centered at x = 10
+	if m.Positions[0] != 4 || m.Positions[1] != -2 {
+		t.Error("Bad first vertex", m.Positions[:2])
+	}
+	// only the first glyph, on page 1, is counted
+	if len(m.Batches) != 1 || m.Batches[0].TextureID != 8 || m.Batches[0].Count != 6 || m.TextureID != 8 {
+		t.Error("Bad batches", m.Batches)
+	}
+}
+
+func TestDebugOverlay(t *testing.T) {
+	o := &DebugOverlay{Frames: 2}
+	o.Update(1)
+	o.Update(0.5)
+	o.Update(0.5)
+	if fps, ms := o.FPS(); fps != 2 || ms != 500 {
+		t.Error("Bad frame rate", fps, ms)
+	}
+	count := 0
+	o.Watch("count", func() interface{} { return count })
+	o.Watch("name", func() interface{} { return "gltext" })
+	count = 3
+	lines := o.Lines()
+	if len(lines) != 3 || lines[0] != "fps 2.0  500.00 ms" || lines[1] != "count: 3" || lines[2] != "name: gltext" {
+		t.Error("Bad lines", lines)
+	}
+	o.Unwatch("count")
+	if lines = o.Lines(); len(lines) != 2 || lines[1] != "name: gltext" {
+		t.Error("Bad lines", lines)
+	}
+}
+
+func TestLabelInset(t *testing.T) {
+	f := &Font{}
+	f.WindowWidth, f.WindowHeight = 100, 100
+	l := &Label{Text: &Text{Font: f}, Padding: 4, BorderWidth: 1}
+	l.X1, l.X2 = gltext.Point{X: -10, Y: -5}, gltext.Point{X: 10, Y: 5}
+
+	l.Text.anchor = AnchorTopLeft
+	l.SetPosition(mgl32.Vec2{-50, 50})
+	X1, X2 := l.GetBoundingBox()
+	if X1.X != -50 || X2.Y != 50 || X2.X != -20 || X1.Y != 30 {
+		t.Error("Bad top left box", X1, X2)
+	}
+	l.Text.anchor = AnchorCenter
+	l.SetPosition(mgl32.Vec2{0, 0})
+	if X1, X2 = l.GetBoundingBox(); X1.X != -15 || X2.Y != 10 {
+		t.Error("Bad centered box", X1, X2)
+	}
+	if l.GetPosition() != (mgl32.Vec2{0, 0}) {
+		t.Error("Bad position", l.GetPosition())
+	}
+}
+
+func TestTextButton(t *testing.T) {
+	f := &Font{}
+	f.WindowWidth, f.WindowHeight = 100, 100
+	text := &Text{Font: f, ScaleMin: 1, ScaleMax: 2}
+	text.SetScale(1)
+	text.SetPivot(0.5, 0.5)
+	text.X1, text.X2 = gltext.Point{X: -10, Y: -5}, gltext.Point{X: 10, Y: 5}
+	clicks := 0
+	b := &TextButton{Text: text, OnClick: func() { clicks++ }, HoverDuration: 1}
+	b.Colors[ButtonHover] = mgl32.Vec3{1, 0, 0}
+
+	if cursor := f.CursorPosition(55, 50); cursor != (mgl32.Vec2{5, 0}) {
+		t.Fatal("Bad cursor position", cursor)
+	}
+	inside, outside := mgl32.Vec2{5, 0}, mgl32.Vec2{15, 0}
+
+	b.Update(inside, false, 0)
+	if b.State() != ButtonHover || b.color != b.Colors[ButtonHover] {
+		t.Error("Expecting hover", b.State())
+	}
+	// the scaled button covers the point once fully grown
+	b.Update(outside, false, 0)
+	b.Update(inside, false, 1)
+	if b.Scale != 2 || !b.Contains(outside) {
+		t.Error("Expecting the button to grow", b.Scale)
+	}
+	b.Update(inside, true, 0)
+	b.Update(inside, false, 0)
+	if clicks != 1 || b.State() != ButtonHover {
+		t.Error("Expecting a click", clicks, b.State())
+	}
+	// a press that starts outside of the button is not a click
+	b.Update(mgl32.Vec2{50, 50}, true, 0)
+	b.Update(inside, true, 0)
+	b.Update(inside, false, 0)
+	if clicks != 1 {
+		t.Error("Unexpected click", clicks)
+	}
+	b.SetDisabled(true)
+	b.Update(inside, true, 0)
+	b.Update(inside, false, 0)
+	if clicks != 1 || b.State() != ButtonDisabled {
+		t.Error("Disabled buttons are not clicked", clicks, b.State())
+	}
+}
+
+func TestTooltip(t *testing.T) {
+	f := &Font{}
+	f.WindowWidth, f.WindowHeight = 200, 200
+	target := &Text{Font: f, ScaleMin: 1, ScaleMax: 1}
+	target.SetScale(1)
+	target.SetPivot(0.5, 0.5)
+	target.X1, target.X2 = gltext.Point{X: -10, Y: -5}, gltext.Point{X: 10, Y: 5}
+
+	text := &Text{Font: f}
+	text.X1, text.X2 = gltext.Point{X: -20, Y: -10}, gltext.Point{X: 20, Y: 10}
+	tt := &Tooltip{Label: &Label{Text: text}, Target: target, Delay: 0.5, Offset: 10}
+
+	tt.Update(mgl32.Vec2{0, 0}, 0.25)
+	if tt.Visible() {
+		t.Error("Expecting the tooltip to wait")
+	}
+	tt.Update(mgl32.Vec2{0, 0}, 0.25)
+	X1, X2 := tt.GetBoundingBox()
+	if !tt.Visible() || X1.X != 10 || X2.Y != -10 {
+		t.Error("Expecting the tooltip below the cursor", X1, X2)
+	}
+	tt.Update(mgl32.Vec2{50, 50}, 1)
+	if tt.Visible() {
+		t.Error("Expecting the tooltip to hide")
+	}
+
+	anchor, position := placeTooltip(mgl32.Vec2{40, 20}, mgl32.Vec2{200, 200}, mgl32.Vec2{90, -90}, mgl32.Vec2{90, -70}, 70)
+	if anchor != AnchorBottomRight || position != (mgl32.Vec2{70, -70}) {
+		t.Error("Expecting the tooltip to flip", anchor, position)
+	}
+}
+
+// testItem is a stack item of a fixed size
+type testItem struct {
+	size     mgl32.Vec2
+	position mgl32.Vec2
+	anchor   Anchor
+}
+
+func (i *testItem) GetBoundingBox() (X1, X2 gltext.Point) {
+	return gltext.Point{X: i.position.X(), Y: i.position.Y() - i.size.Y()}, gltext.Point{X: i.position.X() + i.size.X(), Y: i.position.Y()}
+}
+func (i *testItem) SetAnchor(a Anchor)       { i.anchor = a }
+func (i *testItem) SetPosition(v mgl32.Vec2) { i.position = v }
+func (i *testItem) Draw()                    {}
+
+func TestStack(t *testing.T) {
+	a, b := &testItem{size: mgl32.Vec2{10, 5}}, &testItem{size: mgl32.Vec2{20, 10}}
+	s := NewVStack(2, a, b)
+	s.Align = StackAlignCenter
+	s.SetPosition(mgl32.Vec2{0, 0})
+	if s.Size() != (mgl32.Vec2{20, 17}) {
+		t.Fatal("Bad size", s.Size())
+	}
+	if a.position != (mgl32.Vec2{5, 0}) || b.position != (mgl32.Vec2{0, -7}) || a.anchor != AnchorTopLeft {
+		t.Error("Bad vertical layout", a.position, b.position)
+	}
+
+	// items are laid out again when they grow
+	a.size = mgl32.Vec2{10, 8}
+	s.Draw()
+	if b.position != (mgl32.Vec2{0, -10}) {
+		t.Error("Expecting the stack to follow its items", b.position)
+	}
+
+	h := NewHStack(4, a, b)
+	h.SetAnchor(AnchorCenter)
+	if h.Size() != (mgl32.Vec2{34, 10}) || a.position != (mgl32.Vec2{-17, 5}) || b.position != (mgl32.Vec2{-3, 5}) {
+		t.Error("Bad horizontal layout", h.Size(), a.position, b.position)
+	}
+}
+
+func TestClone(t *testing.T) {
+	text := &Text{vao: 1, vbo: 2, ebo: 3, String: "ab", RuneCount: 2}
+	text.vboData = []float32{1, 2, 3}
+	text.pageRanges = []pageRange{{page: 1, quads: []int{0, 1}}}
+	text.uniforms = map[string]interface{}{"u": float32(1)}
+
+	c := &Text{vao: 4, vbo: 5, ebo: 6}
+	text.copyTo(c)
+	if c.vao != 4 || c.vbo != 5 || c.ebo != 6 {
+		t.Fatal("Expecting the clone to keep its own buffers")
+	}
+	if c.String != "ab" || c.RuneCount != 2 || c.vboData[2] != 3 || c.pageRanges[0].page != 1 {
+		t.Fatal("Expecting the layout to be copied", c.String, c.vboData, c.pageRanges)
+	}
+	c.vboData[0] = 9
+	c.pageRanges[0].quads[0] = 9
+	c.uniforms["u"] = float32(2)
+	if text.vboData[0] != 1 || text.pageRanges[0].quads[0] != 0 || text.uniforms["u"] != float32(1) {
+		t.Error("Expecting the clone not to share data with the original")
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	if n := commonVBOPrefix([]float32{1, 2, 3}, []float32{1, 2, 4, 5}); n != 2 {
+		t.Error("Expecting a prefix of 2 values", n)
+	}
+	if n := commonVBOPrefix(nil, []float32{1}); n != 0 {
+		t.Error("Expecting no prefix", n)
+	}
+	if n := commonEBOPrefix([]int32{0, 1, 2}, []int32{0, 1, 2, 0}); n != 3 {
+		t.Error("Expecting a prefix of 3 indices", n)
+	}
+}
+
+func TestUploadStart(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {X: 4, Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	// the last rune changes while the string grows, moving its center
+	text := &Text{Font: f, Scale: 1}
+	text.layOut([]rune("ab"))
+	previous, origin := text.vboData, text.origin
+	text.layOut([]rune("acc"))
+	if text.origin == origin {
+		t.Fatal("Expecting the origin to move with the extent of the text")
+	}
+	if start := text.vboUploadStart(previous); start != 16 {
+		t.Error("Expecting the vertices following the first quad to be sent", start)
+	}
+	text.vertexFormat = VFHalfFloatUNormUV
+	if start := text.vboUploadStart(previous); start != 16 {
+		t.Error("Expecting packed vertices to be sent from the same vertex", start)
+	}
+	if start := text.vboUploadStart(text.vboData); start != len(text.vboData) {
+		t.Error("Expecting nothing to be sent", start)
+	}
+
+	// programs without glyph_origin receive centered vertices
+	f.originUniform = -1
+	text.vertexOrigin = origin
+	if start := text.vboUploadStart(previous); start != 0 {
+		t.Error("Expecting every vertex to be sent around the new origin", start)
+	}
+	text.vertexOrigin = text.origin
+	if c := text.interleave(text.vboData, 0); c[0] != text.vboData[0]+text.origin.X || c[1] != text.vboData[1]+text.origin.Y || &c[0] == &text.vboData[0] {
+		t.Error("Expecting the vertices to be centered when sent", c[:4])
+	}
+}
+
+func TestRelayoutTail(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1, MaxWidth: 6}
+	text.layOut([]rune("ab\nab ca"))
+	text.glyphs = append([]layout.Glyph{}, text.glyphs...)
+	text.glyphs[0].X = 1 // marks the glyphs that are kept
+
+	text.unchangedRunes = 8
+	text.layOut([]rune("ab\nab cabc"))
+	fresh := &Text{Font: f, Scale: 1, MaxWidth: 6}
+	fresh.layOut([]rune("ab\nab cabc"))
+	if text.glyphs[0].X != 1 || !reflect.DeepEqual(text.glyphs[1:], fresh.glyphs[1:]) {
+		t.Error("Expecting the lines following the first to be laid out again", text.glyphs, fresh.glyphs)
+	}
+	if text.unchangedRunes != 0 {
+		t.Error("Expecting the unchanged runes to be used once", text.unchangedRunes)
+	}
+
+	// other options lay out the whole string
+	text.glyphs[0].X = 1
+	text.unchangedRunes = 8
+	text.MaxWidth = 0
+	text.layOut([]rune("ab\nab cabc"))
+	if text.glyphs[0].X != 0 {
+		t.Error("Expecting the whole string to be laid out", text.glyphs[0])
+	}
+}
+
+func TestReplaceRunes(t *testing.T) {
+	cases := []struct {
+		start, end int
+		s, expect  string
+	}{
+		{6, 7, "Ü", "héllo Üörld"},
+		{0, 0, ">", ">héllo wörld"},
+		{11, 20, "!", "héllo wörld!"},
+		{-1, 5, "bye", "bye wörld"},
+		{8, 3, "-", "hél-lo wörld"},
+	}
+	for _, c := range cases {
+		if s := replaceRunes("héllo wörld", c.start, c.end, c.s); s != c.expect {
+			t.Error("Expecting", c.expect, "got", s)
+		}
+	}
+}
+
+func TestMarkupRuns(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	m := gltext.ParseBBCode("a[color=#ff0000]b[/color][size=2][url=x]c[/url][/size]")
+	text := &Text{Font: f, Scale: 1}
+	text.styleRuns, text.links = m.Runs, m.Links
+	indices := []rune(m.Text)
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	if len(text.pageRanges) != 2 || text.pageRanges[0].color != nil || *text.pageRanges[1].color != (mgl32.Vec3{1, 0, 0}) {
+		t.Fatal("Expecting a range per color", text.pageRanges)
+	}
+	if len(text.pageRanges[0].quads) != 2 || text.pageRanges[1].quads[0] != 1 {
+		t.Error("Bad quads", text.pageRanges)
+	}
+	if text.Width() != 8 || text.Height() != 4 {
+		t.Error("Expecting the enlarged glyph to widen the text", text.Width(), text.Height())
+	}
+	if link, ok := text.LinkAt(mgl32.Vec2{3, 0}); !ok || link.URL != "x" {
+		t.Error("Expecting the last glyph to be a link", link)
+	}
+	if _, ok := text.LinkAt(mgl32.Vec2{-3, -1}); ok {
+		t.Error("Expecting no link on the first glyph")
+	}
+}
+
+func TestTerminalGrid(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'b'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 3}, {Advance: 2, Height: 3, Page: 1}}
+	f.textureWidth, f.textureHeight = 8, 8
+	f.maxGlyphHeight = 4
+
+	g := newTerminalGrid(f, 2, 3)
+	if g.CellWidth != 2 || g.CellHeight != 4 || len(g.eboData) != 2*6*6 {
+		t.Fatal("Bad grid", g.CellWidth, g.CellHeight, len(g.eboData))
+	}
+	if first, last, changed := g.updateVertices(); !changed || first != 0 || last != 5 {
+		t.Error("Expecting every cell to be written first", first, last)
+	}
+	if _, _, changed := g.updateVertices(); changed {
+		t.Error("Expecting no change")
+	}
+
+	red := mgl32.Vec4{1, 0, 0, 1}
+	g.Set(1, 1, Cell{Rune: 'a', Foreground: red})
+	g.Set(0, 2, Cell{Rune: 'b'})
+	g.Set(9, 9, Cell{Rune: 'a'})
+	first, last, changed := g.updateVertices()
+	if !changed || first != 2 || last != 4 {
+		t.Error("Bad dirty range", first, last)
+	}
+	glyph := g.vboData[(6+4)*gridQuadSize:]
+	if glyph[0] != 2 || glyph[1] != -8 || glyph[16] != 4 || glyph[17] != -5 || glyph[4] != 1 {
+		t.Error("Bad glyph quad", glyph[:gridQuadSize])
+	}
+	if other := g.vboData[(6+2)*gridQuadSize:]; other[16] != 0 {
+		t.Error("Expecting glyphs of other pages to be skipped")
+	}
+	if background := g.vboData[4*gridQuadSize:]; background[0] != 2 || background[1] != -8 || background[2] != -1 {
+		t.Error("Bad background quad", background[:8])
+	}
+	if col := g.Print(0, 1, "abc", red, red); col != 3 || g.Get(0, 2).Rune != 'b' {
+		t.Error("Expecting printing to stop at the end of the row", col)
+	}
+}
+
+func TestMonospace(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'b'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 4, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1, Monospace: true}
+	indices := []rune("aba")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+
+	// narrow glyphs are centered in their cell
+	if text.vboData[0] != 1 || text.vboData[16] != 4 || text.vboData[32] != 9 {
+		t.Error("Bad cells", text.vboData[0], text.vboData[16], text.vboData[32])
+	}
+	if text.X2.X != 12 || text.CharSpacing[0] != 4 {
+		t.Error("Expecting the text to be 3 cells wide", text.X2.X, text.CharSpacing)
+	}
+}
+
+func TestListText(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: ' ', High: 'z'}}
+	for r := ' '; r <= 'z'; r++ {
+		f.Config.Glyphs = append(f.Config.Glyphs, gltext.Glyph{Advance: 2, Height: 2})
+	}
+	f.maxGlyphHeight = 2
+
+	list := &ListText{Text: &Text{Font: f, Scale: 1}, Style: LSNumbered}
+	if s := list.itemsString([]string{"ab", "c\nd"}); s != "1.\tab\n2.\tc d" {
+		t.Error("Bad numbered items", s)
+	}
+	// the gutter fits the widest marker followed by a space
+	if list.HangingIndent != 6 || list.TabWidth != 6 {
+		t.Error("Bad gutter", list.HangingIndent, list.TabWidth)
+	}
+
+	// the font has no bullet
+	list.Style = LSBullet
+	if s := list.itemsString([]string{"ab"}); s != "-\tab" {
+		t.Error("Expecting a dash for fonts without a bullet", s)
+	}
+	list.Bullet = '*'
+	if s := list.itemsString([]string{"ab"}); s != "*\tab" {
+		t.Error("Bad bullet", s)
+	}
+
+	// wrapped lines line up with the text of their item
+	list.Gutter, list.MaxWidth = 8, 14
+	list.layOut([]rune(list.itemsString([]string{"aa bb"})))
+	starts := map[int]layout.Glyph{}
+	for _, g := range list.glyphs {
+		if g.Index == 2 || g.Index == 5 {
+			starts[g.Index] = g
+		}
+	}
+	if g := starts[2]; g.Rune != 'a' || g.Line != 0 || g.X != 8 {
+		t.Error("Expecting the item to begin at the gutter", g)
+	}
+	if g := starts[5]; g.Rune != 'b' || g.Line != 1 || g.X != 8 {
+		t.Error("Expecting the continuation line to be indented by the gutter", g)
+	}
+
+	// a marker wider than the gutter widens it for the first line and the following ones
+	list.Bullet, list.Gutter = 0, 2
+	list.Style = LSNumbered
+	items := make([]string, 10)
+	items[9] = "aa bb"
+	list.layOut([]rune(list.itemsString(items)))
+	starts = map[int]layout.Glyph{}
+	for _, g := range list.glyphs {
+		if g.Rune == 'a' || g.Rune == 'b' {
+			if _, ok := starts[int(g.Rune)]; !ok {
+				starts[int(g.Rune)] = g
+			}
+		}
+	}
+	if list.HangingIndent != 6 || list.TabWidth != 6 {
+		t.Error("Expecting the gutter to fit the widest marker", list.HangingIndent, list.TabWidth)
+	}
+	if a, b := starts['a'], starts['b']; a.X != 6 || b.X != 6 || b.Line != a.Line+1 {
+		t.Error("Expecting the item text and its continuation line to line up", a, b)
+	}
+}
+
+func TestLines(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1}
+	indices := []rune("ab\nc")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	lines := text.Lines()
+	if len(lines) != 2 {
+		t.Fatal("Expecting 2 lines", lines)
+	}
+	first, second := lines[0], lines[1]
+	if first.Start != 0 || first.End != 2 || first.Width != 4 || first.Baseline != -2 || first.X1 != (gltext.Point{X: -2, Y: -2}) || first.X2 != (gltext.Point{X: 2, Y: 0}) {
+		t.Error("Bad first line", first)
+	}
+	if second.Line != 1 || second.Start != 3 || second.End != 4 || second.Width != 2 || second.Baseline != -4 {
+		t.Error("Bad second line", second)
+	}
+}
+
+func TestGlyphBounds(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'b'}}
+	f.Config.Glyphs = gltext.Charset{{Width: 2, Advance: 4, Height: 2}, {Width: 6, Advance: 4, Height: 1, OffsetY: 1}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1}
+	text.SetPivot(0.5, 0.5)
+	indices := []rune("ab")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	X1, X2, ok := text.GlyphBounds(0)
+	if !ok || X1 != (gltext.Point{X: -4, Y: -1}) || X2 != (gltext.Point{X: -2, Y: 1}) {
+		t.Error("Bad ink bounds", X1, X2)
+	}
+	// wide ink is limited to the quad
+	if X1, X2, _ := text.GlyphBounds(1); X1 != (gltext.Point{X: 0, Y: 0}) || X2 != (gltext.Point{X: 4, Y: 1}) {
+		t.Error("Bad ink bounds", X1, X2)
+	}
+	if _, _, ok := text.GlyphBounds(2); ok {
+		t.Error("Expecting no glyph")
+	}
+	if i, ok := text.GlyphAt(mgl32.Vec2{1, 0.5}); !ok || i != 1 {
+		t.Error("Expecting the second glyph", i)
+	}
+	if _, ok := text.GlyphAt(mgl32.Vec2{-1, 0}); ok {
+		t.Error("Expecting the gap after the first glyph not to hit")
+	}
+}
+
+func TestRotatedHit(t *testing.T) {
+	text := &Text{Scale: 2, X1: gltext.Point{X: -4, Y: -1}, X2: gltext.Point{X: 4, Y: 1}, Position: mgl32.Vec2{10, 0}}
+	text.SetPivot(0.5, 0.5)
+	if !text.Contains(mgl32.Vec2{17, 1.5}) || text.Contains(mgl32.Vec2{10, 5}) {
+		t.Error("Bad scaled hit")
+	}
+
+	// a quarter turn makes the text tall
+	text.SetRotation(math.Pi / 2)
+	if text.Contains(mgl32.Vec2{17, 1.5}) || !text.Contains(mgl32.Vec2{11, 7}) {
+		t.Error("Bad rotated hit")
+	}
+	if p := text.drawnPoint(4, 0); math.Abs(float64(p.X-10)) > 1e-4 || math.Abs(float64(p.Y-8)) > 1e-4 {
+		t.Error("Bad drawn point", p)
+	}
+	if p := text.localPoint(mgl32.Vec2{10, 8}); math.Abs(float64(p.X-4)) > 1e-4 || math.Abs(float64(p.Y)) > 1e-4 {
+		t.Error("Bad local point", p)
+	}
+}
+
+func TestPicking(t *testing.T) {
+	if id, glyph, ok := DecodePick([4]uint8{1, 2, 0, 4}); !ok || id != 258 || glyph != 3 {
+		t.Error("Bad pick", id, glyph)
+	}
+	if _, _, ok := DecodePick([4]uint8{1, 2, 0, 0}); ok {
+		t.Error("Expecting nothing to be picked")
+	}
+
+	text := &Text{RuneCount: 2, eboIndexCount: 18}
+	text.glyphs = []layout.Glyph{{Index: 0}, {Index: 2}, {Index: 3}}
+	text.pageRanges = []pageRange{{page: 0, quads: []int{0, 2}}, {page: 1, first: 12, quads: []int{1}}}
+	if text.PickedRune(1) != 2 || text.PickedRune(5) != -1 {
+		t.Error("Bad picked rune")
+	}
+	ranges := text.drawRanges(text.drawCount())
+	if len(ranges) != 2 || ranges[0].count != 1 || ranges[1].first != 12 || ranges[1].count != 1 {
+		t.Error("Bad draw ranges", ranges)
+	}
+}
+
+func TestViewport(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(200, 100)
+	text := &Text{Font: f, Position: mgl32.Vec2{50, 25}}
+	text.SetPosition(text.Position)
+
+	// a viewport of the size of the window places the text like Draw
+	v := NewViewport(0, 0, 200, 100)
+	if p := v.finalPosition(text.center()); p != text.finalPosition {
+		t.Error("Bad final position", p, text.finalPosition)
+	}
+	half := NewViewport(0, 0, 100, 100)
+	if p := half.finalPosition(text.center()); p != (mgl32.Vec2{1, 0.5}) {
+		t.Error("Bad final position in a half viewport", p)
+	}
+}
+
+func TestRenderState(t *testing.T) {
+	var none *RenderState
+	if !none.textureChanged(1) || !none.textureChanged(1) {
+		t.Error("Expecting values to be sent without a state")
+	}
+	s := NewRenderState(&Font{})
+	if !s.textureChanged(1) || s.textureChanged(1) || !s.textureChanged(2) {
+		t.Error("Expecting textures to be bound when they change")
+	}
+	if !s.colorChanged(mgl32.Vec4{}) || s.colorChanged(mgl32.Vec4{}) || !s.fadeoutChanged(0) {
+		t.Error("Expecting every value to be sent once")
+	}
+
+	// decorations drawn between Begin and End replace the program and blending
+	s.projection = mgl32.Ortho2D(0, 100, 0, 100)
+	if none.covers(s.projection, BMAlpha) || !s.covers(s.projection, BMAlpha) || s.covers(s.projection, BMAdditive) {
+		t.Error("Expecting texts of the projection and blend mode of the state to rely on it")
+	}
+	none.interrupt()
+	s.interrupt()
+	if s.covers(s.projection, BMAlpha) {
+		t.Error("Expecting the text drawn after decorations to set up the program again")
+	}
+}
+
+func TestCulling(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(100, 100)
+	text := &Text{Font: f, Scale: 1, X1: gltext.Point{X: -5, Y: -5}, X2: gltext.Point{X: 5, Y: 5}}
+	text.SetPivot(0.5, 0.5)
+	for position, culled := range map[mgl32.Vec2]bool{{0, 0}: false, {54, 0}: false, {56, 0}: true, {0, -60}: true} {
+		text.Position = position
+		if text.Culled() != culled {
+			t.Error("Bad culling at", position)
+		}
+	}
+
+	// a camera rect replaces the window
+	text.Position = mgl32.Vec2{200, 0}
+	text.CullX1, text.CullX2 = gltext.Point{X: 150, Y: -50}, gltext.Point{X: 250, Y: 50}
+	if text.Culled() {
+		t.Error("Expecting the text to be within the camera rect")
+	}
+}
+
+func TestDepthMode(t *testing.T) {
+	for mode, expected := range map[DepthMode][2]bool{
+		DMUnchanged: {false, false},
+		DMOnTop:     {false, false},
+		DMTest:      {true, false},
+		DMTestWrite: {true, true},
+	} {
+		if mode.test() != expected[0] || mode.write() != expected[1] {
+			t.Error("Bad depth settings for mode", mode)
+		}
+	}
+
+	// the caller's depth state is left alone by default
+	text := &Text{}
+	if text.beginDepth().changed {
+		t.Error("Expecting the depth state to be unchanged")
+	}
+}
+
+func TestBlendMode(t *testing.T) {
+	for mode, expected := range map[BlendMode][2]uint32{
+		BMAlpha:         {gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA},
+		BMPremultiplied: {gl.ONE, gl.ONE_MINUS_SRC_ALPHA},
+		BMAdditive:      {gl.SRC_ALPHA, gl.ONE},
+	} {
+		if src, dst := mode.factors(); src != expected[0] || dst != expected[1] {
+			t.Error("Bad blend factors for mode", mode)
+		}
+	}
+}
+
+func TestOutputColor(t *testing.T) {
+	f := &Font{}
+	c := mgl32.Vec3{1, 0.5, 0}
+	if f.outputColor(c, 1) != (mgl32.Vec4{1, 0.5, 0, 1}) {
+		t.Error("Expecting colors to be sent unchanged")
+	}
+	f.SRGBFramebuffer = true
+	if linear := f.outputColor(c, 1); linear[0] != 1 || linear[1] != gltext.SRGBToLinear(0.5) || linear[2] != 0 {
+		t.Error("Expecting colors to be converted to linear light", linear)
+	}
+}
+
+func TestSetColorHex(t *testing.T) {
+	text := &Text{}
+	if _, alpha := text.Color(); alpha != 1 {
+		t.Error("Expecting texts to be opaque by default")
+	}
+	if err := text.SetColorHex("#ff000080"); err != nil {
+		t.Fatal(err)
+	}
+	if color, alpha := text.Color(); color != (mgl32.Vec3{1, 0, 0}) || alpha != 128.0/255 {
+		t.Error("Bad color", color, alpha)
+	}
+	if err := text.SetColorHex("Navy"); err != nil {
+		t.Fatal(err)
+	}
+	if color, _ := text.Color(); color != (mgl32.Vec3{0, 0, 128.0 / 255}) {
+		t.Error("Bad named color", color)
+	}
+	if text.SetColorHex("#nope") == nil {
+		t.Error("Expecting an error for a bad color")
+	}
+}
+
+func TestPalette(t *testing.T) {
+	defer SetPalette(gltext.Palette{})
+	SetPalette(gltext.Palette{"primary": {255, 0, 0, 255}})
+
+	text := &Text{}
+	if text.SetPaletteColor("error") == nil {
+		t.Error("Expecting an error for a missing palette color")
+	}
+	if err := text.SetPaletteColor("primary"); err != nil {
+		t.Fatal(err)
+	}
+	if color, _ := text.Color(); color != (mgl32.Vec3{1, 0, 0}) {
+		t.Error("Bad palette color", color)
+	}
+
+	// swapping the palette re-tints the text until it stops following it
+	SetPalette(gltext.Palette{"primary": {0, 0, 255, 255}})
+	if color, _ := text.Color(); color != (mgl32.Vec3{0, 0, 1}) {
+		t.Error("Expecting the text to be re-tinted", color)
+	}
+	text.ClearPaletteColor()
+	SetPalette(gltext.Palette{"primary": {0, 255, 0, 255}})
+	if color, _ := text.Color(); color != (mgl32.Vec3{0, 0, 1}) {
+		t.Error("Expecting the text to keep its color", color)
+	}
+}
+
+func TestFadeMask(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(200, 100)
+	text := &Text{Font: f}
+	if outer, inner := text.maskEdges(f.OrthographicMatrix); outer != inner || outer[0] > -1 || outer[3] < 1 {
+		t.Error("Expecting everything to be drawn without a mask", outer, inner)
+	}
+
+	text.SetFadeMask(&FadeMask{X1: gltext.Point{X: -50, Y: -25}, X2: gltext.Point{X: 50, Y: 25}, Fade: gltext.Point{X: 10}})
+	outer, inner := text.maskEdges(f.OrthographicMatrix)
+	if outer != (mgl32.Vec4{-0.5, -0.5, 0.5, 0.5}) {
+		t.Error("Bad outer edges", outer)
+	}
+	if !inner.ApproxEqualThreshold(mgl32.Vec4{-0.4, -0.5, 0.4, 0.5}, 1e-6) {
+		t.Error("Bad inner edges", inner)
+	}
+}
+
+func TestMarquee(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(200, 100)
+	text := &Text{Font: f, Scale: 1, X1: gltext.Point{X: -50, Y: -5}, X2: gltext.Point{X: 50, Y: 5}}
+	m := &Marquee{Text: text, Width: 60, Speed: 10, Pause: 1, Loop: true}
+	m.Reset()
+
+	// pausing at the start, then scrolling the 40 overflowing pixels in 4 seconds
+	for i, expected := range []float32{0, 0, 10, 20, 30, 40, 0, 0, 10, 20} {
+		if m.offset != expected {
+			t.Errorf("Expecting an offset of %v after %d steps, got %v", expected, i, m.offset)
+		}
+		m.advance(1)
+	}
+	m.place()
+	if x := m.Text.Position.X(); x != -30-30 {
+		t.Error("Expecting the text to be scrolled", x)
+	}
+
+	// bouncing back
+	m.Bounce, m.offset, m.direction, m.wait = true, 35, 1, 0
+	m.advance(1)
+	m.advance(1)
+	m.advance(1)
+	if m.offset != 30 {
+		t.Error("Expecting the text to scroll back", m.offset)
+	}
+
+	// text that fits is not scrolled
+	m.Width = 120
+	m.advance(1)
+	if m.offset != 0 || m.Scrolling() {
+		t.Error("Expecting text that fits to stay in place")
+	}
+}
+
+func TestSetWorldPosition(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(200, 100)
+	text := &Text{Font: f, Scale: 1}
+	camera := mgl32.Perspective(mgl32.DegToRad(90), 2, 0.1, 100).Mul4(mgl32.LookAtV(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0}))
+
+	// the same offset from the view axis lands closer to the center when farther away
+	if !text.SetWorldPosition(mgl32.Vec3{1, 0, -2}, camera) {
+		t.Fatal("Expecting the point to be in front of the camera")
+	}
+	near := text.Position.X()
+	text.SetWorldPosition(mgl32.Vec3{1, 0, -4}, camera)
+	if far := text.Position.X(); far >= near || far <= 0 {
+		t.Error("Bad projected positions", near, far)
+	}
+	if text.SetWorldPosition(mgl32.Vec3{0, 0, 2}, camera) {
+		t.Error("Expecting points behind the camera to be rejected")
+	}
+}
+
+func TestMultiScaleFont(t *testing.T) {
+	fonts := make([]*Font, 3)
+	for i, height := range []int{32, 8, 16} {
+		fonts[i] = &Font{Config: &gltext.FontConfig{LineHeight: height}}
+	}
+	m, err := NewMultiScaleFont(fonts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Fonts[0] != fonts[1] || m.Fonts[2] != fonts[0] {
+		t.Error("Expecting the fonts to be ordered by line height")
+	}
+	for lineHeight, expected := range map[float32]*Font{4: fonts[1], 11: fonts[1], 12: fonts[2], 40: fonts[0]} {
+		if f := m.Pick(lineHeight); f != expected {
+			t.Errorf("Expecting a line height of %v to pick the font of %v pixels, got %v", lineHeight, expected.lineHeight(), f.lineHeight())
+		}
+	}
+	if _, err := NewMultiScaleFont(); err == nil {
+		t.Error("Expecting an error without fonts")
+	}
+}
+
+func TestStats(t *testing.T) {
+	f := &Font{}
+	f.stats.record(1, 5)
+	f.stats.record(3, 7)
+	if s := f.Stats(); s != (Stats{Texts: 2, DrawCalls: 4, Glyphs: 12}) {
+		t.Error("Bad stats", s)
+	}
+	f.ResetStats()
+	if s := f.Stats(); s != (Stats{}) {
+		t.Error("Expecting the stats to be reset", s)
+	}
+}
+
+func TestMemoryUsage(t *testing.T) {
+	f := &Font{textureWidth: 256, textureHeight: 128, pageTextureIDs: []uint32{1, 2}}
+	if m := f.MemoryUsage(); m.Texture != 2*256*128*4 || m.CPU != 0 {
+		t.Error("Bad font memory usage", m)
+	}
+	f.minFilter = FilterLinearMipmapLinear
+	if m := f.MemoryUsage(); m.Texture != 2*(256*128*4+256*128*4/3) {
+		t.Error("Expecting mipmaps to be counted", m)
+	}
+	f.minFilter, f.atlasFormat = FilterLinear, AFBC4
+	if m := f.MemoryUsage(); m.Texture != 2*256*128/2 {
+		t.Error("Expecting compressed pages", m)
+	}
+
+	text := &Text{vboCapacity: 16, eboCapacity: 6, vboData: make([]float32, 16), eboData: make([]int32, 6)}
+	if m := text.MemoryUsage(); m.Buffers != 22*4 || m.CPU != 22*4 || m.Total() != 44*4 {
+		t.Error("Bad text memory usage", m)
+	}
+}
+
+func TestLeakWarnings(t *testing.T) {
+	gltext.LeakWarnings = true
+	defer func() { gltext.LeakWarnings = false }()
+
+	// the finalizers do not fire before the objects are collected and are removed on release
+	f := &Font{}
+	watchFontLeak(f)
+	unwatchLeak(f)
+	text := &Text{Font: f}
+	watchTextLeak(text)
+	unwatchLeak(text)
+	if fontName(f) != "" {
+		t.Error("Expecting fonts without a config to have no name")
+	}
+}
+
+func TestTextOptions(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(100, 100)
+	text := &Text{Font: f}
+	for _, opt := range []TextOption{WithScaleRange(1.5, 2), WithMaxWidth(40), WithMaxRuneCount(10), WithColor(mgl32.Vec3{1, 0, 0}), WithAnchor(AnchorCenter), WithAlign(layout.AlignRight), WithNormalize(), WithTruncate(TruncateEllipsis)} {
+		opt(text)
+	}
+	if text.ScaleMin != 1.5 || text.ScaleMax != 2 || text.Scale != 1.5 {
+		t.Error("Bad scale range", text.ScaleMin, text.ScaleMax, text.Scale)
+	}
+	// like NewText the option rests at a scale of 1 when the boundaries allow it
+	WithScaleRange(0.5, 2)(text)
+	if text.Scale != 1 {
+		t.Error("Expecting the scale of a new text", text.Scale)
+	}
+	rested := &Text{Font: f}
+	rested.setDefaults(1.5, 2)
+	if rested.Scale != 1.5 {
+		t.Error("Expecting new texts to rest at scaleMin when the boundaries exclude 1", rested.Scale)
+	}
+	if text.MaxWidth != 40 || text.MaxRuneCount != 10 || text.Align != layout.AlignRight || !text.Normalize || text.Truncate != TruncateEllipsis {
+		t.Error("Bad limits", text.MaxWidth, text.MaxRuneCount, text.Align, text.Normalize, text.Truncate)
+	}
+	if color, _ := text.Color(); color != (mgl32.Vec3{1, 0, 0}) {
+		t.Error("Bad color", color)
+	}
+}
+
+func TestLayoutSnapshot(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'b'}}
+	f.Config.Glyphs = gltext.Charset{{X: 0, Advance: 2, Height: 2}, {X: 2, Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+	f.ResizeWindow(100, 100)
+
+	snapshot := func(position mgl32.Vec2) LayoutSnapshot {
+		text := &Text{Font: f, Scale: 1, String: "ab"}
+		indices := []rune(text.String)
+		text.vboData = make([]float32, len(indices)*16)
+		text.eboData = make([]int32, len(indices)*6)
+		text.makeBufferData(indices)
+		text.centerTheData(text.getLowerLeft())
+		text.SetPosition(position)
+		return text.LayoutSnapshot()
+	}
+	s := snapshot(mgl32.Vec2{})
+	if !reflect.DeepEqual(s, snapshot(mgl32.Vec2{20, -5})) {
+		t.Error("Expecting snapshots not to depend on the position of the text")
+	}
+	expected := GlyphSnapshot{Index: 1, Rune: 'b', X1: gltext.Point{X: 0, Y: -1}, X2: gltext.Point{X: 2, Y: 1}, UV1: gltext.Point{X: 0.25, Y: 0.5}, UV2: gltext.Point{X: 0.5, Y: 0}}
+	if len(s.Glyphs) != 2 || s.Glyphs[1] != expected {
+		t.Errorf("Expecting %+v, got %+v", expected, s.Glyphs)
+	}
+	if len(s.Lines) != 1 || s.Lines[0].X1 != (gltext.Point{X: -2, Y: -1}) {
+		t.Error("Bad lines", s.Lines)
+	}
+}
+
+func TestWriteGLTF(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{Image: image.NewNRGBA(image.Rect(0, 0, 8, 4))}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'b'}}
+	f.Config.Glyphs = gltext.Charset{{X: 0, Advance: 2, Height: 2}, {X: 2, Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+	f.ResizeWindow(100, 100)
+
+	text := &Text{Font: f, Scale: 1, String: "ab"}
+	indices := []rune(text.String)
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	var out bytes.Buffer
+	if err := text.WriteGLTF(&out, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	var doc gltfDocument
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Meshes) != 1 || len(doc.Meshes[0]["primitives"]) != 1 || len(doc.Images) != 1 {
+		t.Fatal("Expecting a single primitive and image", doc.Meshes, len(doc.Images))
+	}
+	position := doc.Accessors[doc.Meshes[0]["primitives"][0].Attributes["POSITION"]]
+	if position.Count != 8 || position.Min[0] != -1 || position.Max[0] != 1 || position.Max[1] != 0.5 {
+		t.Error("Bad positions", position)
+	}
+	if index := doc.Accessors[doc.Meshes[0]["primitives"][0].Indices]; index.Count != 12 || index.ComponentType != gltfUnsignedInt {
+		t.Error("Bad indices", index)
+	}
+	if doc.Buffers[0].ByteLength != 8*3*4+8*2*4+12*4 {
+		t.Error("Bad buffer length", doc.Buffers[0].ByteLength)
+	}
+}
+
+func TestVectorVertices(t *testing.T) {
+	square := []gltext.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	other := []gltext.Point{{X: 20, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 10}, {X: 20, Y: 10}}
+	outlines := []gltext.GlyphOutline{{Contours: [][]gltext.Point{square}}, {Index: 1, Contours: [][]gltext.Point{other}}}
+
+	vertices, x1, x2 := vectorVertices(outlines)
+	if len(vertices) != 4*3*2 {
+		t.Fatal("Expecting two triangles per square", len(vertices))
+	}
+	if x1 != (gltext.Point{X: -15, Y: -5}) || x2 != (gltext.Point{X: 15, Y: 5}) {
+		t.Error("Bad bounds", x1, x2)
+	}
+	for i := 0; i < len(vertices); i += 2 {
+		if x, y := vertices[i], vertices[i+1]; (x != -15 && x != -5 && x != 5 && x != 15) || (y != -5 && y != 5) {
+			t.Fatal("Bad centering", x, y)
+		}
+	}
+	if vertices, _, _ := vectorVertices(nil); len(vertices) != 0 {
+		t.Error("Expecting no vertices", vertices)
+	}
+}
+
+func TestCurveVertices(t *testing.T) {
+	square := []gltext.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	bulge := gltext.Curve{Start: gltext.Point{X: 10, Y: 0}, Control: gltext.Point{X: 20, Y: 5}, End: gltext.Point{X: 10, Y: 10}}
+	outlines := []gltext.GlyphCurves{{Polygons: [][]gltext.Point{square}, Curves: []gltext.Curve{bulge}}}
+
+	vertices, x1, x2 := curveVertices(outlines)
+	// a fan of two triangles, one curve triangle and the covering quad
+	if len(vertices) != (2*3+3+6)*4 {
+		t.Fatal("Bad vertex count", len(vertices)/4)
+	}
+	if x1 != (gltext.Point{X: -10, Y: -5}) || x2 != (gltext.Point{X: 10, Y: 5}) {
+		t.Error("Bad bounds", x1, x2)
+	}
+	curve := vertices[6*4 : 9*4]
+	if !reflect.DeepEqual(curve, []float32{0, -5, 0, 0, 10, 0, 0.5, 0, 0, 5, 1, 1}) {
+		t.Error("Bad curve triangle", curve)
+	}
+	if cover := vertices[9*4:]; cover[0] != -10 || cover[1] != -5 || cover[2] != 0 || cover[3] != 1 {
+		t.Error("Bad cover", cover)
+	}
+}
+
+func TestNewFontLazy(t *testing.T) {
+	config := &gltext.FontConfig{Image: image.NewNRGBA(image.Rect(0, 0, 100, 60))}
+	config.Glyphs = gltext.Charset{{Width: 4, Height: 9}, {Width: 7, Height: 5}}
+
+	// nothing touches gl until Init
+	f := NewFontLazy(config)
+	if f.Initialized() || f.Config != config {
+		t.Fatal("Expecting an uninitialized font")
+	}
+	if f.textureWidth != 128 || f.textureHeight != 64 || f.maxGlyphWidth != 7 || f.maxGlyphHeight != 9 {
+		t.Error("Bad metrics", f.textureWidth, f.textureHeight, f.maxGlyphWidth, f.maxGlyphHeight)
+	}
+	f.SetTextureFilter(FilterLinearMipmapLinear, FilterNearest)
+	if err := f.SetAtlasFormat(AFR8); err != nil || f.AtlasFormat() != AFR8 || f.minFilter != FilterLinearMipmapLinear {
+		t.Error("Expecting settings to be kept for Init", err)
+	}
+	f.Release()
+	if f.sources != nil || f.Init() == nil {
+		t.Error("Expecting a released font to never create gl objects")
+	}
+}
+
+func TestNewFontShared(t *testing.T) {
+	config := &gltext.FontConfig{Image: image.NewNRGBA(image.Rect(0, 0, 100, 60))}
+	config.Glyphs = gltext.Charset{{Width: 4, Height: 9}}
+
+	// the render context neither draws nor creates the font during the upload
+	f := NewFontShared(config)
+	if f.Init() == nil || f.Initialized() || f.sources == nil {
+		t.Fatal("Expecting the font to wait for its upload")
+	}
+	f.shared.done = true
+	if f.Init() != nil || !f.Initialized() {
+		t.Error("Expecting an uploaded font")
+	}
+	if NewFontLazy(config).Upload() == nil {
+		t.Error("Expecting lazy fonts to be initialized by Init")
+	}
+}
+
+func TestRangeRects(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1}
+	indices := []rune("ab\nc")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	// the range continues on the next line
+	rects := text.RangeRects(1, 4)
+	if len(rects) != 2 {
+		t.Fatal("Expecting a rectangle per line", rects)
+	}
+	if rects[0] != [2]gltext.Point{{X: 0, Y: -2}, {X: 2, Y: 0}} || rects[1] != [2]gltext.Point{{X: -2, Y: -4}, {X: 0, Y: -2}} {
+		t.Error("Bad rectangles", rects)
+	}
+	if rects = text.RangeRects(0, 2); len(rects) != 1 || rects[0][0].X != -2 || rects[0][1].X != 2 {
+		t.Error("Bad single line range", rects)
+	}
+
+	text.Highlight(0, 1, mgl32.Vec4{1, 1, 0, 1})
+	if len(text.highlights) != 1 {
+		t.Error("Expecting a highlight")
+	}
+	text.ClearHighlights()
+	if len(text.highlights) != 0 {
+		t.Error("Expecting no highlight")
+	}
+}
+
+func TestUpdateLinks(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	clicks := 0
+	text := &Text{Font: f, Scale: 1}
+	text.links = []gltext.Link{{Start: 0, End: 2}}
+	text.linkClicks = []func(){func() { clicks++ }}
+	indices := []rune("ab\nc")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	link, off := mgl32.Vec2{1, -1}, mgl32.Vec2{-1, -3}
+	text.UpdateLinks(link, true)
+	text.UpdateLinks(link, false)
+	if clicks != 1 {
+		t.Error("Expecting a click", clicks)
+	}
+	// presses that began elsewhere are ignored
+	text.UpdateLinks(off, true)
+	text.UpdateLinks(link, false)
+	text.UpdateLinks(link, true)
+	text.UpdateLinks(off, false)
+	if clicks != 1 {
+		t.Error("Expecting no other click", clicks)
+	}
+
+	rects := &Rects{}
+	text.addUnderlines(rects)
+	if rects.Len() != 1 || rects.vboData[1] != -2 || rects.vboData[13] != -1 {
+		t.Error("Expecting an underline along the bottom of the link", rects.vboData)
+	}
+}
+
+func TestSquiggle(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1}
+	indices := []rune("ab\nc")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	text.Squiggle(0, 2, 1, mgl32.Vec4{1, 0, 0, 1})
+	rects := &Rects{}
+	text.addSquiggles(rects)
+	if rects.Len() != 8 {
+		t.Fatal("Expecting a rectangle every half amplitude", rects.Len())
+	}
+	low, high := float32(0), float32(-3)
+	for i := 0; i < len(rects.vboData); i += 6 {
+		if y := rects.vboData[i+1]; y < low {
+			low = y
+		} else if y > high {
+			high = y
+		}
+	}
+	if rects.vboData[0] != -2 || rects.vboData[len(rects.vboData)-6*2] != 2 || low < -2.5 || high > 0.5 || high-low < 1.5 {
+		t.Error("Bad wave", rects.vboData)
+	}
+	text.ClearSquiggles()
+	if len(text.squiggles) != 0 {
+		t.Error("Expecting no squiggle")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	text := &Text{}
+	decomposed := "e\u0301te\u0301"
+	if runes, _ := text.runes(decomposed); len(runes) != 5 {
+		t.Error("Expecting the combining marks to be kept", string(runes))
+	}
+	text.Normalize = true
+	if runes, _ := text.runes(decomposed); string(runes) != "\u00e9t\u00e9" {
+		t.Error("Expecting precomposed accents", string(runes))
+	}
+}
+
+func TestShowWhitespace(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: ' ', High: ' '}, {Low: 'a', High: 'a'}, {Low: '·', High: '·'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 4, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1, ShowWhitespace: true}
+	indices := []rune("a a")
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+
+	if len(text.glyphs) != 3 || text.glyphs[1].Glyph != 2 {
+		t.Fatal("Expecting the space to be drawn with the middle dot", text.glyphs)
+	}
+	// the dot is centered over the advance of the space
+	if text.CharSpacing[1] != 4 || text.vboData[16] != 3 || text.glyphs[2].X != 6 {
+		t.Error("Bad space", text.CharSpacing, text.vboData[16], text.glyphs[2])
+	}
+}
+
+func TestLogicalBounds(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: ' ', High: ' '}, {Low: 'a', High: 'a'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 4}, {Advance: 2, Width: 1, Height: 2, OffsetY: 1}}
+	f.maxGlyphHeight = 4
+
+	text := &Text{Font: f, Scale: 1, String: "a a\t"}
+	indices := []rune(text.String)
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+	text.centerTheData(text.getLowerLeft())
+
+	// the trailing space widens the box, the tab only the logical bounds
+	if text.Width() != 6 || text.AdvanceWidth() != 8 {
+		t.Error("Bad widths", text.Width(), text.AdvanceWidth())
+	}
+	X1, X2 := text.InkBounds()
+	B1, _ := text.GetBoundingBox()
+	if X2.X-X1.X != 5 || X2.Y-X1.Y != 2 || X1.X != B1.X || X1.Y != B1.Y+1 {
+		t.Error("Bad ink bounds", X1, X2, B1)
+	}
+}
+
+func TestDrawQueueSorting(t *testing.T) {
+	a := &Font{program: 2, textureID: 1}
+	b := &Font{program: 1, textureID: 3}
+	c := &Font{program: 1, textureID: 2}
+	texts := []*Text{{Font: a}, {Font: b}, {Font: c, Blend: BMAdditive}, {Font: c}, {Font: b}}
+
+	q := NewDrawQueue()
+	for _, text := range texts {
+		q.Queue(text)
+	}
+	if q.Len() != 5 {
+		t.Fatal("Expecting every text to be queued", q.Len())
+	}
+	sorted := q.sorted()
+	expected := []*Text{texts[3], texts[1], texts[4], texts[2], texts[0]}
+	for i := range expected {
+		if sorted[i] != expected[i] {
+			t.Fatal("Bad order at", i, sorted[i].Font, sorted[i].Blend)
+		}
+	}
+}
+
+func TestCommandListRecord(t *testing.T) {
+	f := &Font{pageTextureIDs: []uint32{5}, textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	texts := []*Text{}
+	for _, s := range []string{"ab", "c"} {
+		text := &Text{Font: f, Scale: 2, ScaleMin: 1, ScaleMax: 2}
+		indices := []rune(s)
+		text.vboData = make([]float32, len(indices)*16)
+		text.eboData = make([]int32, len(indices)*6)
+		text.eboIndexCount, text.RuneCount = len(indices)*6, len(indices)
+		text.makeBufferData(indices)
+		text.centerTheData(text.getLowerLeft())
+		texts = append(texts, text)
+	}
+
+	l := &CommandList{Font: f}
+	if err := l.record(texts); err != nil {
+		t.Fatal(err)
+	}
+	if l.Len() != 1 || l.commands[0].count != 18 || l.commands[0].texture != 5 {
+		t.Fatal("Expecting the draws sharing their uniforms to be merged", l.commands)
+	}
+	if len(l.vboData) != 3*16 || l.eboData[12] != 8+texts[1].eboData[0] {
+		t.Error("Expecting the vertices of the second text to follow those of the first", len(l.vboData), l.eboData)
+	}
+	// the vbo data of texts is in the coordinates of the layout, moved by the origin
+	o := texts[0].origin
+	if p := texts[0].drawnPoint(texts[0].vboData[0]+o.X, texts[0].vboData[1]+o.Y); l.vboData[0] != p.X || l.vboData[1] != p.Y || l.vboData[2] != texts[0].vboData[2] {
+		t.Error("Expecting the vertices where they are drawn", l.vboData[:4], p)
+	}
+
+	texts[1].SetColor(mgl32.Vec3{1, 0, 0})
+	if l.record(texts); l.Len() != 2 {
+		t.Error("Expecting a draw per color", l.commands)
+	}
+	if err := l.record([]*Text{{Font: &Font{}}}); err == nil {
+		t.Error("Expecting texts of other fonts to be refused")
+	}
+}
+
+func TestThreadChecks(t *testing.T) {
+	defer func(checks bool) { gltext.ThreadChecks = checks }(gltext.ThreadChecks)
+	gltext.ThreadChecks = true
+
+	// keep the goroutines on their threads so that they differ
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	f := &Font{}
+	f.claimThread()
+	f.checkThread("Draw")
+
+	panicked := make(chan interface{})
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer func() { panicked <- recover() }()
+		f.checkThread("Draw")
+	}()
+	if message, ok := (<-panicked).(string); !ok || !strings.Contains(message, "Draw called from another os thread") {
+		t.Error("Expecting calls from other threads to panic", message)
+	}
+}
+
+func TestSkippedRunes(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, Scale: 1, String: "axb\n\tyxc"}
+	indices := []rune(text.String)
+	text.vboData = make([]float32, len(indices)*16)
+	text.eboData = make([]int32, len(indices)*6)
+	text.makeBufferData(indices)
+
+	if skipped := text.SkippedRunes(); string(skipped) != "xy" {
+		t.Error("Expecting the runes without glyphs once each", string(skipped))
+	}
+}
+
+func TestInitError(t *testing.T) {
+	// a lazy font whose gl objects failed to be created
+	f := &Font{initErr: errors.New("Shader failed to compile.")}
+	text := &Text{Font: f, initErr: f.initErr}
+	if err := text.SetStringChecked("a"); err != f.initErr || text.String != "" {
+		t.Error("Expecting the error of initializing the font", err, text.String)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	f := &Font{}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: '.', High: '.'}, {Low: 'a', High: 'z'}}
+
+	dropped := 0
+	text := &Text{Font: f, MaxRuneCount: 5, OnTruncate: func(n int) { dropped = n }}
+	if runes, err := text.runes("abcde"); err != nil || string(runes) != "abcde" || dropped != 0 {
+		t.Error("Expecting strings of MaxRuneCount runes to be kept", string(runes), err, dropped)
+	}
+	if runes, _ := text.runes("abcdef"); string(runes) != "abcde" || dropped != 1 {
+		t.Error("Bad cut", string(runes), dropped)
+	}
+
+	// the font has no ellipsis glyph
+	text.Truncate = TruncateEllipsis
+	if runes, _ := text.runes("abcdefgh"); string(runes) != "ab..." || dropped != 6 {
+		t.Error("Bad ellipsis", string(runes), dropped)
+	}
+	f.Config.RuneRanges = append(f.Config.RuneRanges, gltext.RuneRange{Low: '…', High: '…'})
+	if runes, _ := text.runes("abcdefgh"); string(runes) != "abcd…" || dropped != 4 {
+		t.Error("Bad ellipsis glyph", string(runes), dropped)
+	}
+
+	text.Truncate = TruncateReject
+	if runes, err := text.runes("abcdefgh"); err == nil || runes != nil || dropped != 3 {
+		t.Error("Expecting long strings to be rejected", string(runes), dropped)
+	}
+}
+
+func TestBoundingPolygon(t *testing.T) {
+	text := &Text{Scale: 2, X1: gltext.Point{X: -4, Y: -1}, X2: gltext.Point{X: 4, Y: 1}, Position: mgl32.Vec2{10, 0}}
+	text.SetPivot(0.5, 0.5)
+	text.SetRotation(math.Pi / 2)
+	text.glyphBounds = [][2]gltext.Point{{{X: -4, Y: -1}, {X: 0, Y: 1}}, {{X: 0, Y: -1}, {X: 0, Y: 1}}}
+
+	near := func(p gltext.Point, x, y float32) bool {
+		return math.Abs(float64(p.X-x)) < 1e-4 && math.Abs(float64(p.Y-y)) < 1e-4
+	}
+	polygon := text.BoundingPolygon()
+	if !near(polygon[0], 12, -8) || !near(polygon[1], 12, 8) || !near(polygon[2], 8, 8) || !near(polygon[3], 8, -8) {
+		t.Error("Bad polygon", polygon)
+	}
+	if glyphs := text.GlyphPolygons(); len(glyphs) != 1 || !near(glyphs[0][0], 12, -8) || !near(glyphs[0][2], 8, 0) {
+		t.Error("Expecting the polygon of the glyph with ink", glyphs)
+	}
+}
+
+func TestAccessibleTexts(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(100, 100)
+	box := func(s string, x, y float32) *Text {
+		text := &Text{Font: f, String: s, Scale: 1, X1: gltext.Point{X: -5, Y: -2}, X2: gltext.Point{X: 5, Y: 2}, Position: mgl32.Vec2{x, y}}
+		text.SetPivot(0.5, 0.5)
+		return text
+	}
+	right, left, below, footer := box("right", 20, 10), box("left", -20, 10), box("below", -20, -10), box("footer", 0, -40)
+	right.RegisterAccessible("", 0)
+	below.RegisterAccessible("", 0)
+	left.RegisterAccessible("", 0)
+	footer.RegisterAccessible("Footer", 1)
+	defer func() {
+		for _, text := range []*Text{right, left, below, footer} {
+			text.UnregisterAccessible()
+		}
+	}()
+
+	texts := AccessibleTexts()
+	if len(texts) != 4 || texts[0].Text != left || texts[1].Text != right || texts[2].Text != below || texts[3].Label != "Footer" {
+		t.Fatal("Bad reading order", texts)
+	}
+	if texts[0].Label != "left" || texts[0].Min != (gltext.Point{X: 25, Y: 38}) || texts[0].Max != (gltext.Point{X: 35, Y: 42}) {
+		t.Error("Bad window rectangle", texts[0])
+	}
+	footer.UnregisterAccessible()
+	if len(AccessibleTexts()) != 3 {
+		t.Error("Expecting the unregistered text to be left out")
+	}
+}
+
+func TestFloatingText(t *testing.T) {
+	f := &Font{}
+	f.ResizeWindow(100, 100)
+	ft := NewFloatingText(f)
+	ft.Easing = gltext.EaseLinear
+	a, b := &floating{text: &Text{Font: f}}, &floating{text: &Text{Font: f}, age: 0.5}
+	ft.active = []*floating{a, b}
+
+	ft.Update(0.25)
+	if ft.Len() != 2 || a.text.Position != (mgl32.Vec2{0, 10}) || a.text.transparency != 0 {
+		t.Error("Bad rising text", a.text.Position, a.text.transparency)
+	}
+	if b.text.Position != (mgl32.Vec2{0, 30}) || b.text.transparency != 0.5 {
+		t.Error("Bad fading text", b.text.Position, b.text.transparency)
+	}
+
+	ft.Update(0.25)
+	if ft.Len() != 1 || ft.active[0] != a || len(ft.free) != 1 || ft.free[0] != b {
+		t.Fatal("Expecting the text whose lifetime is over to be recycled")
+	}
+	ft.Clear()
+	if ft.Len() != 0 || len(ft.free) != 2 {
+		t.Error("Expecting every text to be recycled", ft.Len(), len(ft.free))
+	}
+}
+
+func TestCounter(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.ResizeWindow(100, 100)
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: '-', High: '9'}}
+	f.Config.Glyphs = make(gltext.Charset, '9'-'-'+1)
+	for i := range f.Config.Glyphs {
+		f.Config.Glyphs[i] = gltext.Glyph{Advance: 4, Height: 2}
+	}
+	f.Config.Glyphs['1'-'-'].Advance = 2
+	f.maxGlyphHeight = 2
+
+	c := &Counter{Text: &Text{Font: f}, Digits: 3}
+	if err := c.bake(); err != nil {
+		t.Fatal(err)
+	}
+	if c.RuneCount != 1 || c.String != "0" || c.Width() != 12 || c.X1 != (gltext.Point{X: -6, Y: -1}) {
+		t.Fatal("Expecting a single zero in the rightmost of three cells", c.RuneCount, c.String, c.X1, c.X2)
+	}
+	// the cells are stored from the lower left corner of the counter, the shader centering them
+	if c.vboData[0] != 8 || c.vboData[4] != 12 || c.origin != c.X1 {
+		t.Error("Bad rightmost cell", c.vboData[:8], c.origin)
+	}
+
+	if first, last := c.setValue(-1); first != 0 || last != 1 || c.RuneCount != 2 {
+		t.Error("Expecting the two rightmost cells to change", first, last, c.RuneCount)
+	}
+	// the narrow one is centered in its cell
+	if c.vboData[0] != 9 || c.vboData[4] != 11 || c.vboData[16] != 4 {
+		t.Error("Bad cells", c.vboData[:20])
+	}
+	if first, last := c.setValue(-2); first != 0 || last != 0 {
+		t.Error("Expecting only the rightmost cell to change", first, last)
+	}
+	if first, last := c.setValue(-2); first <= last {
+		t.Error("Expecting no change", first, last)
+	}
+
+	c.setValue(12345)
+	if c.Value() != 999 || c.RuneCount != 3 {
+		t.Error("Expecting the value to be clamped", c.Value())
+	}
+	c.setValue(-12345)
+	if c.Value() != -99 || c.String != "-99" {
+		t.Error("Expecting the value to be clamped", c.Value())
+	}
+	c.setValue(7)
+	for _, v := range c.vboData[2*counterQuadSize:] {
+		if v != 0 {
+			t.Fatal("Expecting blank cells to be cleared", c.vboData)
+		}
+	}
+
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: '0', High: '9'}}
+	if err := c.bake(); err == nil {
+		t.Error("Expecting an error for the missing minus sign")
+	}
+}
+
+func TestStaticVertices(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, String: "abc"}
+	text.layOut([]rune(text.String))
+	vbo, ebo := text.vboData, text.eboData
+
+	text.static = true
+	text.vboData, text.eboData = nil, nil
+	v, e := text.vertices()
+	if !reflect.DeepEqual(v, vbo) || !reflect.DeepEqual(e, ebo) {
+		t.Error("Expecting the dropped data to be laid out again", v, e)
+	}
+	if text.vboData != nil || text.eboData != nil {
+		t.Error("Expecting the data laid out again not to be kept")
+	}
+	if err := text.SetStringChecked("ab"); err == nil || text.String != "abc" {
+		t.Error("Expecting static text not to be set", err)
+	}
+}
+
+func TestReleaseCPUBuffers(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4, pageTextureIDs: []uint32{1}}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	text := &Text{Font: f, String: "abc", RuneCount: 3}
+	text.layOut([]rune(text.String))
+	before := text.MemoryUsage().CPU
+	mesh := text.Mesh()
+
+	text.ReleaseCPUBuffers()
+	if text.vboData != nil || text.eboData != nil || text.MemoryUsage().CPU != before-(48+18)*4 {
+		t.Fatal("Expecting the vertex data to be dropped", text.MemoryUsage().CPU, before)
+	}
+	if !reflect.DeepEqual(text.Mesh(), mesh) {
+		t.Error("Expecting the mesh to be laid out again")
+	}
+	if text.vboData != nil {
+		t.Error("Expecting the data laid out again not to be kept")
+	}
+}
+
+func TestTextPool(t *testing.T) {
+	f := &Font{premultiplied: true}
+	p := NewTextPool(f, 1, 2)
+	text := &Text{Font: f, vao: 3, vbo: 4, ebo: 5, vboCapacity: 32, eboCapacity: 12, String: "ab", RuneCount: 2}
+	text.RegisterAccessible("label", 0)
+	text.SetColor(mgl32.Vec3{1, 0, 0})
+
+	p.Release(text)
+	if p.Len() != 1 || len(AccessibleTexts()) != 0 {
+		t.Fatal("Expecting the text to be pooled and unregistered")
+	}
+	if p.Acquire() != text || p.Len() != 0 {
+		t.Fatal("Expecting the pooled text to be reused")
+	}
+	if text.String != "" || text.RuneCount != 0 || text.color != (mgl32.Vec3{}) {
+		t.Error("Expecting the state of a new text", text.String, text.color)
+	}
+	if text.vao != 3 || text.vbo != 4 || text.ebo != 5 || text.vboCapacity != 32 || text.eboCapacity != 12 {
+		t.Error("Expecting the gl objects to be kept", text.vao, text.vbo, text.ebo)
+	}
+	if text.ScaleMin != 1 || text.ScaleMax != 2 || text.Scale != 1 || text.Blend != BMPremultiplied {
+		t.Error("Expecting the defaults of a new text", text.ScaleMin, text.ScaleMax, text.Scale, text.Blend)
+	}
+}
+
+func TestQuadPattern(t *testing.T) {
+	if !isQuadPattern(quadPattern(3), 3) || !isQuadPattern(nil, 0) || !isQuadPattern(append(quadPattern(1), make([]int32, 6)...), 1) {
+		t.Error("Expecting quads drawn in order")
+	}
+	if isQuadPattern([]int32{4, 5, 6, 4, 6, 7, 0, 1, 2, 0, 2, 3}, 2) || isQuadPattern([]int32{0, 1, 2}, 0) || isQuadPattern(quadPattern(1), 2) {
+		t.Error("Expecting quads out of order not to match")
+	}
+
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+	text := &Text{Font: f}
+	text.layOut([]rune("abc"))
+	if !isQuadPattern(text.eboData, text.quadCount()) {
+		t.Error("Expecting a single run of quads to use the shared indices", text.eboData)
+	}
+
+	// newlines and runes without a glyph leave unused values at the end of the ebo
+	text.layOut([]rune("ab\nzc"))
+	if text.quadCount() != 3 || len(text.eboData) != 5*6 || !isQuadPattern(text.eboData, text.quadCount()) {
+		t.Error("Expecting the quads of the drawn runes to use the shared indices", text.quadCount(), text.eboData)
+	}
+	text.styleRuns = gltext.StyleRuns{{Start: 0, End: 1, Color: image.Black}}
+	text.layOut([]rune("abc"))
+	if isQuadPattern(text.eboData, text.quadCount()) {
+		t.Error("Expecting the quads grouped by color to keep their own indices", text.eboData)
+	}
+}
+
+func TestVertexFormat(t *testing.T) {
+	for f, half := range map[float32]uint16{
+		0: 0, 1: 0x3c00, 0.5: 0x3800, -2: 0xc000, 65504: 0x7bff, 1e6: 0x7c00,
+		1 + 1.0/1024: 0x3c01, 1 + 1.0/2048: 0x3c00, 1 + 3.0/2048: 0x3c02, 1.0 / (1 << 24): 0x0001, 1e-10: 0,
+		float32(math.Inf(-1)): 0xfc00,
+	} {
+		if h := halfFloat(f); h != half {
+			t.Errorf("halfFloat(%v) = %#04x, expecting %#04x", f, h, half)
+		}
+	}
+	if h := halfFloat(float32(math.NaN())); h&0x7c00 != 0x7c00 || h&0x3ff == 0 {
+		t.Errorf("Expecting a NaN, got %#04x", h)
+	}
+
+	text := &Text{vertexFormat: VFHalfFloatUNormUV}
+	packed := text.packVertices([]float32{-1, 2, 0.5, 1.5, 4, 8, 0, 1})
+	if !reflect.DeepEqual(packed, []uint16{0xbc00, 0x4000, 0x8000, 0xffff, 0x4400, 0x4800, 0, 0xffff}) {
+		t.Errorf("Bad packed vertices %#04x", packed)
+	}
+	text.vertexFormat = VFHalfFloat
+	if packed := text.packVertices([]float32{0, 0, 0.5, 1}); packed[2] != 0x3800 || packed[3] != 0x3c00 {
+		t.Errorf("Expecting half float texture coordinates, got %#04x", packed)
+	}
+	text.vboCapacity, text.eboCapacity = 16, 6
+	if m := text.MemoryUsage(); m.Buffers != 16*2+6*4 {
+		t.Error("Bad buffer memory", m.Buffers)
+	}
+}
+
+func TestDrawnVertices(t *testing.T) {
+	f := &Font{textureWidth: 8, textureHeight: 4}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+	text := &Text{Font: f, Scale: 1, ScaleMin: 1, ScaleMax: 1}
+	text.layOut([]rune("ab"))
+
+	moved := text.offsetVertices([]gltext.Point{{X: 1}, {Y: 2}})
+	if d := text.drawnVertices(); &d[0] != &moved[0] || d[0] != text.vboData[0]+1 || d[17] != text.vboData[17]+2 {
+		t.Fatal("Expecting the vertices moved by the offsets", d)
+	}
+	if text.offsetVertices(nil); !reflect.DeepEqual(text.drawnVertices(), text.vboData) {
+		t.Error("Expecting the laid out vertices once the glyphs are back in place")
+	}
+
+	// a new string is not moved by the offsets of the previous one
+	text.offsetVertices([]gltext.Point{{X: 1}})
+	text.layOut([]rune("abc"))
+	if d := text.drawnVertices(); len(d) != 3*16 || !reflect.DeepEqual(d, text.vboData) {
+		t.Error("Expecting the vertices of the new string", d)
+	}
+}
+
+func TestVertexColors(t *testing.T) {
+	f := &Font{pageTextureIDs: []uint32{5}, textureWidth: 8, textureHeight: 4, vertexColors: true}
+	f.Config = &gltext.FontConfig{}
+	f.Config.RuneRanges = gltext.RuneRanges{{Low: 'a', High: 'c'}}
+	f.Config.Glyphs = gltext.Charset{{Advance: 2, Height: 2}, {Advance: 2, Height: 2}, {Advance: 2, Height: 2}}
+	f.maxGlyphHeight = 2
+
+	texts := []*Text{}
+	for i, s := range []string{"ab", "c"} {
+		text := &Text{Font: f, Scale: 1, ScaleMin: 1, ScaleMax: 1, color: mgl32.Vec3{float32(i), 0, 0}}
+		if i == 0 {
+			text.styleRuns = gltext.StyleRuns{{Start: 1, End: 2, Color: image.White}}
+		}
+		text.layOut([]rune(s))
+		text.RuneCount = len(s)
+		// as upload does
+		text.vertexColored = text.interleavesColors()
+		texts = append(texts, text)
+	}
+	text := texts[0]
+	if len(text.pageRanges) != 1 || text.pageRanges[0].color != nil {
+		t.Fatal("Expecting colored runs to be drawn with the rest of the text", text.pageRanges)
+	}
+
+	// texts of a single color keep the color uniform
+	plain := texts[1]
+	if plain.vertexColored || plain.vertexValues() != 4 || len(plain.interleave(plain.vboData, 0)) != len(plain.vboData) {
+		t.Error("Expecting single colored texts not to interleave colors", plain.vertexValues())
+	}
+	plain.SetColor(mgl32.Vec3{0, 1, 0})
+	if plain.colorsChanged() {
+		t.Error("Expecting the color of single colored texts not to be sent with the vertices")
+	}
+	plain.SetColor(mgl32.Vec3{1, 0, 0})
+
+	interleaved := text.interleave(text.vboData, 0)
+	if len(interleaved) != 8*8 || !reflect.DeepEqual(interleaved[:8], append(text.vboData[:4:4], 0, 0, 0, 1)) {
+		t.Fatal("Expecting each vertex followed by its color", interleaved[:8])
+	}
+	if !reflect.DeepEqual(interleaved[4*8+4:4*8+8], []float32{1, 1, 1, 1}) {
+		t.Error("Expecting the color of the run", interleaved[4*8:4*8+8])
+	}
+	if c := text.interleave(text.vboData[16:], 4); !reflect.DeepEqual(c[4:8], []float32{1, 1, 1, 1}) {
+		t.Error("Expecting the colors of the quads following the first vertex", c[:8])
+	}
+	text.vertexFormat = VFHalfFloat
+	if packed := text.packVertices([]float32{0, 0, 0.5, 1, 1, 0, 0.5, 1}); packed[2] != 0x3800 || packed[4] != 0xffff || packed[5] != 0 || packed[6] != 0x8000 {
+		t.Errorf("Expecting normalized colors, got %#04x", packed)
+	}
+
+	if text.colorsChanged() {
+		t.Error("Expecting the colors of the vbo to be those of the text")
+	}
+	text.SetColor(mgl32.Vec3{0, 1, 0})
+	if !text.colorsChanged() {
+		t.Error("Expecting a new color to be interleaved again")
+	}
+
+	// the colors are interleaved again with the glyphs of removed effects back in place,
+	// moving the vertices as Update and SetEffects() do without uploading them
+	text.SetEffects(gltext.Wave{Amplitude: 2, Frequency: 1})
+	text.offsetVertices([]gltext.Point{{Y: 2}, {Y: -2}})
+	text.effects = nil
+	text.offsetVertices(nil)
+	text.SetColor(mgl32.Vec3{0, 0, 1})
+	if c := text.interleave(text.drawnVertices(), 0); c[1] != text.vboData[1] || len(c) != 8*8 {
+		t.Error("Expecting the laid out vertices to be sent with the new color", c[:8])
+	}
+
+	l := &CommandList{Font: f}
+	if err := l.record(texts); err != nil {
+		t.Fatal(err)
+	}
+	if l.Len() != 1 || l.commands[0].color != (mgl32.Vec4{1, 1, 1, 1}) {
+		t.Fatal("Expecting differently colored texts to be drawn at once", l.commands)
+	}
+	if len(l.vboData) != 3*4*8 || l.eboData[12] != 8+texts[1].eboData[0] || l.vboData[8*8+4] != 1 {
+		t.Error("Expecting the vertices and colors of the second text to follow those of the first", l.vboData[8*8:9*8], l.eboData)
+	}
+}
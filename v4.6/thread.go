@@ -0,0 +1,40 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+/*
+#include <pthread.h>
+#include <stdint.h>
+
+static uint64_t current_thread() { return (uint64_t)(uintptr_t)pthread_self(); }
+*/
+import "C"
+
+import (
+	"fmt"
+
+	"github.com/mikzorz/gltext"
+)
+
+// currentThread identifies the os thread running the calling goroutine
+func currentThread() uint64 {
+	return uint64(C.current_thread())
+}
+
+// claimThread records the thread owning the gl objects of the font when
+// gltext.ThreadChecks is set
+func (f *Font) claimThread() {
+	if gltext.ThreadChecks && f.thread == 0 {
+		f.thread = currentThread()
+	}
+}
+
+// checkThread panics when the font records its thread and call is made from another one
+func (f *Font) checkThread(call string) {
+	if f.thread != 0 && f.thread != currentThread() {
+		panic(fmt.Sprintf("gltext: %s called from another os thread than the one the font %q was created on.  "+
+			"gl calls have to be made on the thread owning the context, see runtime.LockOSThread.", call, fontName(f)))
+	}
+}
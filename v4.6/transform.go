@@ -0,0 +1,84 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// SetRotation rotates the text counter-clockwise by angle radians around its pivot.
+func (t *Text) SetRotation(angle float32) {
+	t.rotation = angle
+}
+
+// Rotation returns the angle given to SetRotation.
+func (t *Text) Rotation() float32 {
+	return t.rotation
+}
+
+// ModelMatrix transforms the centered vbo data of the text into the coordinates of
+// SetPosition, scaling and rotating it around the pivot.
+func (t *Text) ModelMatrix() mgl32.Mat4 {
+	c := t.center()
+	px := t.X1.X + t.pivot.X*(t.X2.X-t.X1.X)
+	py := t.X1.Y + t.pivot.Y*(t.X2.Y-t.X1.Y)
+	return mgl32.Translate3D(c.X()+px, c.Y()+py, 0).
+		Mul4(mgl32.HomogRotate3DZ(t.rotation)).
+		Mul4(mgl32.Scale3D(t.Scale, t.Scale, 1)).
+		Mul4(mgl32.Translate3D(-px, -py, 0))
+}
+
+// BoundingPolygon returns the corners of the bounding box of the text as drawn, after
+// scaling, rotation and anchoring, counter-clockwise from the lower left one in the
+// coordinates of SetPosition.  Under rotation it is tighter than the box holding it, which
+// makes it suited to collision and focus outlines.
+func (t *Text) BoundingPolygon() [4]gltext.Point {
+	return t.drawnQuad(t.X1, t.X2)
+}
+
+// GlyphPolygons returns the corners of the ink of every drawn glyph as BoundingPolygon does,
+// following the shape of the text more closely.  Glyphs without ink, such as spaces, are
+// left out.
+func (t *Text) GlyphPolygons() [][4]gltext.Point {
+	polygons := make([][4]gltext.Point, 0, len(t.glyphBounds))
+	for q := range t.glyphBounds {
+		if X1, X2 := t.inkBounds(q); X2.X > X1.X {
+			polygons = append(polygons, t.drawnQuad(X1, X2))
+		}
+	}
+	return polygons
+}
+
+// drawnQuad moves the corners of the box from X1 to X2 of the centered vbo data to where
+// they are drawn
+func (t *Text) drawnQuad(X1, X2 gltext.Point) [4]gltext.Point {
+	model := t.ModelMatrix()
+	quad := [4]gltext.Point{}
+	for i, corner := range [4]gltext.Point{X1, {X: X2.X, Y: X1.Y}, X2, {X: X1.X, Y: X2.Y}} {
+		v := model.Mul4x1(mgl32.Vec4{corner.X, corner.Y, 0, 1})
+		quad[i] = gltext.Point{X: v.X(), Y: v.Y()}
+	}
+	return quad
+}
+
+// drawnPoint moves a point of the centered vbo data to where it is drawn
+func (t *Text) drawnPoint(x, y float32) gltext.Point {
+	v := t.ModelMatrix().Mul4x1(mgl32.Vec4{x, y, 0, 1})
+	return gltext.Point{X: v.X(), Y: v.Y()}
+}
+
+// localPoint is the inverse of drawnPoint, moving p, given in the coordinates of
+// SetPosition, into those of the centered text.  Hit tests are done there so that
+// they hold under any transform.
+func (t *Text) localPoint(p mgl32.Vec2) gltext.Point {
+	v := t.ModelMatrix().Inv().Mul4x1(mgl32.Vec4{p.X(), p.Y(), 0, 1})
+	return gltext.Point{X: v.X(), Y: v.Y()}
+}
+
+// inside reports whether p lies within the box from X1 to X2
+func inside(p, X1, X2 gltext.Point) bool {
+	return p.X >= X1.X && p.X <= X2.X && p.Y >= X1.Y && p.Y <= X2.Y
+}
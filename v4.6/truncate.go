@@ -0,0 +1,53 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"fmt"
+)
+
+// TruncateMode determines what SetString does with strings longer than MaxRuneCount
+type TruncateMode int
+
+const (
+	TruncateCut      TruncateMode = iota // the runes past MaxRuneCount are dropped
+	TruncateEllipsis                     // the string is cut so that an ellipsis ends it within MaxRuneCount runes
+	TruncateReject                       // the string is not set, SetStringChecked returning an error
+)
+
+// truncate applies the truncate mode of the text to runes longer than MaxRuneCount,
+// calling OnTruncate with the number of runes dropped, or with the number of runes over
+// MaxRuneCount when the string is rejected
+func (t *Text) truncate(runes []rune) ([]rune, error) {
+	if t.MaxRuneCount <= 0 || len(runes) <= t.MaxRuneCount {
+		return runes, nil
+	}
+	dropped := len(runes) - t.MaxRuneCount
+	switch t.Truncate {
+	case TruncateReject:
+		if t.OnTruncate != nil {
+			t.OnTruncate(dropped)
+		}
+		return nil, fmt.Errorf("string of %d runes is longer than the %d allowed", len(runes), t.MaxRuneCount)
+	case TruncateEllipsis:
+		ellipsis := []rune("…")
+		if t.Font.Config != nil && !t.HasRune(ellipsis[0]) {
+			ellipsis = []rune("...")
+		}
+		if t.MaxRuneCount > len(ellipsis) {
+			kept := t.MaxRuneCount - len(ellipsis)
+			dropped = len(runes) - kept
+			runes = append(runes[:kept:kept], ellipsis...)
+			break
+		}
+		runes = runes[:t.MaxRuneCount]
+	default:
+		runes = runes[:t.MaxRuneCount]
+	}
+	if t.OnTruncate != nil {
+		t.OnTruncate(dropped)
+	}
+	return runes, nil
+}
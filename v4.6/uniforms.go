@@ -0,0 +1,126 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SetUniform stores a value for a uniform of a custom shader (see NewFontWithShaders).
+// The value is applied in Draw after the built-in uniforms.  As the program is shared by the
+// texts of the font, the uniform is restored to the value the program had before once the
+// text is drawn.  Supported types are float32, int32, int, mgl32.Vec2, mgl32.Vec3,
+// mgl32.Vec4 and mgl32.Mat4.
+// Passing nil removes the uniform.
+func (t *Text) SetUniform(name string, value interface{}) error {
+	if value == nil {
+		delete(t.uniforms, name)
+		return nil
+	}
+	switch v := value.(type) {
+	case float32, int32, mgl32.Vec2, mgl32.Vec3, mgl32.Vec4, mgl32.Mat4:
+	case int:
+		value = int32(v)
+	default:
+		return fmt.Errorf("unsupported uniform type %T for %s", value, name)
+	}
+	if t.uniforms == nil {
+		t.uniforms = make(map[string]interface{})
+	}
+	t.uniforms[name] = value
+	return nil
+}
+
+// applyUniforms is called by Draw once the program is in use
+func (t *Text) applyUniforms() {
+	for name, value := range t.uniforms {
+		if location := t.Font.uniformLocation(name); location >= 0 {
+			t.Font.keepUniformDefault(name, location, value)
+			setUniform(location, value)
+		}
+	}
+}
+
+// resetUniforms restores the uniforms set by applyUniforms once the text is drawn, as the
+// program is shared by the texts of the font which would otherwise be drawn with them
+func (t *Text) resetUniforms() {
+	for name := range t.uniforms {
+		if location := t.Font.uniformLocation(name); location >= 0 {
+			setUniform(location, t.Font.uniformDefaults[name])
+		}
+	}
+}
+
+// keepUniformDefault reads the value the program has for a uniform the first time a text
+// sets it, or sets it with another type
+func (f *Font) keepUniformDefault(name string, location int32, value interface{}) {
+	if d, ok := f.uniformDefaults[name]; ok && reflect.TypeOf(d) == reflect.TypeOf(value) {
+		return
+	}
+	if f.uniformDefaults == nil {
+		f.uniformDefaults = make(map[string]interface{})
+	}
+	switch value.(type) {
+	case float32:
+		var v float32
+		gl.GetUniformfv(f.program, location, &v)
+		f.uniformDefaults[name] = v
+	case int32:
+		var v int32
+		gl.GetUniformiv(f.program, location, &v)
+		f.uniformDefaults[name] = v
+	case mgl32.Vec2:
+		var v mgl32.Vec2
+		gl.GetUniformfv(f.program, location, &v[0])
+		f.uniformDefaults[name] = v
+	case mgl32.Vec3:
+		var v mgl32.Vec3
+		gl.GetUniformfv(f.program, location, &v[0])
+		f.uniformDefaults[name] = v
+	case mgl32.Vec4:
+		var v mgl32.Vec4
+		gl.GetUniformfv(f.program, location, &v[0])
+		f.uniformDefaults[name] = v
+	case mgl32.Mat4:
+		var v mgl32.Mat4
+		gl.GetUniformfv(f.program, location, &v[0])
+		f.uniformDefaults[name] = v
+	}
+}
+
+// setUniform sets a uniform of the program in use to a value stored by SetUniform
+func setUniform(location int32, value interface{}) {
+	switch v := value.(type) {
+	case float32:
+		gl.Uniform1f(location, v)
+	case int32:
+		gl.Uniform1i(location, v)
+	case mgl32.Vec2:
+		gl.Uniform2fv(location, 1, &v[0])
+	case mgl32.Vec3:
+		gl.Uniform3fv(location, 1, &v[0])
+	case mgl32.Vec4:
+		gl.Uniform4fv(location, 1, &v[0])
+	case mgl32.Mat4:
+		gl.UniformMatrix4fv(location, 1, false, &v[0])
+	}
+}
+
+// uniformLocation looks up and caches the location of a uniform in the font's program
+func (f *Font) uniformLocation(name string) int32 {
+	if location, ok := f.uniformLocations[name]; ok {
+		return location
+	}
+	if f.uniformLocations == nil {
+		f.uniformLocations = make(map[string]int32)
+	}
+	location := gl.GetUniformLocation(f.program, gl.Str(name+"\x00"))
+	f.uniformLocations[name] = location
+	return location
+}
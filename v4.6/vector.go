@@ -0,0 +1,159 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+var vectorVertexShaderSource string = `
+#version 330
+
+uniform mat4 orthographic_matrix;
+uniform mat4 model_matrix;
+
+in vec2 position;
+
+void main() {
+  gl_Position = orthographic_matrix * model_matrix * vec4(position, 0, 1);
+}
+` + "\x00"
+
+var vectorFragmentShaderSource string = `
+#version 330
+
+uniform vec4 color;
+out vec4 fragment_color;
+
+void main() {
+  fragment_color = color;
+}
+` + "\x00"
+
+// VectorText draws text as the triangulated outlines of its glyphs instead of quads
+// sampling an atlas, so that it stays sharp at any size.  It suits large display text;
+// edges are only antialiased when the framebuffer is multisampled.
+type VectorText struct {
+	Font *gltext.OutlineFont
+
+	String string
+	Color  mgl32.Vec4
+
+	// Position is the screen position of the center of the text, in pixels from the
+	// center of the window.  Scale multiplies the size the outline font was loaded at.
+	Position mgl32.Vec2
+	Scale    float32
+
+	// the bounding box of the laid out text, centered on (0,0)
+	X1 gltext.Point
+	X2 gltext.Point
+
+	OrthographicMatrix mgl32.Mat4
+
+	program        uint32
+	vao, vbo       uint32
+	vertices       []float32 // x, y per vertex, three vertices per triangle
+	dirty          bool
+	colorUniform   int32
+	modelUniform   int32
+	orthoUniform   int32
+	positionAttrib uint32
+}
+
+// NewVectorText creates an empty vector text drawn in a window of the given size
+func NewVectorText(f *gltext.OutlineFont, windowWidth, windowHeight float32) (*VectorText, error) {
+	program, err := NewProgram(vectorVertexShaderSource, vectorFragmentShaderSource)
+	if err != nil {
+		return nil, err
+	}
+	v := &VectorText{Font: f, Color: mgl32.Vec4{1, 1, 1, 1}, Scale: 1, program: program}
+	v.ResizeWindow(windowWidth, windowHeight)
+	v.colorUniform = gl.GetUniformLocation(program, gl.Str("color\x00"))
+	v.modelUniform = gl.GetUniformLocation(program, gl.Str("model_matrix\x00"))
+	v.orthoUniform = gl.GetUniformLocation(program, gl.Str("orthographic_matrix\x00"))
+	v.positionAttrib = uint32(gl.GetAttribLocation(program, gl.Str("position\x00")))
+
+	gl.GenVertexArrays(1, &v.vao)
+	gl.GenBuffers(1, &v.vbo)
+	gl.BindVertexArray(v.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, v.vbo)
+	gl.EnableVertexAttribArray(v.positionAttrib)
+	gl.VertexAttribPointer(v.positionAttrib, 2, gl.FLOAT, false, 2*4, gl.PtrOffset(0))
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	return v, nil
+}
+
+// ResizeWindow updates the projection after the window changed size
+func (v *VectorText) ResizeWindow(width, height float32) {
+	v.OrthographicMatrix = mgl32.Ortho2D(-width/2, width/2, -height/2, height/2)
+}
+
+// SetString triangulates the outlines of s.  Nothing is done when s did not change.
+func (v *VectorText) SetString(s string) {
+	if s == v.String && v.vertices != nil {
+		return
+	}
+	v.String = s
+	v.vertices, v.X1, v.X2 = vectorVertices(v.Font.Outlines(s))
+	v.dirty = true
+}
+
+// vectorVertices triangulates the outlines and centers the triangles on (0,0)
+func vectorVertices(outlines []gltext.GlyphOutline) (vertices []float32, x1, x2 gltext.Point) {
+	points := []gltext.Point{}
+	for _, outline := range outlines {
+		for _, triangle := range gltext.Triangulate(outline.Contours) {
+			points = append(points, triangle[:]...)
+		}
+	}
+	vertices = make([]float32, 0, 2*len(points))
+	if len(points) == 0 {
+		return vertices, x1, x2
+	}
+	x1, x2 = bounds(points...)
+	center := gltext.Point{X: (x1.X + x2.X) / 2, Y: (x1.Y + x2.Y) / 2}
+	for _, p := range points {
+		vertices = append(vertices, p.X-center.X, p.Y-center.Y)
+	}
+	x1 = gltext.Point{X: x1.X - center.X, Y: x1.Y - center.Y}
+	x2 = gltext.Point{X: x2.X - center.X, Y: x2.Y - center.Y}
+	return vertices, x1, x2
+}
+
+// Draw draws the filled glyphs, uploading them first when the string changed
+func (v *VectorText) Draw() {
+	if len(v.vertices) == 0 {
+		return
+	}
+	if v.dirty {
+		gl.BindBuffer(gl.ARRAY_BUFFER, v.vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, 4*len(v.vertices), gl.Ptr(v.vertices), gl.STATIC_DRAW)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		v.dirty = false
+	}
+	model := mgl32.Translate3D(v.Position.X(), v.Position.Y(), 0).Mul4(mgl32.Scale3D(v.Scale, v.Scale, 1))
+
+	gl.UseProgram(v.program)
+	gl.UniformMatrix4fv(v.orthoUniform, 1, false, &v.OrthographicMatrix[0])
+	gl.UniformMatrix4fv(v.modelUniform, 1, false, &model[0])
+	gl.Uniform4fv(v.colorUniform, 1, &v.Color[0])
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(v.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(v.vertices)/2))
+	gl.BindVertexArray(0)
+	gl.Disable(gl.BLEND)
+}
+
+// Release releases the program and buffers of the text
+func (v *VectorText) Release() {
+	gl.DeleteBuffers(1, &v.vbo)
+	gl.DeleteVertexArrays(1, &v.vao)
+	gl.DeleteProgram(v.program)
+}
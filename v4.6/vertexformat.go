@@ -0,0 +1,293 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mikzorz/gltext"
+)
+
+// VertexFormat is the type the positions and texture coordinates of a text are sent to
+// the gpu as.  The 16 bit formats halve the size of the vbo and the bandwidth of uploads.
+// Colors interleaved with the vertices are stored as normalized shorts in those formats.
+//
+// Half floats are exact for whole pixels up to 2048 pixels from the center of the text,
+// positions further than 512 pixels being rounded to half pixels and further than 1024
+// to whole pixels.  Half float texture coordinates are rounded to 1/2048 of the atlas
+// near its right and bottom edges, which shows on atlases wider than 1024 texels; the
+// normalized shorts of VFHalfFloatUNormUV keep them exact.
+type VertexFormat int
+
+const (
+	VFFloat32          VertexFormat = iota // 32 bit floats, the default
+	VFHalfFloat                            // 16 bit floats
+	VFHalfFloatUNormUV                     // 16 bit float positions and 16 bit normalized texture coordinates
+)
+
+// valueSize returns the bytes taken by a value of the vbo in the format
+func (vf VertexFormat) valueSize() int {
+	if vf == VFFloat32 {
+		return 4
+	}
+	return 2
+}
+
+// SetVertexFormat stores the vertices of the text in format, sending them to the gpu again.
+// The vertices kept on the cpu remain 32 bit floats.
+func (t *Text) SetVertexFormat(format VertexFormat) {
+	if format == t.vertexFormat {
+		return
+	}
+	t.vertexFormat = format
+	gl.BindVertexArray(t.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	t.setVertexPointers()
+	t.vboCapacity = 0
+	if t.eboIndexCount > 0 {
+		vbo := t.drawnVertices()
+		usage := uint32(gl.DYNAMIC_DRAW)
+		if t.static {
+			usage = gl.STATIC_DRAW
+		}
+		t.vertexBufferData(vbo, usage)
+		t.vboCapacity = len(vbo)
+	}
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	if format == VFFloat32 {
+		t.packedData = nil
+	}
+}
+
+// VertexFormat returns the format the vertices of the text are stored in
+func (t *Text) VertexFormat() VertexFormat {
+	return t.vertexFormat
+}
+
+// vertexValues returns the number of values of a vertex of the vbo: a position and texture
+// coordinates, followed by a color when the vbo interleaves them
+func (t *Text) vertexValues() int {
+	if t.vertexColored {
+		return 8
+	}
+	return 4
+}
+
+// interleavesColors reports whether the vbo should hold the color of each vertex, which is
+// the case when the program of the font reads them and style runs color some of the glyphs
+// so that they are drawn with the rest of the text.  Texts of a single color keep 4 values
+// per vertex and are drawn with the color uniform.
+func (t *Text) interleavesColors() bool {
+	if t.Font == nil || !t.Font.vertexColors {
+		return false
+	}
+	for _, run := range t.styleRuns {
+		if run.Color != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// setVertexPointers describes the vertices of the vbo bound to GL_ARRAY_BUFFER to the
+// bound vertex array: a position followed by texture coordinates and possibly a color
+func (t *Text) setVertexPointers() {
+	size := t.vertexFormat.valueSize()
+	stride := int32(t.vertexValues() * size)
+	position, uv, normalized := uint32(gl.FLOAT), uint32(gl.FLOAT), false
+	switch t.vertexFormat {
+	case VFHalfFloat:
+		position, uv = gl.HALF_FLOAT, gl.HALF_FLOAT
+	case VFHalfFloatUNormUV:
+		position, uv, normalized = gl.HALF_FLOAT, gl.UNSIGNED_SHORT, true
+	}
+	gl.EnableVertexAttribArray(t.Font.centeredPositionAttribute)
+	gl.VertexAttribPointer(t.Font.centeredPositionAttribute, 2, position, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(t.Font.uvAttribute)
+	gl.VertexAttribPointer(t.Font.uvAttribute, 2, uv, normalized, stride, gl.PtrOffset(2*size))
+	if t.vertexColored {
+		color := uint32(gl.FLOAT)
+		if t.vertexFormat != VFFloat32 {
+			color = gl.UNSIGNED_SHORT
+		}
+		gl.EnableVertexAttribArray(t.Font.colorAttribute)
+		gl.VertexAttribPointer(t.Font.colorAttribute, 4, color, color != gl.FLOAT, stride, gl.PtrOffset(4*size))
+	} else if t.Font.vertexColors {
+		// the shader reads the constant value set by draw instead
+		gl.DisableVertexAttribArray(t.Font.colorAttribute)
+	}
+}
+
+// vertexBufferData allocates the vbo bound to GL_ARRAY_BUFFER, filling it with data
+func (t *Text) vertexBufferData(data []float32, usage uint32) {
+	t.vertexColor, t.vertexSRGB, t.vertexOrigin = t.color, t.Font.SRGBFramebuffer, t.origin
+	data = t.interleave(data, 0)
+	if t.vertexFormat == VFFloat32 {
+		gl.BufferData(gl.ARRAY_BUFFER, 4*len(data), gl.Ptr(data), usage)
+		return
+	}
+	packed := t.packVertices(data)
+	gl.BufferData(gl.ARRAY_BUFFER, 2*len(packed), gl.Ptr(packed), usage)
+}
+
+// vertexBufferSubData replaces the values of the vbo bound to GL_ARRAY_BUFFER from the
+// value at start with those of data.  Every vertex is sent again when the color of the
+// text, or the origin of vertices sent centered, changed since the vbo was interleaved.
+func (t *Text) vertexBufferSubData(data []float32, start int) {
+	start = t.subDataStart(start)
+	t.vertexColor, t.vertexSRGB, t.vertexOrigin = t.color, t.Font.SRGBFramebuffer, t.origin
+	vertices := t.interleave(data[start:], start/4)
+	offset := start / 4 * t.vertexValues() * t.vertexFormat.valueSize()
+	if t.vertexFormat == VFFloat32 {
+		gl.BufferSubData(gl.ARRAY_BUFFER, offset, 4*len(vertices), gl.Ptr(vertices))
+		return
+	}
+	packed := t.packVertices(vertices)
+	gl.BufferSubData(gl.ARRAY_BUFFER, offset, 2*len(packed), gl.Ptr(packed))
+}
+
+// subDataStart returns the first value of the vbo sent by vertexBufferSubData when the
+// values from start changed
+func (t *Text) subDataStart(start int) int {
+	if t.colorsChanged() || t.originChanged() {
+		return 0
+	}
+	// interleaving and packing depend on the position of a value within its vertex
+	return start - start%4
+}
+
+// originChanged reports whether the vertices were sent centered around another origin
+func (t *Text) originChanged() bool {
+	return t.Font.originUniform < 0 && t.vertexOrigin != t.origin
+}
+
+// colorsChanged reports whether the vbo was interleaved with another color of the text or
+// for another framebuffer encoding
+func (t *Text) colorsChanged() bool {
+	return t.vertexColored && (t.vertexColor != t.color || t.vertexSRGB != t.Font.SRGBFramebuffer)
+}
+
+// uploadColors sends the vertices, interleaved with the current color of the text, to the gpu
+func (t *Text) uploadColors() {
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	t.vertexBufferSubData(t.drawnVertices(), 0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// interleave returns whole vertices of data, the first being vertex first of the text,
+// each followed by the color of its quad when the vbo interleaves colors.  Programs
+// without a glyph_origin uniform are sent the vertices centered.  The memory of the
+// previous interleaving is reused.
+func (t *Text) interleave(data []float32, first int) []float32 {
+	var origin gltext.Point
+	if t.Font.originUniform < 0 {
+		origin = t.origin
+	}
+	if !t.vertexColored && origin == (gltext.Point{}) {
+		return data
+	}
+	values := t.vertexValues()
+	vertices := len(data) / 4
+	if cap(t.interleavedData) < vertices*values {
+		t.interleavedData = make([]float32, vertices*values)
+	}
+	interleaved := t.interleavedData[:vertices*values]
+	quad, color := -1, mgl32.Vec4{}
+	for v := 0; v < vertices; v++ {
+		vertex := interleaved[v*values : v*values+values]
+		copy(vertex, data[v*4:v*4+4])
+		vertex[0] += origin.X
+		vertex[1] += origin.Y
+		if !t.vertexColored {
+			continue
+		}
+		if q := (first + v) / 4; q != quad {
+			quad, color = q, t.quadColor(q)
+		}
+		copy(vertex[4:], color[:])
+	}
+	return interleaved
+}
+
+// quadColor returns the color sent to the shader for quad q of the vbo, that of the style
+// run of its glyph or else the color of the text.  Transparency is left to the uniform.
+func (t *Text) quadColor(q int) mgl32.Vec4 {
+	c := t.color
+	if q < len(t.glyphs) {
+		if run := t.styleRuns.ColorAt(t.glyphs[q].Index); run != nil {
+			c = colorVec3(run)
+		}
+	}
+	return t.Font.outputColor(c, 1)
+}
+
+// packVertices converts whole vertices to the 16 bit values of the vertex format, reusing
+// the memory of the previous conversion
+func (t *Text) packVertices(data []float32) []uint16 {
+	if cap(t.packedData) < len(data) {
+		t.packedData = make([]uint16, len(data))
+	}
+	packed := t.packedData[:len(data)]
+	values := t.vertexValues()
+	for i, v := range data {
+		switch c := i % values; {
+		case c >= 4, c >= 2 && t.vertexFormat == VFHalfFloatUNormUV:
+			// colors and normalized texture coordinates
+			packed[i] = unormShort(v)
+		default:
+			packed[i] = halfFloat(v)
+		}
+	}
+	return packed
+}
+
+// halfFloat returns the IEEE 754 half precision bits of f, rounded to the nearest even value
+func halfFloat(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exponent := int32(bits>>23&0xff) - 127 + 15
+	mantissa := bits & 0x7fffff
+	switch {
+	case bits&0x7fffffff > 0x7f800000:
+		// not a number
+		return sign | 0x7e00
+	case exponent >= 0x1f:
+		// infinity and values too large
+		return sign | 0x7c00
+	case exponent <= 0:
+		if exponent < -10 {
+			// too small, even for a subnormal value
+			return sign
+		}
+		mantissa |= 0x800000
+		shift := uint32(14 - exponent)
+		half := mantissa >> shift
+		rest, halfway := mantissa&(1<<shift-1), uint32(1)<<(shift-1)
+		if rest > halfway || rest == halfway && half&1 == 1 {
+			half++
+		}
+		return sign | uint16(half)
+	}
+	// rounding up may carry into the exponent, which is the correct result
+	half := uint32(exponent)<<10 | mantissa>>13
+	if rest := mantissa & 0x1fff; rest > 0x1000 || rest == 0x1000 && half&1 == 1 {
+		half++
+	}
+	return sign | uint16(half)
+}
+
+// unormShort returns v, clamped to [0, 1], as a normalized unsigned short
+func unormShort(v float32) uint16 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return math.MaxUint16
+	}
+	return uint16(v*math.MaxUint16 + 0.5)
+}
@@ -0,0 +1,42 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v46
+
+import (
+	"github.com/go-gl/gl/v4.6-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Viewport is an area of the framebuffer texts are drawn in, such as a split screen
+// panel, along with its projection.
+type Viewport struct {
+	// the area of the framebuffer in pixels from its lower left corner
+	X, Y, Width, Height int32
+
+	// Projection maps pixels, relative to the center of the viewport, to clip space.
+	// It may pan or zoom the view of the texts.
+	Projection mgl32.Mat4
+}
+
+// NewViewport creates a viewport projecting pixels like Font.ResizeWindow does for the
+// whole window.
+func NewViewport(x, y, width, height int32) Viewport {
+	w, h := float32(width), float32(height)
+	return Viewport{X: x, Y: y, Width: width, Height: height, Projection: mgl32.Ortho2D(-w/2, w/2, -h/2, h/2)}
+}
+
+// DrawViewport draws the text in v instead of the window of its font.  The position of
+// the text is relative to the center of the viewport.  The gl viewport is left set to v.
+func (t *Text) DrawViewport(v Viewport) {
+	gl.Viewport(v.X, v.Y, v.Width, v.Height)
+	t.draw(v.Projection, v.finalPosition(t.center()))
+}
+
+// finalPosition projects the center of a text without translating it, as the projection
+// is applied to the vertices as well
+func (v Viewport) finalPosition(center mgl32.Vec2) mgl32.Vec2 {
+	p := v.Projection.Mul4x1(mgl32.Vec4{center.X(), center.Y(), 0, 0})
+	return mgl32.Vec2{p.X(), p.Y()}
+}